@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+// GetAddressFees godoc
+// @Summary Get the fees paid by an address
+// @Description Sum up the fees of the transactions an address sent to the tracked pools, optionally over a time window.
+// @Description With transactions=true a page of those transactions is returned as well, paginated like the search endpoint.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param address path string true "Sender address"
+// @Param from_time query string false "Earliest transaction time (RFC3339)"
+// @Param to_time query string false "Latest transaction time (RFC3339)"
+// @Param window query string false "Trailing window such as 24h or 720h, instead of from_time"
+// @Param transactions query bool false "Also return a page of the address's transactions"
+// @Param sort_by query string false "Sort field of the transactions" Enums(block_number, timestamp, fee_usdt) default(block_number)
+// @Param order query string false "Sort order of the transactions" Enums(asc, desc) default(desc)
+// @Param limit query int false "Page size (max 1000)" default(50)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {object} models.AddressFeesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/addresses/{address}/fees [get]
+func (h *TransactionHandler) GetAddressFees(c *gin.Context) {
+	var query models.AddressFeesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	filter := syncer.AddressFeeFilter{
+		Address:  c.Param("address"),
+		FromTime: query.FromTime,
+		ToTime:   query.ToTime,
+	}
+	if query.Window != "" {
+		window, err := time.ParseDuration(query.Window)
+		if err != nil || window <= 0 || query.FromTime != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "window must be a positive duration such as 24h and cannot be combined with from_time",
+			})
+			return
+		}
+		fromTime := time.Now().Add(-window)
+		filter.FromTime = &fromTime
+	}
+
+	summary, err := h.syncService.AddressFeeSummary(filter)
+	if err != nil {
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to summarize address fees",
+		})
+		return
+	}
+
+	response := models.AddressFeesResponse{
+		Address:          strings.ToLower(filter.Address),
+		FromTime:         filter.FromTime,
+		ToTime:           filter.ToTime,
+		TransactionCount: summary.TransactionCount,
+		PricedCount:      summary.PricedCount,
+		RevertedCount:    summary.RevertedCount,
+		TotalFeeETH:      formatBigFloat(summary.TotalFeeETH, 18),
+		TotalFeeUSDT:     formatBigFloat(summary.TotalFeeUSDT, 6),
+		AverageFeeETH:    formatBigFloat(summary.AverageFeeETH, 18),
+		AverageFeeUSDT:   formatBigFloat(summary.AverageFeeUSDT, 6),
+		FirstSeen:        summary.FirstSeen,
+		LastSeen:         summary.LastSeen,
+	}
+	if summary.TransactionCount == 0 {
+		response.AverageFeeETH = ""
+	}
+	if summary.PricedCount == 0 {
+		response.AverageFeeUSDT = ""
+	}
+
+	if query.Transactions {
+		page, err := h.syncService.ListTransactions(syncer.TransactionFilter{
+			FromTime:    filter.FromTime,
+			ToTime:      filter.ToTime,
+			FromAddress: strings.ToLower(filter.Address),
+			SortBy:      syncer.SortField(query.SortBy),
+			Order:       syncer.SortOrder(query.Order),
+			Limit:       query.Limit,
+			Cursor:      query.Cursor,
+		})
+		if err != nil {
+			if errors.Is(err, syncer.ErrInvalidFilter) {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error: err.Error(),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to list transactions",
+			})
+			return
+		}
+		response.Transactions = make([]models.TransactionResponse, 0, len(page.Transactions))
+		for _, tx := range page.Transactions {
+			response.Transactions = append(response.Transactions, h.toTransactionResponse(tx))
+		}
+		response.NextCursor = page.NextCursor
+	}
+	c.JSON(http.StatusOK, response)
+}
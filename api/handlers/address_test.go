@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addressRepository records the filters it is queried with and returns a fixed summary and page
+type addressRepository struct {
+	fakeRepository
+	summary       *syncer.AddressFeeSummary
+	summaryFilter syncer.AddressFeeFilter
+	listFilter    *syncer.TransactionFilter
+}
+
+func (r *addressRepository) GetAddressFeeSummary(filter syncer.AddressFeeFilter) (*syncer.AddressFeeSummary, error) {
+	r.summaryFilter = filter
+	return r.summary, nil
+}
+
+func (r *addressRepository) ListTransactions(filter syncer.TransactionFilter) (*syncer.TransactionPage, error) {
+	r.listFilter = &filter
+	var txs []*syncer.Transaction
+	for _, tx := range r.txs {
+		txs = append(txs, tx)
+	}
+	return &syncer.TransactionPage{Transactions: txs}, nil
+}
+
+func TestGetAddressFees(t *testing.T) {
+	const address = "0x5F6AB5E2AB6A2F4D3C6A1B2B9B5C1E7C7F3E9A01"
+	firstSeen := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	lastSeen := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	tx := &syncer.Transaction{
+		TxHash:   "0xaaa",
+		From:     "0x5f6ab5e2ab6a2f4d3c6a1b2b9b5c1e7c7f3e9a01",
+		GasUsed:  syncer.NewBigInt(big.NewInt(21000)),
+		GasPrice: syncer.NewBigInt(big.NewInt(1e9)),
+	}
+	tx.UpdatePrices(big.NewFloat(2000))
+	repo := &addressRepository{
+		fakeRepository: fakeRepository{txs: map[string]*syncer.Transaction{tx.TxHash: tx}},
+		summary: &syncer.AddressFeeSummary{
+			TransactionCount: 3,
+			PricedCount:      2,
+			RevertedCount:    1,
+			TotalFeeETH:      syncer.NewBigFloat(big.NewFloat(0.003)),
+			TotalFeeUSDT:     syncer.NewBigFloat(big.NewFloat(4)),
+			AverageFeeETH:    syncer.NewBigFloat(big.NewFloat(0.001)),
+			AverageFeeUSDT:   syncer.NewBigFloat(big.NewFloat(2)),
+			FirstSeen:        &firstSeen,
+			LastSeen:         &lastSeen,
+		},
+	}
+	handler := NewTransactionHandler(syncer.NewService(&config.Config{}, nil, nil, nil, repo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/addresses/:address/fees", handler.GetAddressFees)
+
+	get := func(query string) (int, models.AddressFeesResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/addresses/"+address+"/fees"+query, nil)
+		router.ServeHTTP(w, req)
+		var response models.AddressFeesResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, tx.From, response.Address)
+	assert.Equal(t, tx.From, repo.summaryFilter.Address)
+	assert.Nil(t, repo.summaryFilter.FromTime)
+	assert.Equal(t, int64(3), response.TransactionCount)
+	assert.Equal(t, int64(2), response.PricedCount)
+	assert.Equal(t, int64(1), response.RevertedCount)
+	assert.Equal(t, "0.003000000000000000", response.TotalFeeETH)
+	assert.Equal(t, "4.000000", response.TotalFeeUSDT)
+	assert.Equal(t, "2.000000", response.AverageFeeUSDT)
+	assert.Equal(t, firstSeen, *response.FirstSeen)
+	assert.Equal(t, lastSeen, *response.LastSeen)
+	assert.Empty(t, response.Transactions)
+	assert.Nil(t, repo.listFilter)
+
+	code, response = get("?window=24h&transactions=true&limit=10")
+	assert.Equal(t, http.StatusOK, code)
+	require.NotNil(t, repo.summaryFilter.FromTime)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), *repo.summaryFilter.FromTime, time.Minute)
+	require.NotNil(t, repo.listFilter)
+	assert.Equal(t, tx.From, repo.listFilter.FromAddress)
+	assert.Equal(t, 10, repo.listFilter.Limit)
+	require.Len(t, response.Transactions, 1)
+	assert.Equal(t, "0.042000", response.Transactions[0].FeeUSDT)
+
+	for _, query := range []string{"?window=soon", "?window=-1h", "?window=1h&from_time=2024-02-01T00:00:00Z",
+		"?from_time=2024-02-13T00:00:00Z&to_time=2024-02-01T00:00:00Z", "?transactions=true&sort_by=gas"} {
+		code, _ = get(query)
+		assert.Equal(t, http.StatusBadRequest, code, "query %s", query)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/addresses/0x1234/fees", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"math/big"
+	"net/http"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+type AdminHandler struct {
+	syncService *syncer.Service
+}
+
+func NewAdminHandler(syncService *syncer.Service) *AdminHandler {
+	return &AdminHandler{
+		syncService: syncService,
+	}
+}
+
+// ReconcilePrices godoc
+// @Summary Reprice failed transactions
+// @Description Refetch ETH prices for FAILED and PENDING_PRICE transactions in a block range.
+// @Description Transactions still backing off after a failed attempt are skipped unless force is set.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param request body models.ReconcileRequest true "Block range"
+// @Success 200 {object} models.ReconcileResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reconcile [post]
+func (h *AdminHandler) ReconcilePrices(c *gin.Context) {
+	var request models.ReconcileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if *request.FromBlock > *request.ToBlock {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "from_block is greater than to_block",
+		})
+		return
+	}
+
+	result, err := h.syncService.ReconcilePrices(c.Request.Context(), request.FromBlock, request.ToBlock, request.Force)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to reconcile prices",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReconcileResponse{
+		Checked:  result.Checked,
+		Repriced: result.Repriced,
+		Failed:   result.Failed,
+	})
+}
+
+// Reprice godoc
+// @Summary Reprice stored transactions
+// @Description Recompute the ETH price and USDT fee of every stored transaction in a block or time range
+// @Description with the given price providers. With dry_run the changes are reported without being written.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param request body models.RepriceRequest true "Range and price providers"
+// @Success 200 {object} models.RepriceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/reprice [post]
+func (h *AdminHandler) Reprice(c *gin.Context) {
+	var request models.RepriceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	report, err := h.syncService.Reprice(c.Request.Context(), syncer.RepriceOptions{
+		FromBlock: request.FromBlock,
+		ToBlock:   request.ToBlock,
+		FromTime:  request.FromTime,
+		ToTime:    request.ToTime,
+		Providers: request.Providers,
+		DryRun:    request.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to reprice transactions",
+		})
+		return
+	}
+
+	response := models.RepriceResponse{
+		DryRun:    report.DryRun,
+		Checked:   report.Checked,
+		Changed:   report.Changed,
+		Unchanged: report.Unchanged,
+		Failed:    report.Failed,
+		Changes:   make([]models.PriceChangeResponse, 0, len(report.Changes)),
+	}
+	for _, change := range report.Changes {
+		response.Changes = append(response.Changes, models.PriceChangeResponse{
+			TxHash:         change.TxHash,
+			BlockNumber:    change.BlockNumber,
+			OldETHPrice:    formatAmount(change.OldETHPrice),
+			NewETHPrice:    formatAmount(change.NewETHPrice),
+			OldFeeUSDT:     formatAmount(change.OldFeeUSDT),
+			NewFeeUSDT:     formatAmount(change.NewFeeUSDT),
+			OldPriceSource: change.OldSource,
+			NewPriceSource: change.NewSource,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// formatAmount returns a price or fee with the 6 decimals it is stored with, or an empty string when unset
+func formatAmount(v *big.Float) string {
+	if v == nil {
+		return ""
+	}
+	return v.Text('f', 6)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/price"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func (r *fakeRepository) GetUnpricedTransactions(syncer.UnpricedFilter) ([]*syncer.Transaction, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) ListTransactions(syncer.TransactionFilter) (*syncer.TransactionPage, error) {
+	return &syncer.TransactionPage{}, nil
+}
+
+// fixedPriceProvider prices ETH at 2000 USDT
+type fixedPriceProvider struct{}
+
+func (fixedPriceProvider) Name() string {
+	return price.SourceBinance
+}
+
+func (fixedPriceProvider) GetPrice(_ context.Context, req price.Request) (*price.Quote, error) {
+	return &price.Quote{Pair: req.Pair, Price: big.NewFloat(2000), Timestamp: req.Timestamp, Source: price.SourceBinance}, nil
+}
+
+func setupAdminRouter() *gin.Engine {
+	repo := &fakeRepository{txs: make(map[string]*syncer.Transaction)}
+	handler := NewAdminHandler(syncer.NewService(&config.Config{}, nil, fixedPriceProvider{}, nil, repo))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/admin/reconcile", handler.ReconcilePrices)
+	r.POST("/admin/reprice", handler.Reprice)
+	return r
+}
+
+func TestReconcilePrices(t *testing.T) {
+	router := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/admin/reconcile", bytes.NewBufferString(`{"from_block": 100, "to_block": 200}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.ReconcileResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.ReconcileResponse{}, response)
+}
+
+func TestReconcilePrices_InvalidBody(t *testing.T) {
+	router := setupAdminRouter()
+
+	for _, body := range []string{`{}`, `{"from_block": 100}`, `{"from_block": 200, "to_block": 100}`, `not json`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/admin/reconcile", bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "body %s", body)
+	}
+}
+
+func TestReprice(t *testing.T) {
+	router := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	body := `{"from_time": "2024-01-01T00:00:00Z", "to_block": 200, "providers": ["binance"], "dry_run": true}`
+	req, _ := http.NewRequest(http.MethodPost, "/admin/reprice", bytes.NewBufferString(body))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.RepriceResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.RepriceResponse{DryRun: true, Changes: []models.PriceChangeResponse{}}, response)
+}
+
+func TestReprice_InvalidBody(t *testing.T) {
+	router := setupAdminRouter()
+
+	for _, body := range []string{`{}`, `{"dry_run": true}`, `{"from_block": 100, "providers": ["coinbase"]}`, `{"from_time": "yesterday"}`, `not json`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/admin/reprice", bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "body %s", body)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+type PoolHandler struct {
+	syncService *syncer.Service
+}
+
+func NewPoolHandler(syncService *syncer.Service) *PoolHandler {
+	return &PoolHandler{
+		syncService: syncService,
+	}
+}
+
+// ListPools godoc
+// @Summary List tracked pools
+// @Description List the Uniswap V3 pools whose transactions are tracked
+// @Tags pools
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.PoolResponse
+// @Router /api/v1/pools [get]
+func (h *PoolHandler) ListPools(c *gin.Context) {
+	pools := h.syncService.Pools()
+
+	response := make([]models.PoolResponse, 0, len(pools))
+	for _, pool := range pools {
+		response = append(response, models.PoolResponse{
+			Name:       pool.Name,
+			Address:    pool.Address,
+			Token0:     pool.Token0.Symbol,
+			Token1:     pool.Token1.Symbol,
+			FeeTier:    pool.FeeTier,
+			StartBlock: pool.StartBlock,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
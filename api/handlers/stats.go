@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+const (
+	// defaultStatsInterval is the bucket width used when no interval is given
+	defaultStatsInterval = "1h"
+	// defaultStatsRange is the time range covered when no start is given
+	defaultStatsRange = 24 * time.Hour
+)
+
+type StatsHandler struct {
+	syncService *syncer.Service
+}
+
+func NewStatsHandler(syncService *syncer.Service) *StatsHandler {
+	return &StatsHandler{
+		syncService: syncService,
+	}
+}
+
+// GetFeeStats godoc
+// @Summary Get fee statistics over time
+// @Description Count, sum, mean, median and p90/p95/p99 of the transaction fees in ETH and USDT, and the average gas price, per time bucket.
+// @Description Buckets are aligned to the Unix epoch; at most 1000 buckets are returned.
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param interval query string false "Bucket width such as 15m, 1h or 1d" default(1h)
+// @Param from query string false "Start of the time range (RFC3339); defaults to 24 hours before to"
+// @Param to query string false "Exclusive end of the time range (RFC3339); defaults to now"
+// @Param pool query string false "Pool address"
+// @Success 200 {object} models.FeeStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/stats/fees [get]
+func (h *StatsHandler) GetFeeStats(c *gin.Context) {
+	var query models.FeeStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if query.Interval == "" {
+		query.Interval = defaultStatsInterval
+	}
+	interval, err := syncer.ParseStatsInterval(query.Interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	filter := syncer.FeeStatsFilter{
+		Interval:    interval,
+		ToTime:      time.Now(),
+		PoolAddress: query.Pool,
+	}
+	if query.To != nil {
+		filter.ToTime = *query.To
+	}
+	filter.FromTime = filter.ToTime.Add(-defaultStatsRange)
+	if query.From != nil {
+		filter.FromTime = *query.From
+	}
+
+	buckets, err := h.syncService.FeeStats(filter)
+	if err != nil {
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to compute fee statistics",
+		})
+		return
+	}
+
+	response := models.FeeStatsResponse{
+		Interval: interval.String(),
+		From:     filter.FromTime.UTC(),
+		To:       filter.ToTime.UTC(),
+		Buckets:  make([]models.FeeStatsBucketResponse, 0, len(buckets)),
+	}
+	if len(buckets) > 0 {
+		response.From = buckets[0].Start
+	}
+	for _, bucket := range buckets {
+		response.Buckets = append(response.Buckets, models.FeeStatsBucketResponse{
+			Start:           bucket.Start,
+			Count:           bucket.Count,
+			PricedCount:     bucket.PricedCount,
+			SumFeeETH:       formatBigFloat(bucket.SumFeeETH, 18),
+			MeanFeeETH:      formatBigFloat(bucket.MeanFeeETH, 18),
+			MedianFeeETH:    formatBigFloat(bucket.MedianFeeETH, 18),
+			P90FeeETH:       formatBigFloat(bucket.P90FeeETH, 18),
+			P95FeeETH:       formatBigFloat(bucket.P95FeeETH, 18),
+			P99FeeETH:       formatBigFloat(bucket.P99FeeETH, 18),
+			SumFeeUSDT:      formatBigFloat(bucket.SumFeeUSDT, 6),
+			MeanFeeUSDT:     formatBigFloat(bucket.MeanFeeUSDT, 6),
+			MedianFeeUSDT:   formatBigFloat(bucket.MedianFeeUSDT, 6),
+			P90FeeUSDT:      formatBigFloat(bucket.P90FeeUSDT, 6),
+			P95FeeUSDT:      formatBigFloat(bucket.P95FeeUSDT, 6),
+			P99FeeUSDT:      formatBigFloat(bucket.P99FeeUSDT, 6),
+			AverageGasPrice: formatBigFloat(bucket.AverageGasPrice, 0),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsRepository returns fixed fee statistics and records the filter it is queried with
+type statsRepository struct {
+	syncer.Repository
+	buckets []syncer.FeeStatsBucket
+	filter  *syncer.FeeStatsFilter
+}
+
+func (r *statsRepository) GetFeeStats(filter syncer.FeeStatsFilter) ([]syncer.FeeStatsBucket, error) {
+	r.filter = &filter
+	return r.buckets, nil
+}
+
+func TestGetFeeStats(t *testing.T) {
+	start := time.Date(2024, 2, 13, 0, 0, 0, 0, time.UTC)
+	repo := &statsRepository{buckets: []syncer.FeeStatsBucket{{
+		Start:           start.Add(24 * time.Hour),
+		Count:           2,
+		PricedCount:     2,
+		SumFeeUSDT:      syncer.NewBigFloat(big.NewFloat(12)),
+		MedianFeeUSDT:   syncer.NewBigFloat(big.NewFloat(6)),
+		P95FeeUSDT:      syncer.NewBigFloat(big.NewFloat(9.5)),
+		AverageGasPrice: syncer.NewBigFloat(big.NewFloat(25e9)),
+	}}}
+	handler := NewStatsHandler(syncer.NewService(&config.Config{}, nil, nil, nil, repo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stats/fees", handler.GetFeeStats)
+
+	get := func(query string) (int, models.FeeStatsResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/stats/fees"+query, nil)
+		router.ServeHTTP(w, req)
+		var response models.FeeStatsResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("?interval=1d&from=2024-02-13T06:00:00Z&to=2024-02-16T00:00:00Z")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 24*time.Hour, repo.filter.Interval)
+	assert.Equal(t, start, response.From)
+	require.Len(t, response.Buckets, 3)
+	assert.Zero(t, response.Buckets[0].Count)
+	assert.Empty(t, response.Buckets[0].MedianFeeUSDT)
+	assert.Equal(t, start.Add(24*time.Hour), response.Buckets[1].Start)
+	assert.Equal(t, int64(2), response.Buckets[1].Count)
+	assert.Equal(t, "12.000000", response.Buckets[1].SumFeeUSDT)
+	assert.Equal(t, "6.000000", response.Buckets[1].MedianFeeUSDT)
+	assert.Equal(t, "9.500000", response.Buckets[1].P95FeeUSDT)
+	assert.Equal(t, "25000000000", response.Buckets[1].AverageGasPrice)
+
+	// The last 24 hours in hourly buckets by default
+	code, response = get("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, time.Hour, repo.filter.Interval)
+	assert.Len(t, response.Buckets, 25)
+
+	for _, query := range []string{"?interval=hourly", "?interval=1s", "?interval=1m&from=2024-01-01T00:00:00Z&to=2024-02-01T00:00:00Z",
+		"?from=2024-02-13T00:00:00Z&to=2024-02-12T00:00:00Z", "?from=yesterday"} {
+		code, _ = get(query)
+		assert.Equal(t, http.StatusBadRequest, code, "query %s", query)
+	}
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"log"
+	"math/big"
+	"net/http"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+const (
+	// streamPingInterval is how often idle streams are pinged to keep proxies from closing them
+	streamPingInterval = 15 * time.Second
+	// streamWriteTimeout bounds a single WebSocket write
+	streamWriteTimeout = 10 * time.Second
+)
+
+// upgrader accepts WebSocket connections from any origin since the stream is read-only public data
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// StreamTransactions godoc
+// @Summary Stream newly processed transactions (SSE)
+// @Description Push each transaction as soon as live sync stores it, as Server-Sent Events named "transaction".
+// @Description Clients that fall behind are sent an "error" event and disconnected; they should reconnect and backfill with the search endpoint.
+// @Tags stream
+// @Produce text/event-stream
+// @Param min_fee_usdt query string false "Minimum fee in USDT"
+// @Param pool query string false "Pool address"
+// @Param address query string false "Sender or called contract address"
+// @Success 200 {object} models.TransactionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/stream/transactions [get]
+func (h *TransactionHandler) StreamTransactions(c *gin.Context) {
+	sub, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case tx, ok := <-sub.Transactions():
+			if !ok {
+				c.SSEvent("error", models.ErrorResponse{Error: sub.Err().Error()})
+				c.Writer.Flush()
+				return
+			}
+			c.SSEvent("transaction", h.toTransactionResponse(tx))
+			c.Writer.Flush()
+		case <-ping.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// StreamTransactionsWebSocket godoc
+// @Summary Stream newly processed transactions (WebSocket)
+// @Description Push each transaction as soon as live sync stores it as a JSON text message.
+// @Description Clients that fall behind are closed with status 1008 (policy violation) and should reconnect.
+// @Tags stream
+// @Param min_fee_usdt query string false "Minimum fee in USDT"
+// @Param pool query string false "Pool address"
+// @Param address query string false "Sender or called contract address"
+// @Success 101 {object} models.TransactionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /api/v1/stream/transactions/ws [get]
+func (h *TransactionHandler) StreamTransactionsWebSocket(c *gin.Context) {
+	sub, ok := h.subscribe(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already replied with an error
+		log.Printf("Failed to upgrade stream connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Incoming messages are ignored, but reading is required to process control frames and notice disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case tx, ok := <-sub.Transactions():
+			deadline := time.Now().Add(streamWriteTimeout)
+			if !ok {
+				message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, sub.Err().Error())
+				_ = conn.WriteControl(websocket.CloseMessage, message, deadline)
+				return
+			}
+			_ = conn.SetWriteDeadline(deadline)
+			if err := conn.WriteJSON(h.toTransactionResponse(tx)); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe validates the stream filters and subscribes to newly processed transactions,
+// replying with 400 when the filters are invalid
+func (h *TransactionHandler) subscribe(c *gin.Context) (*syncer.Subscription, bool) {
+	var query models.StreamQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	filter := syncer.StreamFilter{
+		PoolAddress: query.Pool,
+		Address:     query.Address,
+	}
+	if query.MinFeeUSDT != "" {
+		minFee, ok := new(big.Float).SetString(query.MinFeeUSDT)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "invalid min_fee_usdt",
+			})
+			return nil, false
+		}
+		filter.MinFeeUSDT = minFee
+	}
+	if err := filter.Normalize(); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return nil, false
+	}
+	return h.syncService.Hub().Subscribe(filter, syncer.DefaultSubscriptionBuffer), true
+}
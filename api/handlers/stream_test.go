@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamPool  = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	streamOther = "0x11b815efb8f581194ae79006d24e0d814b7697f6"
+)
+
+// newStreamServer serves the stream endpoints of a service without a repository
+func newStreamServer(t *testing.T) (*syncer.Service, *httptest.Server) {
+	service := syncer.NewService(&config.Config{}, nil, nil, nil, nil)
+	handler := NewTransactionHandler(service)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream/transactions", handler.StreamTransactions)
+	router.GET("/stream/transactions/ws", handler.StreamTransactionsWebSocket)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return service, server
+}
+
+// publishWhenSubscribed waits for a subscriber and publishes one transaction of each pool
+func publishWhenSubscribed(t *testing.T, service *syncer.Service) {
+	require.Eventually(t, func() bool { return service.Hub().Subscribers() == 1 }, time.Second, 5*time.Millisecond)
+	service.Hub().Publish([]*syncer.Transaction{
+		{TxHash: "0x01", PoolAddress: streamOther, GasUsed: syncer.NewBigInt(big.NewInt(21000)), GasPrice: syncer.NewBigInt(big.NewInt(1e9))},
+		{TxHash: "0x02", PoolAddress: streamPool, GasUsed: syncer.NewBigInt(big.NewInt(21000)), GasPrice: syncer.NewBigInt(big.NewInt(1e9))},
+	})
+}
+
+func TestStreamTransactions(t *testing.T) {
+	service, server := newStreamServer(t)
+
+	resp, err := http.Get(server.URL + "/stream/transactions?pool=0x1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/stream/transactions?pool=" + streamPool)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	publishWhenSubscribed(t, service)
+
+	reader := bufio.NewReader(resp.Body)
+	event, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event:transaction\n", event)
+	data, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	var tx models.TransactionResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data:")), &tx))
+	assert.Equal(t, "0x02", tx.TxHash)
+	assert.Equal(t, streamPool, tx.PoolAddress)
+}
+
+func TestStreamTransactionsWebSocket(t *testing.T) {
+	service, server := newStreamServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/stream/transactions/ws"
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?min_fee_usdt=cheap", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?pool="+streamPool, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	publishWhenSubscribed(t, service)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var tx models.TransactionResponse
+	require.NoError(t, conn.ReadJSON(&tx))
+	assert.Equal(t, "0x02", tx.TxHash)
+	assert.Equal(t, streamPool, tx.PoolAddress)
+
+	// Closing the connection ends the subscription
+	conn.Close()
+	assert.Eventually(t, func() bool { return service.Hub().Subscribers() == 0 }, time.Second, 5*time.Millisecond)
+}
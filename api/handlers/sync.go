@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+type SyncHandler struct {
+	syncService *syncer.Service
+}
+
+func NewSyncHandler(syncService *syncer.Service) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// GetSyncStatus godoc
+// @Summary Get sync status
+// @Description Report how far live sync trails the chain tip and the progress of every historical sync range,
+// @Description with ETAs estimated from the throughput of the last 10 minutes.
+// @Description Node errors are reported in live.error rather than failing the request.
+// @Tags sync
+// @Produce json
+// @Success 200 {object} models.SyncStatusResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/sync/status [get]
+func (h *SyncHandler) GetSyncStatus(c *gin.Context) {
+	report, err := h.syncService.SyncStatus(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to get sync status",
+		})
+		return
+	}
+
+	live := report.Live
+	response := models.SyncStatusResponse{
+		Live: models.LiveSyncStatusResponse{
+			TipBlock:         live.TipBlock,
+			LastTrackedBlock: live.LastTrackedBlock,
+			LastTrackedTime:  live.LastTrackedTime,
+			LagBlocks:        live.LagBlocks,
+			LagSeconds:       live.LagSeconds,
+			BlocksPerSecond:  roundRate(live.BlocksPerSecond),
+			ETASeconds:       seconds(live.ETA),
+			Paused:           live.Paused,
+			Error:            live.Error,
+		},
+		Historical:           make([]models.SyncProgressResponse, 0, len(report.Historical)),
+		HistoricalETASeconds: seconds(report.HistoricalETA),
+		HistoricalPaused:     report.HistoricalPaused,
+	}
+	if !live.TipTime.IsZero() {
+		response.Live.TipTime = &live.TipTime
+	}
+	for _, progress := range report.Historical {
+		item := toSyncProgressResponse(&progress.SyncProgress)
+		item.PercentComplete = math.Round(progress.Percent*100) / 100
+		item.BlocksPerSecond = roundRate(progress.BlocksPerSecond)
+		item.ETASeconds = seconds(progress.ETA)
+		response.Historical = append(response.Historical, item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// toSyncProgressResponse converts a historical sync range to its API representation
+func toSyncProgressResponse(progress *syncer.SyncProgress) models.SyncProgressResponse {
+	return models.SyncProgressResponse{
+		ID:                    progress.ID,
+		PoolAddress:           progress.PoolAddress,
+		StartBlock:            progress.StartBlock,
+		EndBlock:              progress.EndBlock,
+		LastProcessedBlock:    progress.LastProcessedBlock,
+		PercentComplete:       math.Round(progress.Percent()*100) / 100,
+		TransactionsProcessed: progress.TransactionsProcessed,
+		Status:                string(progress.Status),
+		Error:                 progress.ErrorMessage,
+		CreatedAt:             progress.CreatedAt,
+		UpdatedAt:             progress.UpdatedAt,
+		CompletedAt:           progress.CompletedAt,
+	}
+}
+
+// seconds returns a duration in whole seconds, or nil when unknown
+func seconds(d *time.Duration) *int64 {
+	if d == nil {
+		return nil
+	}
+	s := int64(d.Seconds())
+	return &s
+}
+
+// roundRate rounds a blocks per second rate to two decimals
+func roundRate(rate float64) float64 {
+	return math.Round(rate*100) / 100
+}
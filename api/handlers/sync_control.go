@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+// PauseLiveSync godoc
+// @Summary Pause live sync
+// @Description Stop live sync after the block being processed. Pausing a paused live sync does nothing.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} models.SyncControlResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/live/pause [post]
+func (h *AdminHandler) PauseLiveSync(c *gin.Context) {
+	if err := h.syncService.PauseLiveSync(); err != nil {
+		h.syncControlError(c, err, "Failed to pause live sync")
+		return
+	}
+	h.syncControlResponse(c)
+}
+
+// ResumeLiveSync godoc
+// @Summary Resume live sync
+// @Description Restart a paused live sync from the last tracked block, catching up on the blocks mined meanwhile.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} models.SyncControlResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/live/resume [post]
+func (h *AdminHandler) ResumeLiveSync(c *gin.Context) {
+	if err := h.syncService.ResumeLiveSync(); err != nil {
+		h.syncControlError(c, err, "Failed to resume live sync")
+		return
+	}
+	h.syncControlResponse(c)
+}
+
+// PauseHistoricalSync godoc
+// @Summary Pause historical sync
+// @Description Stop every running historical sync range after its current batch and keep queued ranges from starting.
+// @Description Paused ranges continue from their last processed block once resumed.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} models.SyncControlResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/historical/pause [post]
+func (h *AdminHandler) PauseHistoricalSync(c *gin.Context) {
+	h.syncService.PauseHistoricalSync()
+	h.syncControlResponse(c)
+}
+
+// ResumeHistoricalSync godoc
+// @Summary Resume historical sync
+// @Description Requeue every incomplete historical sync range after a pause.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} models.SyncControlResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/historical/resume [post]
+func (h *AdminHandler) ResumeHistoricalSync(c *gin.Context) {
+	if err := h.syncService.ResumeHistoricalSync(); err != nil {
+		h.syncControlError(c, err, "Failed to resume historical sync")
+		return
+	}
+	h.syncControlResponse(c)
+}
+
+// EnqueueBackfill godoc
+// @Summary Schedule a backfill
+// @Description Queue a historical sync of a tracked pool over a block range on the historical sync workers.
+// @Description Transactions already stored in the range are kept.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param request body models.BackfillRequest true "Pool and block range"
+// @Success 201 {object} models.SyncProgressResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/historical [post]
+func (h *AdminHandler) EnqueueBackfill(c *gin.Context) {
+	var request models.BackfillRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	progress, err := h.syncService.EnqueueBackfill(c.Request.Context(), request.Pool, *request.FromBlock, *request.ToBlock)
+	if err != nil {
+		h.syncControlError(c, err, "Failed to schedule backfill")
+		return
+	}
+	c.JSON(http.StatusCreated, toSyncProgressResponse(progress))
+}
+
+// CancelSyncJob godoc
+// @Summary Cancel a historical sync range
+// @Description Stop a running range after its current batch, or keep a queued, paused or failed one from running again.
+// @Tags admin
+// @Param X-API-Key header string true "Admin API key"
+// @Param id path int true "Sync progress ID"
+// @Success 202
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sync/historical/{id}/cancel [post]
+func (h *AdminHandler) CancelSyncJob(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.syncService.CancelSyncJob(id); err != nil {
+		h.syncControlError(c, err, "Failed to cancel sync job")
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// syncControlResponse replies with whether live and historical sync are paused
+func (h *AdminHandler) syncControlResponse(c *gin.Context) {
+	live, historical := h.syncService.SyncPaused()
+	c.JSON(http.StatusOK, models.SyncControlResponse{
+		LivePaused:       live,
+		HistoricalPaused: historical,
+	})
+}
+
+// syncControlError replies with 400 for invalid backfills, 404 for unknown jobs, 409 for jobs or sync that
+// cannot be controlled in their current state and 500 otherwise
+func (h *AdminHandler) syncControlError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, syncer.ErrInvalidBackfill):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, syncer.ErrSyncJobNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, syncer.ErrSyncJobFinished), errors.Is(err, syncer.ErrSyncNotStarted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: message,
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// syncControlRepository stores no sync progress
+type syncControlRepository struct {
+	syncer.Repository
+}
+
+func (r *syncControlRepository) GetSyncProgress(uint) (*syncer.SyncProgress, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestSyncControl(t *testing.T) {
+	handler := NewAdminHandler(syncer.NewService(&config.Config{Pools: config.DefaultPools}, nil, nil, nil, &syncControlRepository{}))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/sync/live/pause", handler.PauseLiveSync)
+	router.POST("/admin/sync/historical", handler.EnqueueBackfill)
+	router.POST("/admin/sync/historical/pause", handler.PauseHistoricalSync)
+	router.POST("/admin/sync/historical/:id/cancel", handler.CancelSyncJob)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/admin/sync/historical/pause", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.SyncControlResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.SyncControlResponse{HistoricalPaused: true}, response)
+
+	// Live sync was never started
+	assert.Equal(t, http.StatusConflict, post("/admin/sync/live/pause", "").Code)
+
+	assert.Equal(t, http.StatusNotFound, post("/admin/sync/historical/1/cancel", "").Code)
+	assert.Equal(t, http.StatusBadRequest, post("/admin/sync/historical/abc/cancel", "").Code)
+
+	// Rejected before the node is queried
+	for _, body := range []string{
+		`{"pool": "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", "from_block": 100}`,
+		`{"pool": "0xcbcdf9626bc03e24f779434178a73a0b4bad62ed", "from_block": 100, "to_block": 200}`,
+		`{"pool": "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", "from_block": 200, "to_block": 100}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, post("/admin/sync/historical", body).Code, body)
+	}
+}
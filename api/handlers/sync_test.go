@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/ethereum"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// syncRepository returns fixed sync progress
+type syncRepository struct {
+	syncer.Repository
+	progress []syncer.SyncProgress
+}
+
+func (r *syncRepository) ListSyncProgress() ([]syncer.SyncProgress, error) {
+	return r.progress, nil
+}
+
+func (r *syncRepository) GetLastTrackedBlock() (uint64, error) {
+	return 100, nil
+}
+
+// unreachableNode fails every request
+type unreachableNode struct {
+	ethereum.Client
+}
+
+func (unreachableNode) GetLatestBlockNumber(context.Context) (uint64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestGetSyncStatus(t *testing.T) {
+	repo := &syncRepository{progress: []syncer.SyncProgress{
+		{Model: gorm.Model{ID: 1}, PoolAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", StartBlock: 1000, EndBlock: 2000,
+			LastProcessedBlock: 1333, Status: syncer.SyncStatusRunning},
+		{Model: gorm.Model{ID: 2}, PoolAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", StartBlock: 2000, EndBlock: 3000,
+			LastProcessedBlock: 2500, Status: syncer.SyncStatusFailed, ErrorMessage: "boom"},
+	}}
+	handler := NewSyncHandler(syncer.NewService(&config.Config{}, nil, nil, unreachableNode{}, repo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sync/status", handler.GetSyncStatus)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/sync/status", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.SyncStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	// Node failures are reported without failing the request
+	assert.Equal(t, uint64(100), response.Live.LastTrackedBlock)
+	assert.Contains(t, response.Live.Error, "connection refused")
+	assert.Nil(t, response.Live.TipTime)
+
+	require.Len(t, response.Historical, 2)
+	assert.Equal(t, 33.3, response.Historical[0].PercentComplete)
+	assert.Equal(t, "RUNNING", response.Historical[0].Status)
+	assert.Nil(t, response.Historical[0].ETASeconds, "no throughput recorded")
+	assert.Equal(t, 50.0, response.Historical[1].PercentComplete)
+	assert.Equal(t, "boom", response.Historical[1].Error)
+	assert.Nil(t, response.HistoricalETASeconds)
+}
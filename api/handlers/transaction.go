@@ -1,12 +1,29 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"math/big"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"uniswap-fee-tracker/api/models"
 	"uniswap-fee-tracker/internal/syncer"
 )
 
+// txHashPattern matches a 32-byte hex transaction hash
+var txHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// maxBatchSize is the maximum number of hashes accepted by BatchGetTransactions
+const maxBatchSize = 1000
+
+// Decimals converted fees are formatted with: 6 like USDT for fiat currencies, satoshis for BTC
+const (
+	fiatDecimals = 6
+	btcDecimals  = 8
+)
+
 type TransactionHandler struct {
 	syncService *syncer.Service
 }
@@ -19,35 +36,322 @@ func NewTransactionHandler(syncService *syncer.Service) *TransactionHandler {
 
 // GetTransactionFee godoc
 // @Summary Get transaction fee in USDT
-// @Description Get the transaction fee in USDT for a specific Uniswap WETH-USDC transaction
+// @Description Get the transaction fee in USDT for a specific transaction of a tracked Uniswap pool.
+// @Description Transactions not yet reached by the sync are fetched from the node and stored on demand.
+// @Description When the ETH price is unavailable they are stored FAILED, without USDT figures, until the reconciler prices them.
+// @Description The fee is also converted to the currencies configured in FEE_CURRENCIES; conversions that failed or
+// @Description of currencies added after the transaction was stored are filled in by the price reconciler.
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Param txHash path string true "Transaction Hash"
+// @Param currency query string false "Only return the fee in this configured currency (e.g. EUR); all configured currencies are returned by default"
 // @Success 200 {object} models.TransactionResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/v1/transactions/{txHash} [get]
 func (h *TransactionHandler) GetTransactionFee(c *gin.Context) {
 	txHash := c.Param("txHash")
+	if !txHashPattern.MatchString(txHash) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid transaction hash",
+		})
+		return
+	}
+
+	currency := strings.ToUpper(c.Query("currency"))
+	if currency != "" && currency != "USDT" && !slices.Contains(h.syncService.FeeCurrencies(), currency) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Unsupported currency, expected USDT or one of: " + strings.Join(h.syncService.FeeCurrencies(), ", "),
+		})
+		return
+	}
+
+	tx, err := h.syncService.ResolveTransaction(c.Request.Context(), txHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, syncer.ErrTransactionNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Transaction not found or still pending",
+			})
+		case errors.Is(err, syncer.ErrNotPoolTransaction):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error: "Transaction does not interact with a tracked pool",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error: "Failed to resolve transaction",
+			})
+		}
+		return
+	}
+
+	response := h.toTransactionResponse(tx)
+	if currency != "" {
+		var fees []models.FeeResponse
+		for _, fee := range response.Fees {
+			if fee.Currency == currency {
+				fees = append(fees, fee)
+			}
+		}
+		response.Fees = fees
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListTransactions godoc
+// @Summary Search transactions
+// @Description List stored transactions filtered by block range, time range, fee, status, finality, pool, sender, target contract and revert status, using cursor-based pagination
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param from_block query int false "Lowest block number to include"
+// @Param to_block query int false "Highest block number to include"
+// @Param from_time query string false "Earliest transaction time (RFC3339)"
+// @Param to_time query string false "Latest transaction time (RFC3339)"
+// @Param min_fee_usdt query string false "Minimum fee in USDT"
+// @Param max_fee_usdt query string false "Maximum fee in USDT"
+// @Param status query string false "Processing status" Enums(PROCESSED, PENDING_PRICE, FAILED)
+// @Param finality query string false "Finality status" Enums(UNCONFIRMED, FINALIZED)
+// @Param pool query string false "Pool address"
+// @Param from query string false "Transaction sender address"
+// @Param to query string false "Address of the contract called by the transaction, e.g. a router"
+// @Param reverted query bool false "Only reverted (true) or successful (false) transactions"
+// @Param sort_by query string false "Sort field" Enums(block_number, timestamp, fee_usdt) default(block_number)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
+// @Param limit query int false "Page size (max 1000)" default(50)
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {object} models.TransactionListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/transactions [get]
+func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	var query models.TransactionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	filter, err := toTransactionFilter(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
 
-	tx, err := h.syncService.GetTransaction(txHash)
+	page, err := h.syncService.ListTransactions(filter)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: "Transaction not found",
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to list transactions",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.TransactionResponse{
-		TxHash:      tx.TxHash,
-		BlockNumber: tx.BlockNumber,
-		Timestamp:   tx.Timestamp,
-		GasUsed:     tx.GasUsed.String(),
-		GasPrice:    tx.GasPrice.String(),
-		FeeETH:      tx.FeeETH.Text('f', 18),
-		FeeUSDT:     tx.FeeUSDT.Text('f', 6),
-		ETHPrice:    tx.ETHPrice.Text('f', 6),
-		Status:      tx.Status,
-	})
+	response := models.TransactionListResponse{
+		Transactions: make([]models.TransactionResponse, 0, len(page.Transactions)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, tx := range page.Transactions {
+		response.Transactions = append(response.Transactions, h.toTransactionResponse(tx))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// BatchGetTransactions godoc
+// @Summary Look up many transactions
+// @Description Look up fees for up to 1000 transaction hashes in a single request
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param request body models.BatchTransactionRequest true "Transaction hashes"
+// @Success 200 {object} models.BatchTransactionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/transactions/batch [post]
+func (h *TransactionHandler) BatchGetTransactions(c *gin.Context) {
+	var request models.BatchTransactionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if len(request.TxHashes) == 0 || len(request.TxHashes) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "tx_hashes must contain between 1 and 1000 hashes",
+		})
+		return
+	}
+
+	txs, err := h.syncService.GetTransactions(request.TxHashes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to look up transactions",
+		})
+		return
+	}
+
+	response := models.BatchTransactionResponse{
+		Results: make(map[string]models.BatchTransactionResult, len(request.TxHashes)),
+	}
+	for _, txHash := range request.TxHashes {
+		tx, found := txs[strings.ToLower(txHash)]
+		if !found {
+			response.Results[txHash] = models.BatchTransactionResult{Status: models.BatchResultNotFound}
+			continue
+		}
+
+		txResponse := h.toTransactionResponse(tx)
+		status := models.BatchResultFound
+		if tx.Status != syncer.StatusProcessed {
+			status = models.BatchResultPending
+		}
+		response.Results[txHash] = models.BatchTransactionResult{
+			Status:      status,
+			Transaction: &txResponse,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// toTransactionFilter converts search query parameters into a repository filter
+func toTransactionFilter(query models.TransactionListQuery) (syncer.TransactionFilter, error) {
+	filter := syncer.TransactionFilter{
+		FromBlock:   query.FromBlock,
+		ToBlock:     query.ToBlock,
+		FromTime:    query.FromTime,
+		ToTime:      query.ToTime,
+		Status:      syncer.TransactionStatus(query.Status),
+		Finality:    syncer.FinalityStatus(query.Finality),
+		PoolAddress: query.Pool,
+		FromAddress: query.From,
+		ToAddress:   query.To,
+		Reverted:    query.Reverted,
+		SortBy:      syncer.SortField(query.SortBy),
+		Order:       syncer.SortOrder(query.Order),
+		Limit:       query.Limit,
+		Cursor:      query.Cursor,
+	}
+
+	if query.MinFeeUSDT != "" {
+		minFee, ok := new(big.Float).SetString(query.MinFeeUSDT)
+		if !ok {
+			return filter, errors.New("invalid min_fee_usdt")
+		}
+		filter.MinFeeUSDT = minFee
+	}
+	if query.MaxFeeUSDT != "" {
+		maxFee, ok := new(big.Float).SetString(query.MaxFeeUSDT)
+		if !ok {
+			return filter, errors.New("invalid max_fee_usdt")
+		}
+		filter.MaxFeeUSDT = maxFee
+	}
+	return filter, nil
+}
+
+// toTransactionResponse converts a stored transaction into its API representation
+func (h *TransactionHandler) toTransactionResponse(tx *syncer.Transaction) models.TransactionResponse {
+	response := models.TransactionResponse{
+		TxHash:        tx.TxHash,
+		BlockNumber:   tx.BlockNumber,
+		Timestamp:     tx.Timestamp,
+		PoolAddress:   tx.PoolAddress,
+		GasUsed:       formatBigInt(tx.GasUsed),
+		GasPrice:      formatBigInt(tx.GasPrice),
+		FeeETH:        formatBigFloat(tx.FeeETH, 18),
+		FeeUSDT:       formatBigFloat(tx.FeeUSDT, 6),
+		ETHPrice:      formatBigFloat(tx.ETHPrice, 6),
+		PriceSource:   tx.PriceSource,
+		PriceInterval: tx.PriceInterval,
+		PoolETHPrice:  formatBigFloat(tx.PoolETHPrice, 6),
+		Status:        tx.Status,
+		Finality:      tx.Finality,
+	}
+	response.TxType = tx.TxType
+	response.BaseFeePerGas = formatBigInt(tx.BaseFeePerGas)
+	response.MaxFeePerGas = formatBigInt(tx.MaxFeePerGas)
+	response.MaxPriorityFeePerGas = formatBigInt(tx.MaxPriorityFeePerGas)
+	response.BurnedFeeETH = formatBigFloat(tx.BurnedFeeETH, 18)
+	response.BurnedFeeUSDT = formatBigFloat(tx.BurnedFeeUSDT, 6)
+	response.TipFeeETH = formatBigFloat(tx.TipFeeETH, 18)
+	response.TipFeeUSDT = formatBigFloat(tx.TipFeeUSDT, 6)
+	response.From = tx.From
+	response.To = tx.To
+	response.Nonce = tx.Nonce
+	response.TransactionIndex = tx.TransactionIndex
+	response.Reverted = tx.Reverted
+	for _, swap := range tx.Swaps {
+		response.Swaps = append(response.Swaps, h.toSwapResponse(swap))
+	}
+	for _, fee := range tx.Fees {
+		decimals := fiatDecimals
+		if fee.Currency == "BTC" {
+			decimals = btcDecimals
+		}
+		response.Fees = append(response.Fees, models.FeeResponse{
+			Currency:    fee.Currency,
+			Fee:         formatBigFloat(fee.Fee, decimals),
+			ETHPrice:    formatBigFloat(fee.ETHPrice, decimals),
+			PriceSource: fee.PriceSource,
+		})
+	}
+	return response
+}
+
+// toSwapResponse converts a stored swap into its API representation, scaling amounts by token decimals
+func (h *TransactionHandler) toSwapResponse(swap syncer.Swap) models.SwapResponse {
+	response := models.SwapResponse{
+		LogIndex:       swap.LogIndex,
+		PoolAddress:    swap.PoolAddress,
+		Sender:         swap.Sender,
+		Recipient:      swap.Recipient,
+		Amount0:        formatBigInt(swap.Amount0),
+		Amount1:        formatBigInt(swap.Amount1),
+		ExecutionPrice: formatBigFloat(swap.ExecutionPrice, 6),
+		SqrtPriceX96:   formatBigInt(swap.SqrtPriceX96),
+		Tick:           swap.Tick,
+	}
+
+	pool, ok := h.syncService.Pool(swap.PoolAddress)
+	if !ok {
+		return response
+	}
+	response.Token0 = pool.Token0.Symbol
+	response.Token1 = pool.Token1.Symbol
+	response.PriceUnit = pool.PriceUnit()
+	if swap.Amount0 != nil && swap.Amount0.Int != nil {
+		response.Amount0 = pool.Token0.ScaleAmount(swap.Amount0.Int).Text('f', pool.Token0.Decimals)
+	}
+	if swap.Amount1 != nil && swap.Amount1.Int != nil {
+		response.Amount1 = pool.Token1.ScaleAmount(swap.Amount1.Int).Text('f', pool.Token1.Decimals)
+	}
+	return response
+}
+
+// formatBigInt returns the decimal representation of v, or an empty string when unset
+func formatBigInt(v *syncer.BigInt) string {
+	if v == nil || v.Int == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// formatBigFloat returns v with the given number of decimals, or an empty string when unset
+// (e.g. fees of transactions that have not been priced yet)
+func formatBigFloat(v *syncer.BigFloat, decimals int) string {
+	if v == nil || v.Float == nil {
+		return ""
+	}
+	return v.Text('f', decimals)
 }
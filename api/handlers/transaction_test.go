@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/price"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeRepository serves transactions from memory; unimplemented methods panic via the nil embedded interface
+type fakeRepository struct {
+	syncer.Repository
+	txs map[string]*syncer.Transaction
+}
+
+func (r *fakeRepository) GetTransaction(txHash string) (*syncer.Transaction, error) {
+	if tx, ok := r.txs[txHash]; ok {
+		return tx, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRepository) GetTransactionsByHashes(txHashes []string) ([]*syncer.Transaction, error) {
+	var result []*syncer.Transaction
+	for _, txHash := range txHashes {
+		if tx, ok := r.txs[txHash]; ok {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
+
+func setupTransactionRouter(txs ...*syncer.Transaction) *gin.Engine {
+	repo := &fakeRepository{txs: make(map[string]*syncer.Transaction)}
+	for _, tx := range txs {
+		repo.txs[tx.TxHash] = tx
+	}
+	handler := NewTransactionHandler(syncer.NewService(&config.Config{}, nil, nil, nil, repo))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/transactions", handler.ListTransactions)
+	r.GET("/transactions/:txHash", handler.GetTransactionFee)
+	r.POST("/transactions/batch", handler.BatchGetTransactions)
+	return r
+}
+
+func TestGetTransactionFee(t *testing.T) {
+	txHash := "0x8395927f2e5f97b2a31fd63063d12a51fa73438523305b5b30e7bec6afb26f48"
+	tx := &syncer.Transaction{
+		TxHash:   txHash,
+		GasUsed:  syncer.NewBigInt(big.NewInt(150000)),
+		GasPrice: syncer.NewBigInt(big.NewInt(2e10)),
+		Finality: syncer.FinalityFinalized,
+		From:     "0x5f6ab5e2ab6a2f4d3c6a1b2b9b5c1e7c7f3e9a01",
+		To:       "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad",
+		Nonce:    412,
+		Reverted: true,
+	}
+	tx.SetBaseFee(big.NewInt(1.5e10))
+	tx.UpdatePrices(big.NewFloat(2500))
+	router := setupTransactionRouter(tx)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/transactions/"+txHash, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.TransactionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "0.003000000000000000", response.FeeETH)
+	assert.Equal(t, "7.500000", response.FeeUSDT)
+	assert.Equal(t, syncer.FinalityFinalized, response.Finality)
+	assert.Equal(t, "15000000000", response.BaseFeePerGas)
+	assert.Equal(t, tx.From, response.From)
+	assert.Equal(t, tx.To, response.To)
+	assert.Equal(t, uint64(412), response.Nonce)
+	assert.True(t, response.Reverted)
+	assert.Equal(t, "5.625000", response.BurnedFeeUSDT)
+	assert.Equal(t, "0.000750000000000000", response.TipFeeETH)
+	assert.Equal(t, "1.875000", response.TipFeeUSDT)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/transactions/0x123", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetTransactionFee_Currency(t *testing.T) {
+	txHash := "0x8395927f2e5f97b2a31fd63063d12a51fa73438523305b5b30e7bec6afb26f48"
+	tx := &syncer.Transaction{
+		TxHash:   txHash,
+		GasUsed:  syncer.NewBigInt(big.NewInt(150000)),
+		GasPrice: syncer.NewBigInt(big.NewInt(2e10)),
+	}
+	tx.UpdatePrices(big.NewFloat(2500))
+	tx.ApplyCurrencyQuote(&price.Quote{Pair: price.Pair{Base: "ETH", Quote: "EUR"}, Price: big.NewFloat(2300), Source: "coinbase"})
+	tx.ApplyCurrencyQuote(&price.Quote{Pair: price.Pair{Base: "ETH", Quote: "BTC"}, Price: big.NewFloat(0.0525), Source: "binance"})
+
+	repo := &fakeRepository{txs: map[string]*syncer.Transaction{txHash: tx}}
+	cfg := &config.Config{FeeCurrencies: []string{"EUR", "BTC"}}
+	handler := NewTransactionHandler(syncer.NewService(cfg, nil, nil, nil, repo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/transactions/:txHash", handler.GetTransactionFee)
+
+	get := func(query string) (int, models.TransactionResponse) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/transactions/"+txHash+query, nil)
+		router.ServeHTTP(w, req)
+		var response models.TransactionResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.FeeResponse{
+		{Currency: "EUR", Fee: "6.900000", ETHPrice: "2300.000000", PriceSource: "coinbase"},
+		{Currency: "BTC", Fee: "0.00015750", ETHPrice: "0.05250000", PriceSource: "binance"},
+	}, response.Fees)
+
+	code, response = get("?currency=eur")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.FeeResponse{{Currency: "EUR", Fee: "6.900000", ETHPrice: "2300.000000", PriceSource: "coinbase"}}, response.Fees)
+	assert.Equal(t, "7.500000", response.FeeUSDT)
+
+	code, response = get("?currency=USDT")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, response.Fees)
+
+	code, _ = get("?currency=GBP")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestBatchGetTransactions(t *testing.T) {
+	processed := &syncer.Transaction{
+		TxHash:   "0xaaa",
+		GasUsed:  syncer.NewBigInt(big.NewInt(21000)),
+		GasPrice: syncer.NewBigInt(big.NewInt(1e9)),
+	}
+	processed.UpdatePrices(big.NewFloat(2000))
+	pending := &syncer.Transaction{
+		TxHash:   "0xbbb",
+		GasUsed:  syncer.NewBigInt(big.NewInt(21000)),
+		GasPrice: syncer.NewBigInt(big.NewInt(1e9)),
+		Status:   syncer.StatusPendingPrice,
+	}
+	router := setupTransactionRouter(processed, pending)
+
+	body, _ := json.Marshal(models.BatchTransactionRequest{TxHashes: []string{"0xAAA", "0xbbb", "0xccc"}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/transactions/batch", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.BatchTransactionResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 3)
+
+	assert.Equal(t, models.BatchResultFound, response.Results["0xAAA"].Status)
+	assert.Equal(t, "0.042000", response.Results["0xAAA"].Transaction.FeeUSDT)
+	assert.Equal(t, models.BatchResultPending, response.Results["0xbbb"].Status)
+	assert.Equal(t, "", response.Results["0xbbb"].Transaction.FeeUSDT)
+	assert.Equal(t, models.BatchResultNotFound, response.Results["0xccc"].Status)
+	assert.Nil(t, response.Results["0xccc"].Transaction)
+}
+
+func TestBatchGetTransactions_InvalidBody(t *testing.T) {
+	router := setupTransactionRouter()
+
+	for _, body := range []string{`{}`, `{"tx_hashes": []}`, `not json`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/transactions/batch", bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "body %s", body)
+	}
+}
+
+func TestListTransactions_InvalidQuery(t *testing.T) {
+	router := setupTransactionRouter()
+
+	for _, query := range []string{"sort_by=gas", "limit=5000", "min_fee_usdt=abc", "from_block=x", "cursor=%21%21", "finality=SAFE"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/transactions?"+query, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "query %s", query)
+	}
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"math/big"
+	"net/http"
+	"strconv"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+// CreateWebhook godoc
+// @Summary Subscribe a webhook
+// @Description Notify an endpoint of every newly stored transaction matching all of the given rules.
+// @Description Payloads are signed with HMAC-SHA256 over "<X-Webhook-Timestamp>.<body>" in the X-Webhook-Signature header.
+// @Description The secret is only returned in this response; it is generated when not given.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param request body models.WebhookRequest true "Endpoint and rules"
+// @Success 201 {object} models.WebhookResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks [post]
+func (h *AdminHandler) CreateWebhook(c *gin.Context) {
+	var request models.WebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	sub := &syncer.WebhookSubscription{
+		URL:     request.URL,
+		Secret:  request.Secret,
+		Address: request.Address,
+	}
+	if request.MinFeeUSDT != "" {
+		minFee, ok := new(big.Float).SetString(request.MinFeeUSDT)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "invalid min_fee_usdt",
+			})
+			return
+		}
+		sub.MinFeeUSDT = syncer.NewBigFloat(minFee)
+	}
+	if request.MinGasPrice != "" {
+		minGasPrice, ok := new(big.Int).SetString(request.MinGasPrice, 10)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "invalid min_gas_price",
+			})
+			return
+		}
+		sub.MinGasPrice = syncer.NewBigInt(minGasPrice)
+	}
+
+	if err := h.syncService.CreateWebhookSubscription(sub); err != nil {
+		if errors.Is(err, syncer.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to create webhook",
+		})
+		return
+	}
+
+	response := toWebhookResponse(sub)
+	response.Secret = sub.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List every webhook subscription. Secrets are not returned.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Success 200 {object} models.WebhookListResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks [get]
+func (h *AdminHandler) ListWebhooks(c *gin.Context) {
+	subs, err := h.syncService.ListWebhookSubscriptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: "Failed to list webhooks",
+		})
+		return
+	}
+
+	response := models.WebhookListResponse{
+		Webhooks: make([]models.WebhookResponse, 0, len(subs)),
+	}
+	for i := range subs {
+		response.Webhooks = append(response.Webhooks, toWebhookResponse(&subs[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Remove a webhook subscription along with its pending and past deliveries.
+// @Tags admin
+// @Param X-API-Key header string true "Admin API key"
+// @Param id path int true "Subscription ID"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id} [delete]
+func (h *AdminHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.syncService.DeleteWebhookSubscription(id); err != nil {
+		h.webhookError(c, err, "Failed to delete webhook")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the most recent deliveries of a webhook, e.g. status=DEAD for the dead-lettered ones.
+// @Tags admin
+// @Produce json
+// @Param X-API-Key header string true "Admin API key"
+// @Param id path int true "Subscription ID"
+// @Param status query string false "Delivery status" Enums(PENDING, DELIVERED, DEAD)
+// @Param limit query int false "Maximum number of deliveries (max 1000)" default(50)
+// @Success 200 {object} models.WebhookDeliveriesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id}/deliveries [get]
+func (h *AdminHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	var query models.WebhookDeliveriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	deliveries, err := h.syncService.ListWebhookDeliveries(syncer.WebhookDeliveryFilter{
+		SubscriptionID: id,
+		Status:         syncer.WebhookStatus(query.Status),
+		Limit:          query.Limit,
+	})
+	if err != nil {
+		h.webhookError(c, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	response := models.WebhookDeliveriesResponse{
+		Deliveries: make([]models.WebhookDeliveryResponse, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		item := models.WebhookDeliveryResponse{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			TxHash:         delivery.TxHash,
+			Status:         string(delivery.Status),
+			Attempts:       delivery.Attempts,
+			LastError:      delivery.LastError,
+			DeliveredAt:    delivery.DeliveredAt,
+			CreatedAt:      delivery.CreatedAt,
+		}
+		if delivery.Status == syncer.WebhookStatusPending {
+			next := delivery.NextAttemptAt
+			item.NextAttemptAt = &next
+		}
+		response.Deliveries = append(response.Deliveries, item)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RetryWebhookDelivery godoc
+// @Summary Retry a dead-lettered webhook delivery
+// @Description Requeue a DEAD delivery for immediate delivery with a fresh attempt budget.
+// @Tags admin
+// @Param X-API-Key header string true "Admin API key"
+// @Param id path int true "Subscription ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/{id}/deliveries/{deliveryId}/retry [post]
+func (h *AdminHandler) RetryWebhookDelivery(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseID(c, "deliveryId")
+	if !ok {
+		return
+	}
+	if err := h.syncService.RetryWebhookDelivery(id, deliveryID); err != nil {
+		h.webhookError(c, err, "Failed to retry webhook delivery")
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// webhookError replies with 400 for invalid filters, 404 for unknown webhooks and 500 otherwise
+func (h *AdminHandler) webhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, syncer.ErrInvalidFilter):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, syncer.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error: message,
+		})
+	}
+}
+
+// parseID reads a positive integer path parameter, replying with 400 when it is invalid
+func parseID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "invalid " + name,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// toWebhookResponse converts a subscription to its API representation without its secret
+func toWebhookResponse(sub *syncer.WebhookSubscription) models.WebhookResponse {
+	return models.WebhookResponse{
+		ID:          sub.ID,
+		URL:         sub.URL,
+		MinFeeUSDT:  formatBigFloat(sub.MinFeeUSDT, 6),
+		MinGasPrice: formatBigInt(sub.MinGasPrice),
+		Address:     sub.Address,
+		CreatedAt:   sub.CreatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/syncer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// webhookRepository keeps webhook subscriptions in memory
+type webhookRepository struct {
+	syncer.Repository
+	subs []syncer.WebhookSubscription
+}
+
+func (r *webhookRepository) CreateWebhookSubscription(sub *syncer.WebhookSubscription) error {
+	sub.ID = uint(len(r.subs) + 1)
+	r.subs = append(r.subs, *sub)
+	return nil
+}
+
+func (r *webhookRepository) ListWebhookSubscriptions() ([]syncer.WebhookSubscription, error) {
+	return r.subs, nil
+}
+
+func (r *webhookRepository) DeleteWebhookSubscription(id uint) error {
+	for i, sub := range r.subs {
+		if sub.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *webhookRepository) RetryWebhookDelivery(uint, uint) error {
+	return gorm.ErrRecordNotFound
+}
+
+func TestWebhooks(t *testing.T) {
+	repo := &webhookRepository{}
+	handler := NewAdminHandler(syncer.NewService(&config.Config{}, nil, nil, nil, repo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/webhooks", handler.CreateWebhook)
+	router.GET("/admin/webhooks", handler.ListWebhooks)
+	router.DELETE("/admin/webhooks/:id", handler.DeleteWebhook)
+	router.POST("/admin/webhooks/:id/deliveries/:deliveryId/retry", handler.RetryWebhookDelivery)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/admin/webhooks",
+		`{"url": "https://example.com/hook", "min_fee_usdt": "50", "min_gas_price": "20000000000", "address": "0x5F6AB5E2AB6A2F4D3C6A1B2B9B5C1E7C7F3E9A01"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created models.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, uint(1), created.ID)
+	assert.Len(t, created.Secret, 64)
+	assert.Equal(t, "50.000000", created.MinFeeUSDT)
+	assert.Equal(t, "20000000000", created.MinGasPrice)
+	assert.Equal(t, "0x5f6ab5e2ab6a2f4d3c6a1b2b9b5c1e7c7f3e9a01", created.Address)
+
+	for _, body := range []string{`{}`, `{"url": "not a url"}`, `{"url": "https://example.com", "min_fee_usdt": "lots"}`,
+		`{"url": "https://example.com", "min_gas_price": "1.5"}`, `{"url": "https://example.com", "address": "0x1"}`} {
+		w = do(http.MethodPost, "/admin/webhooks", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "body %s", body)
+	}
+
+	// Secrets are only returned on creation
+	w = do(http.MethodGet, "/admin/webhooks", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.WebhookListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Webhooks, 1)
+	assert.Empty(t, list.Webhooks[0].Secret)
+	assert.Equal(t, "https://example.com/hook", list.Webhooks[0].URL)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/admin/webhooks/1/deliveries/7/retry", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/admin/webhooks/abc", "").Code)
+	assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, "/admin/webhooks/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/admin/webhooks/1", "").Code)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+	"uniswap-fee-tracker/api/models"
+	"uniswap-fee-tracker/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminAPIKeyHeader carries the key required by admin endpoints
+const adminAPIKeyHeader = "X-API-Key"
+
+// AdminAuth rejects requests that do not carry the admin API key. Admin endpoints are disabled
+// when no key is configured.
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error: "Admin API is disabled",
+			})
+			return
+		}
+		provided := c.GetHeader(adminAPIKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error: "Invalid API key",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Metrics records the latency of every request by route and status. Requests matching no route are
+// recorded under a single "unmatched" route so arbitrary paths do not create new series.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
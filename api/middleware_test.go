@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdminRouter(apiKey string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/admin", AdminAuth(apiKey), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   string
+		header   string
+		expected int
+	}{
+		{"valid key", "secret", "secret", http.StatusOK},
+		{"wrong key", "secret", "guess", http.StatusUnauthorized},
+		{"missing key", "secret", "", http.StatusUnauthorized},
+		{"admin disabled", "", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupAdminRouter(tt.apiKey)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set(adminAPIKeyHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.GET("/items/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	for _, path := range []string{"/items/1", "/items/2", "/unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(w, req)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Requests are labelled by route template rather than path
+	assert.Contains(t, w.Body.String(),
+		`uniswap_fee_tracker_http_request_duration_seconds_count{method="GET",route="/items/:id",status="200"} 2`)
+	assert.Contains(t, w.Body.String(),
+		`uniswap_fee_tracker_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, w.Body.String(), `route="/items/1"`)
+}
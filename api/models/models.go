@@ -26,6 +26,10 @@ type TransactionResponse struct {
 	// @Description When this transaction was processed
 	Timestamp time.Time `json:"timestamp"`
 
+	// Pool address
+	// @Description First tracked pool the transaction interacted with
+	PoolAddress string `json:"pool_address"`
+
 	// Gas used
 	// @Description Amount of gas used by this transaction
 	GasUsed string `json:"gas_used"`
@@ -46,9 +50,814 @@ type TransactionResponse struct {
 	// @Description ETH/USDT price at transaction time
 	ETHPrice string `json:"eth_price"`
 
+	// Price source
+	// @Description Price provider the ETH price was taken from (binance, coinbase, kraken or uniswap)
+	PriceSource string `json:"price_source,omitempty"`
+
+	// Price interval
+	// @Description Candle interval the ETH price was taken from (1s, 1m, 5m or 1h); coarser intervals are used for dates without 1s data
+	PriceInterval string `json:"price_interval,omitempty"`
+
+	// On-chain ETH price
+	// @Description ETH price derived from the sqrtPriceX96 of the tracked WETH/stablecoin pool at the transaction's block
+	PoolETHPrice string `json:"pool_eth_price,omitempty"`
+
+	// Transaction type
+	// @Description EIP-2718 transaction type: 0 legacy, 1 access list, 2 EIP-1559, 3 blob, 4 set code
+	TxType uint8 `json:"tx_type"`
+
+	// Base fee per gas
+	// @Description Base fee of the transaction's block in Wei; 0 before the London fork
+	BaseFeePerGas string `json:"base_fee_per_gas,omitempty"`
+
+	// Max fee per gas
+	// @Description maxFeePerGas of EIP-1559 transactions in Wei
+	MaxFeePerGas string `json:"max_fee_per_gas,omitempty"`
+
+	// Max priority fee per gas
+	// @Description maxPriorityFeePerGas of EIP-1559 transactions in Wei
+	MaxPriorityFeePerGas string `json:"max_priority_fee_per_gas,omitempty"`
+
+	// Burned fee in ETH
+	// @Description Part of the fee burned as base fee (gas used * base fee), in ETH
+	BurnedFeeETH string `json:"burned_fee_eth,omitempty"`
+
+	// Burned fee in USDT
+	// @Description Part of the fee burned as base fee, in USDT
+	BurnedFeeUSDT string `json:"burned_fee_usdt,omitempty"`
+
+	// Tip in ETH
+	// @Description Priority fee paid to the block builder (gas used * (gas price - base fee)), in ETH
+	TipFeeETH string `json:"tip_fee_eth,omitempty"`
+
+	// Tip in USDT
+	// @Description Priority fee paid to the block builder, in USDT
+	TipFeeUSDT string `json:"tip_fee_usdt,omitempty"`
+
+	// Sender
+	// @Description Address that sent the transaction
+	From string `json:"from"`
+
+	// Target contract
+	// @Description Contract called by the transaction, usually a router or aggregator; empty for contract creations
+	To string `json:"to"`
+
+	// Nonce
+	// @Description Nonce of the transaction sender
+	Nonce uint64 `json:"nonce"`
+
+	// Transaction index
+	// @Description Position of the transaction within its block
+	TransactionIndex uint `json:"transaction_index"`
+
+	// Reverted
+	// @Description True when the transaction reverted; its fee was paid without executing a swap
+	Reverted bool `json:"reverted"`
+
 	// Transaction status
 	// @Description Current processing status of the transaction
 	Status syncer.TransactionStatus `json:"status"`
+
+	// Finality
+	// @Description UNCONFIRMED while the block can still be reorganized away or the fee is unpriced (the fee may change), FINALIZED afterwards
+	Finality syncer.FinalityStatus `json:"finality"`
+
+	// Decoded swaps
+	// @Description Uniswap V3 Swap events emitted by the pool in this transaction
+	Swaps []SwapResponse `json:"swaps,omitempty"`
+
+	// Fees in other currencies
+	// @Description Fee converted to each currency configured in FEE_CURRENCIES, or to the requested currency only
+	Fees []FeeResponse `json:"fees,omitempty"`
+}
+
+// FeeResponse represents a transaction fee converted to a currency other than USDT
+// @Description Fee and ETH price in a configured currency
+type FeeResponse struct {
+	// Currency
+	// @Description Currency code, e.g. EUR or BTC
+	Currency string `json:"currency"`
+
+	// Fee
+	// @Description Transaction fee in the currency
+	Fee string `json:"fee"`
+
+	// ETH price
+	// @Description Price of 1 ETH in the currency at transaction time
+	ETHPrice string `json:"eth_price"`
+
+	// Price source
+	// @Description Price provider the conversion rate was taken from
+	PriceSource string `json:"price_source,omitempty"`
+}
+
+// SwapResponse represents a decoded Uniswap V3 swap
+// @Description Swap amounts and implied execution price decoded from a pool Swap event
+type SwapResponse struct {
+	// Log index
+	// @Description Position of the Swap event within the block
+	LogIndex uint `json:"log_index"`
+
+	// Pool address
+	// @Description Address of the pool that emitted the event
+	PoolAddress string `json:"pool_address"`
+
+	// Swap sender
+	// @Description Address that initiated the swap (usually a router)
+	Sender string `json:"sender"`
+
+	// Swap recipient
+	// @Description Address that received the output tokens
+	Recipient string `json:"recipient"`
+
+	// Token0 symbol
+	// @Description Symbol of the pool's token0
+	Token0 string `json:"token0"`
+
+	// Token1 symbol
+	// @Description Symbol of the pool's token1
+	Token1 string `json:"token1"`
+
+	// Token0 amount
+	// @Description Decimal-adjusted token0 amount; positive when paid into the pool, negative when paid out
+	Amount0 string `json:"amount0"`
+
+	// Token1 amount
+	// @Description Decimal-adjusted token1 amount; positive when paid into the pool, negative when paid out
+	Amount1 string `json:"amount1"`
+
+	// Execution price
+	// @Description Price implied by the swapped amounts, expressed in price_unit
+	ExecutionPrice string `json:"execution_price"`
+
+	// Price unit
+	// @Description Unit of execution_price, e.g. USDC/WETH
+	PriceUnit string `json:"price_unit"`
+
+	// Pool sqrt price after the swap
+	// @Description sqrtPriceX96 of the pool after the swap
+	SqrtPriceX96 string `json:"sqrt_price_x96"`
+
+	// Pool tick after the swap
+	// @Description Current tick of the pool after the swap
+	Tick int32 `json:"tick"`
+}
+
+// TransactionListQuery represents the query parameters of the transaction search endpoint
+type TransactionListQuery struct {
+	// Lowest block number to include
+	FromBlock *uint64 `form:"from_block"`
+
+	// Highest block number to include
+	ToBlock *uint64 `form:"to_block"`
+
+	// Earliest transaction time to include (RFC3339)
+	FromTime *time.Time `form:"from_time" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Latest transaction time to include (RFC3339)
+	ToTime *time.Time `form:"to_time" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Minimum fee in USDT
+	MinFeeUSDT string `form:"min_fee_usdt"`
+
+	// Maximum fee in USDT
+	MaxFeeUSDT string `form:"max_fee_usdt"`
+
+	// Processing status
+	Status string `form:"status"`
+
+	// Finality: UNCONFIRMED or FINALIZED
+	Finality string `form:"finality"`
+
+	// Pool address
+	Pool string `form:"pool"`
+
+	// Transaction sender
+	From string `form:"from"`
+
+	// Contract called by the transaction
+	To string `form:"to"`
+
+	// Only reverted (true) or successful (false) transactions
+	Reverted *bool `form:"reverted"`
+
+	// Sort field: block_number, timestamp or fee_usdt
+	SortBy string `form:"sort_by"`
+
+	// Sort order: asc or desc
+	Order string `form:"order"`
+
+	// Page size
+	Limit int `form:"limit"`
+
+	// Cursor returned by the previous page
+	Cursor string `form:"cursor"`
+}
+
+// TransactionListResponse represents a page of transaction search results
+// @Description Paginated list of transactions matching the search filters
+type TransactionListResponse struct {
+	// Transactions in this page
+	// @Description Transactions matching the filters, in the requested order
+	Transactions []TransactionResponse `json:"transactions"`
+
+	// Cursor for the next page
+	// @Description Pass as the cursor parameter to fetch the next page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// AddressFeesQuery represents the query parameters of the address fee summary endpoint
+type AddressFeesQuery struct {
+	// Earliest transaction time to include (RFC3339)
+	FromTime *time.Time `form:"from_time" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Latest transaction time to include (RFC3339)
+	ToTime *time.Time `form:"to_time" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Only include transactions of this trailing window, e.g. 24h or 720h; cannot be combined with from_time
+	Window string `form:"window"`
+
+	// Also return a page of the address's transactions
+	Transactions bool `form:"transactions"`
+
+	// Sort field of the transactions: block_number, timestamp or fee_usdt
+	SortBy string `form:"sort_by"`
+
+	// Sort order of the transactions: asc or desc
+	Order string `form:"order"`
+
+	// Page size
+	Limit int `form:"limit"`
+
+	// Cursor returned by the previous page
+	Cursor string `form:"cursor"`
+}
+
+// AddressFeesResponse represents the fees paid by an address
+// @Description Fee totals of the transactions an address sent to the tracked pools, with an optional page of transactions
+type AddressFeesResponse struct {
+	// Address
+	// @Description Sender address, lowercase
+	Address string `json:"address"`
+
+	// Start of the window
+	// @Description Earliest transaction time included, absent when unbounded
+	FromTime *time.Time `json:"from_time,omitempty"`
+
+	// End of the window
+	// @Description Latest transaction time included, absent when unbounded
+	ToTime *time.Time `json:"to_time,omitempty"`
+
+	// Transaction count
+	// @Description Transactions sent by the address in the window, including reverted and unpriced ones
+	TransactionCount int64 `json:"transaction_count"`
+
+	// Priced transaction count
+	// @Description Transactions whose USDT fee is known; the USDT figures only cover these
+	PricedCount int64 `json:"priced_count"`
+
+	// Reverted transaction count
+	// @Description Reverted transactions, whose fee was paid without swapping
+	RevertedCount int64 `json:"reverted_count"`
+
+	// Total fee in ETH
+	// @Description Sum of the transaction fees in ETH
+	TotalFeeETH string `json:"total_fee_eth"`
+
+	// Total fee in USDT
+	// @Description Sum of the transaction fees in USDT
+	TotalFeeUSDT string `json:"total_fee_usdt"`
+
+	// Average fee in ETH
+	// @Description Average transaction fee in ETH, absent without transactions
+	AverageFeeETH string `json:"average_fee_eth,omitempty"`
+
+	// Average fee in USDT
+	// @Description Average fee of the priced transactions in USDT, absent without priced transactions
+	AverageFeeUSDT string `json:"average_fee_usdt,omitempty"`
+
+	// First seen
+	// @Description Time of the address's first transaction in the window
+	FirstSeen *time.Time `json:"first_seen,omitempty"`
+
+	// Last seen
+	// @Description Time of the address's last transaction in the window
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+
+	// Transactions
+	// @Description Page of the address's transactions, only returned when transactions=true
+	Transactions []TransactionResponse `json:"transactions,omitempty"`
+
+	// Cursor for the next page
+	// @Description Pass as the cursor parameter to fetch the next page of transactions; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// StreamQuery represents the filters of the transaction stream endpoints
+type StreamQuery struct {
+	// Minimum fee in USDT; unpriced transactions are not streamed when set
+	MinFeeUSDT string `form:"min_fee_usdt"`
+
+	// Pool address
+	Pool string `form:"pool"`
+
+	// Sender or called contract
+	Address string `form:"address"`
+}
+
+// BatchTransactionRequest represents the body of the batch transaction lookup endpoint
+// @Description List of transaction hashes to look up
+type BatchTransactionRequest struct {
+	// Transaction hashes
+	// @Description Transaction hashes to look up (at most 1000)
+	TxHashes []string `json:"tx_hashes" binding:"required"`
+}
+
+// BatchResultStatus describes the lookup outcome for a single hash in a batch request
+type BatchResultStatus string
+
+const (
+	// BatchResultFound means the transaction is stored and its fee has been computed
+	BatchResultFound BatchResultStatus = "FOUND"
+	// BatchResultPending means the transaction is stored but its fee is not available yet
+	BatchResultPending BatchResultStatus = "PENDING"
+	// BatchResultNotFound means the transaction is not stored
+	BatchResultNotFound BatchResultStatus = "NOT_FOUND"
+)
+
+// BatchTransactionResult represents the lookup result for a single hash
+// @Description Lookup outcome for one transaction hash
+type BatchTransactionResult struct {
+	// Lookup status
+	// @Description FOUND, PENDING or NOT_FOUND
+	Status BatchResultStatus `json:"status"`
+
+	// Transaction details
+	// @Description Present when the transaction is stored
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+}
+
+// BatchTransactionResponse represents the response of the batch transaction lookup endpoint
+// @Description Lookup results keyed by the requested transaction hash
+type BatchTransactionResponse struct {
+	// Results keyed by transaction hash
+	// @Description One entry per requested hash
+	Results map[string]BatchTransactionResult `json:"results"`
+}
+
+// PoolResponse represents a tracked Uniswap V3 pool
+// @Description Tracked pool and its tokens
+type PoolResponse struct {
+	// Pool name
+	// @Description Human readable pool name
+	Name string `json:"name"`
+
+	// Pool address
+	// @Description Address of the pool contract
+	Address string `json:"address"`
+
+	// Token0 symbol
+	// @Description Symbol of the pool's token0
+	Token0 string `json:"token0"`
+
+	// Token1 symbol
+	// @Description Symbol of the pool's token1
+	Token1 string `json:"token1"`
+
+	// Fee tier
+	// @Description Pool fee in hundredths of a bip, e.g. 500 = 0.05%
+	FeeTier uint32 `json:"fee_tier"`
+
+	// Start block
+	// @Description Block the historical sync of this pool starts from
+	StartBlock uint64 `json:"start_block"`
+}
+
+// FeeStatsQuery represents the query parameters of the fee statistics endpoint
+type FeeStatsQuery struct {
+	// Bucket width such as 15m, 1h or 1d
+	Interval string `form:"interval"`
+
+	// Start of the time range (RFC3339), aligned down to a bucket boundary
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// End of the time range (RFC3339), exclusive
+	To *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// Pool address
+	Pool string `form:"pool"`
+}
+
+// FeeStatsResponse represents bucketed fee statistics
+// @Description Fee statistics per time bucket
+type FeeStatsResponse struct {
+	// Bucket width
+	// @Description Width of each bucket, e.g. 1h0m0s
+	Interval string `json:"interval"`
+
+	// Start of the time range
+	// @Description Start of the first bucket
+	From time.Time `json:"from"`
+
+	// End of the time range
+	// @Description Exclusive end of the time range
+	To time.Time `json:"to"`
+
+	// Buckets
+	// @Description One entry per bucket in chronological order, including buckets without transactions
+	Buckets []FeeStatsBucketResponse `json:"buckets"`
+}
+
+// FeeStatsBucketResponse represents the fee statistics of a single time bucket
+// @Description Count, sum, mean and percentiles of the fees of the transactions in a bucket.
+// @Description ETH figures cover every transaction, USDT figures only the priced ones; statistics are absent for empty buckets.
+type FeeStatsBucketResponse struct {
+	// Bucket start
+	// @Description Start of the bucket; it ends where the next one starts
+	Start time.Time `json:"start"`
+
+	// Transaction count
+	Count int64 `json:"count"`
+
+	// Priced transaction count
+	// @Description Transactions whose USDT fee is known
+	PricedCount int64 `json:"priced_count"`
+
+	// Sum of the fees in ETH
+	SumFeeETH string `json:"sum_fee_eth,omitempty"`
+
+	// Mean fee in ETH
+	MeanFeeETH string `json:"mean_fee_eth,omitempty"`
+
+	// Median fee in ETH
+	MedianFeeETH string `json:"median_fee_eth,omitempty"`
+
+	// 90th percentile fee in ETH
+	P90FeeETH string `json:"p90_fee_eth,omitempty"`
+
+	// 95th percentile fee in ETH
+	P95FeeETH string `json:"p95_fee_eth,omitempty"`
+
+	// 99th percentile fee in ETH
+	P99FeeETH string `json:"p99_fee_eth,omitempty"`
+
+	// Sum of the fees in USDT
+	SumFeeUSDT string `json:"sum_fee_usdt,omitempty"`
+
+	// Mean fee in USDT
+	MeanFeeUSDT string `json:"mean_fee_usdt,omitempty"`
+
+	// Median fee in USDT
+	MedianFeeUSDT string `json:"median_fee_usdt,omitempty"`
+
+	// 90th percentile fee in USDT
+	P90FeeUSDT string `json:"p90_fee_usdt,omitempty"`
+
+	// 95th percentile fee in USDT
+	P95FeeUSDT string `json:"p95_fee_usdt,omitempty"`
+
+	// 99th percentile fee in USDT
+	P99FeeUSDT string `json:"p99_fee_usdt,omitempty"`
+
+	// Average gas price
+	// @Description Average effective gas price in Wei
+	AverageGasPrice string `json:"average_gas_price,omitempty"`
+}
+
+// ReconcileRequest represents the body of the price reconciliation endpoint
+// @Description Block range whose unpriced transactions are repriced
+type ReconcileRequest struct {
+	// First block of the range
+	// @Description Lowest block number to reprice
+	FromBlock *uint64 `json:"from_block" binding:"required"`
+
+	// Last block of the range
+	// @Description Highest block number to reprice
+	ToBlock *uint64 `json:"to_block" binding:"required"`
+
+	// Ignore retry backoff
+	// @Description Also retry transactions whose next attempt is not due yet
+	Force bool `json:"force"`
+}
+
+// ReconcileResponse represents the outcome of a price reconciliation run
+// @Description Number of transactions checked, repriced and still failing
+type ReconcileResponse struct {
+	// Transactions checked
+	// @Description FAILED and PENDING_PRICE transactions found in the range
+	Checked int `json:"checked"`
+
+	// Transactions repriced
+	// @Description Transactions whose fee was computed
+	Repriced int `json:"repriced"`
+
+	// Transactions still failing
+	// @Description Transactions whose price could not be fetched; they are retried later
+	Failed int `json:"failed"`
+}
+
+// RepriceRequest represents the body of the reprice endpoint
+// @Description Range of stored transactions to reprice and the price providers to use
+type RepriceRequest struct {
+	// First block of the range
+	// @Description Lowest block number to reprice
+	FromBlock *uint64 `json:"from_block"`
+
+	// Last block of the range
+	// @Description Highest block number to reprice
+	ToBlock *uint64 `json:"to_block"`
+
+	// Start of the time range
+	// @Description Earliest transaction time to reprice (RFC3339)
+	FromTime *time.Time `json:"from_time"`
+
+	// End of the time range
+	// @Description Latest transaction time to reprice (RFC3339)
+	ToTime *time.Time `json:"to_time"`
+
+	// Price providers
+	// @Description Configured price providers to use in priority order (binance, coinbase, kraken or uniswap); defaults to PRICE_PROVIDERS
+	Providers []string `json:"providers"`
+
+	// Dry run
+	// @Description Report the changes without writing them
+	DryRun bool `json:"dry_run"`
+}
+
+// PriceChangeResponse represents the old and new pricing of a repriced transaction
+// @Description Pricing of a transaction before and after repricing
+type PriceChangeResponse struct {
+	// Transaction hash
+	TxHash string `json:"tx_hash"`
+
+	// Block number
+	BlockNumber uint64 `json:"block_number"`
+
+	// Previous ETH price in USDT
+	OldETHPrice string `json:"old_eth_price"`
+
+	// New ETH price in USDT
+	NewETHPrice string `json:"new_eth_price"`
+
+	// Previous fee in USDT
+	OldFeeUSDT string `json:"old_fee_usdt"`
+
+	// New fee in USDT
+	NewFeeUSDT string `json:"new_fee_usdt"`
+
+	// Previous price source
+	OldPriceSource string `json:"old_price_source"`
+
+	// New price source
+	NewPriceSource string `json:"new_price_source"`
+}
+
+// RepriceResponse represents the outcome of a reprice run
+// @Description Number of transactions checked, changed, unchanged and failed, with the first changes
+type RepriceResponse struct {
+	// Dry run
+	// @Description True when the changes were only reported
+	DryRun bool `json:"dry_run"`
+
+	// Transactions checked
+	// @Description Stored transactions found in the range
+	Checked int `json:"checked"`
+
+	// Transactions changed
+	// @Description Transactions whose price, fee or price source changed
+	Changed int `json:"changed"`
+
+	// Transactions unchanged
+	// @Description Transactions priced the same by the selected providers
+	Unchanged int `json:"unchanged"`
+
+	// Transactions failed
+	// @Description Transactions whose price could not be fetched; they keep their stored price
+	Failed int `json:"failed"`
+
+	// Price changes
+	// @Description The first 100 changed transactions
+	Changes []PriceChangeResponse `json:"changes"`
+}
+
+// WebhookRequest represents the body of the webhook subscription endpoint
+// @Description Endpoint to notify and the rules transactions must all match
+type WebhookRequest struct {
+	// Endpoint URL
+	// @Description HTTP(S) URL the signed JSON payloads are POSTed to
+	URL string `json:"url" binding:"required"`
+
+	// Signing secret
+	// @Description HMAC-SHA256 key of the X-Webhook-Signature header; generated when empty
+	Secret string `json:"secret"`
+
+	// Minimum fee in USDT
+	// @Description Only notify transactions whose fee is at least this many USDT; unpriced transactions never match
+	MinFeeUSDT string `json:"min_fee_usdt"`
+
+	// Minimum gas price in Wei
+	// @Description Only notify transactions whose gas price is at least this many Wei
+	MinGasPrice string `json:"min_gas_price"`
+
+	// Address
+	// @Description Only notify transactions sent by or calling this address
+	Address string `json:"address"`
+}
+
+// WebhookResponse represents a webhook subscription
+// @Description Webhook subscription; the secret is only returned on creation
+type WebhookResponse struct {
+	// Subscription ID
+	ID uint `json:"id"`
+
+	// Endpoint URL
+	URL string `json:"url"`
+
+	// Signing secret
+	Secret string `json:"secret,omitempty"`
+
+	// Minimum fee in USDT
+	MinFeeUSDT string `json:"min_fee_usdt,omitempty"`
+
+	// Minimum gas price in Wei
+	MinGasPrice string `json:"min_gas_price,omitempty"`
+
+	// Sender or called contract
+	Address string `json:"address,omitempty"`
+
+	// Creation time; only transactions mined afterwards are notified
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookListResponse represents the list of webhook subscriptions
+type WebhookListResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// WebhookDeliveriesQuery represents the filters of the webhook deliveries endpoint
+type WebhookDeliveriesQuery struct {
+	// Delivery status
+	Status string `form:"status" binding:"omitempty,oneof=PENDING DELIVERED DEAD"`
+
+	// Page size
+	Limit int `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// WebhookDeliveryResponse represents a webhook delivery
+// @Description Notification of one transaction to a subscription and its delivery attempts
+type WebhookDeliveryResponse struct {
+	// Delivery ID, sent in the X-Webhook-Delivery header
+	ID uint `json:"id"`
+
+	// Subscription ID
+	SubscriptionID uint `json:"subscription_id"`
+
+	// Transaction hash
+	TxHash string `json:"tx_hash"`
+
+	// PENDING, DELIVERED or DEAD
+	Status string `json:"status"`
+
+	// Delivery attempts made
+	Attempts int `json:"attempts"`
+
+	// Error of the last failed attempt
+	LastError string `json:"last_error,omitempty"`
+
+	// Time of the next attempt of pending deliveries
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// Time the receiver acknowledged the delivery
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+
+	// Time the delivery was queued
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDeliveriesResponse represents a list of webhook deliveries
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+// SyncStatusResponse represents the state of live and historical sync
+// @Description Live sync lag behind the chain tip and the progress of every historical sync range
+type SyncStatusResponse struct {
+	// Live sync
+	Live LiveSyncStatusResponse `json:"live"`
+
+	// Historical sync ranges
+	// @Description One entry per sync progress record, ordered by pool and start block
+	Historical []SyncProgressResponse `json:"historical"`
+
+	// Historical sync ETA
+	// @Description Estimated seconds until every running range completes, from the throughput of the last 10 minutes
+	HistoricalETASeconds *int64 `json:"historical_eta_seconds,omitempty"`
+
+	// Whether historical sync is paused
+	HistoricalPaused bool `json:"historical_paused"`
+}
+
+// LiveSyncStatusResponse represents the lag of live sync behind the chain tip
+type LiveSyncStatusResponse struct {
+	// Latest block of the node
+	TipBlock uint64 `json:"tip_block"`
+
+	// Time of the latest block
+	TipTime *time.Time `json:"tip_time,omitempty"`
+
+	// Last block processed by live sync
+	LastTrackedBlock uint64 `json:"last_tracked_block"`
+
+	// Time of the last processed block
+	LastTrackedTime *time.Time `json:"last_tracked_time,omitempty"`
+
+	// Blocks behind the tip
+	LagBlocks uint64 `json:"lag_blocks"`
+
+	// Seconds between the last processed block and the tip
+	LagSeconds int64 `json:"lag_seconds"`
+
+	// Recent processing speed
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+
+	// Estimated seconds to catch up with the tip
+	ETASeconds *int64 `json:"eta_seconds,omitempty"`
+
+	// Whether live sync is paused
+	Paused bool `json:"paused"`
+
+	// Error querying the node
+	Error string `json:"error,omitempty"`
+}
+
+// SyncProgressResponse represents a historical sync range
+type SyncProgressResponse struct {
+	// Sync progress ID
+	ID uint `json:"id"`
+
+	// Pool address
+	PoolAddress string `json:"pool_address"`
+
+	// Range start; blocks after it are synced
+	StartBlock uint64 `json:"start_block"`
+
+	// Last block of the range
+	EndBlock uint64 `json:"end_block"`
+
+	// Last block synced
+	LastProcessedBlock uint64 `json:"last_processed_block"`
+
+	// Share of the range synced
+	PercentComplete float64 `json:"percent_complete"`
+
+	// Transactions stored
+	TransactionsProcessed uint64 `json:"transactions_processed"`
+
+	// RUNNING, COMPLETED, FAILED, PAUSED or CANCELLED
+	Status string `json:"status"`
+
+	// Error of a failed, paused or cancelled range
+	Error string `json:"error,omitempty"`
+
+	// Recent processing speed of a running range
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+
+	// Estimated seconds until a running range completes
+	ETASeconds *int64 `json:"eta_seconds,omitempty"`
+
+	// Creation time
+	CreatedAt time.Time `json:"created_at"`
+
+	// Last update time
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Completion time
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// BackfillRequest represents the body of the backfill endpoint
+// @Description Pool and block range to sync
+type BackfillRequest struct {
+	// Pool address
+	// @Description Address of a tracked pool
+	Pool string `json:"pool" binding:"required"`
+
+	// First block of the range
+	// @Description Lowest block number to sync
+	FromBlock *uint64 `json:"from_block" binding:"required"`
+
+	// Last block of the range
+	// @Description Highest block number to sync, at most the latest block
+	ToBlock *uint64 `json:"to_block" binding:"required"`
+}
+
+// SyncControlResponse represents whether live and historical sync are paused
+type SyncControlResponse struct {
+	// Whether live sync is paused
+	LivePaused bool `json:"live_paused"`
+
+	// Whether historical sync is paused
+	HistoricalPaused bool `json:"historical_paused"`
 }
 
 // ErrorResponse represents the API error response
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"uniswap-fee-tracker/api/handlers"
@@ -9,17 +10,28 @@ import (
 )
 
 type Server struct {
-	router    *gin.Engine
-	txHandler *handlers.TransactionHandler
+	router       *gin.Engine
+	txHandler    *handlers.TransactionHandler
+	poolHandler  *handlers.PoolHandler
+	statsHandler *handlers.StatsHandler
+	syncHandler  *handlers.SyncHandler
+	adminHandler *handlers.AdminHandler
+	adminAPIKey  string
 }
 
-func NewServer(txHandler *handlers.TransactionHandler) *Server {
+func NewServer(txHandler *handlers.TransactionHandler, poolHandler *handlers.PoolHandler, statsHandler *handlers.StatsHandler, syncHandler *handlers.SyncHandler, adminHandler *handlers.AdminHandler, adminAPIKey string) *Server {
 	// Start HTTP server
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	r.Use(Metrics())
 	server := &Server{
-		router:    r,
-		txHandler: txHandler,
+		router:       r,
+		txHandler:    txHandler,
+		poolHandler:  poolHandler,
+		statsHandler: statsHandler,
+		syncHandler:  syncHandler,
+		adminHandler: adminHandler,
+		adminAPIKey:  adminAPIKey,
 	}
 	return server
 }
@@ -31,13 +43,42 @@ func (s *Server) RegisterRoutes() *Server {
 		c.String(200, "Service is healthy")
 	})
 
+	// Prometheus metrics
+	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Swagger documentation
 	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
+		v1.GET("/transactions", s.txHandler.ListTransactions)
+		v1.POST("/transactions/batch", s.txHandler.BatchGetTransactions)
 		v1.GET("/transactions/:txHash", s.txHandler.GetTransactionFee)
+		v1.GET("/addresses/:address/fees", s.txHandler.GetAddressFees)
+		v1.GET("/pools", s.poolHandler.ListPools)
+		v1.GET("/stats/fees", s.statsHandler.GetFeeStats)
+		v1.GET("/sync/status", s.syncHandler.GetSyncStatus)
+		v1.GET("/stream/transactions", s.txHandler.StreamTransactions)
+		v1.GET("/stream/transactions/ws", s.txHandler.StreamTransactionsWebSocket)
+	}
+
+	// Admin routes require the admin API key
+	admin := v1.Group("/admin", AdminAuth(s.adminAPIKey))
+	{
+		admin.POST("/reconcile", s.adminHandler.ReconcilePrices)
+		admin.POST("/reprice", s.adminHandler.Reprice)
+		admin.POST("/webhooks", s.adminHandler.CreateWebhook)
+		admin.GET("/webhooks", s.adminHandler.ListWebhooks)
+		admin.DELETE("/webhooks/:id", s.adminHandler.DeleteWebhook)
+		admin.GET("/webhooks/:id/deliveries", s.adminHandler.ListWebhookDeliveries)
+		admin.POST("/webhooks/:id/deliveries/:deliveryId/retry", s.adminHandler.RetryWebhookDelivery)
+		admin.POST("/sync/live/pause", s.adminHandler.PauseLiveSync)
+		admin.POST("/sync/live/resume", s.adminHandler.ResumeLiveSync)
+		admin.POST("/sync/historical", s.adminHandler.EnqueueBackfill)
+		admin.POST("/sync/historical/pause", s.adminHandler.PauseHistoricalSync)
+		admin.POST("/sync/historical/resume", s.adminHandler.ResumeHistoricalSync)
+		admin.POST("/sync/historical/:id/cancel", s.adminHandler.CancelSyncJob)
 	}
 	return s
 }
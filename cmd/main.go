@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -18,6 +19,8 @@ import (
 	"uniswap-fee-tracker/internal/config"
 	"uniswap-fee-tracker/internal/ethereum"
 	"uniswap-fee-tracker/internal/etherscan"
+	"uniswap-fee-tracker/internal/metrics"
+	"uniswap-fee-tracker/internal/price"
 	"uniswap-fee-tracker/internal/syncer"
 )
 
@@ -28,6 +31,49 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	service := newService(cfg)
+	prometheus.MustRegister(metrics.NewSyncProgressCollector(service.SyncProgressMetrics))
+
+	// "reprice" recomputes stored fees for a range and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "reprice" {
+		if err := runReprice(service, os.Args[2:]); err != nil {
+			log.Fatalf("Reprice failed: %v", err)
+		}
+		return
+	}
+
+	// Start historical sync from each pool's start block, followed by live sync
+	log.Printf("Starting sync of %d tracked pools", len(cfg.Pools))
+	if err := service.StartSync(context.Background()); err != nil {
+		log.Fatalf("Failed to start historical sync: %v", err)
+	}
+
+	txHandler := handlers.NewTransactionHandler(service)
+	poolHandler := handlers.NewPoolHandler(service)
+	statsHandler := handlers.NewStatsHandler(service)
+	syncHandler := handlers.NewSyncHandler(service)
+	adminHandler := handlers.NewAdminHandler(service)
+
+	// Create API server
+	go func() {
+		routes := api.NewServer(txHandler, poolHandler, statsHandler, syncHandler, adminHandler, cfg.AdminAPIKey).RegisterRoutes()
+
+		log.Println("Starting server on ", cfg.Port)
+		if err := routes.Start(cfg.Port); err != nil {
+			log.Printf("HTTP server error: %v", err)
+		}
+	}()
+
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	<-sigChan
+	log.Println("Shutting down gracefully...")
+}
+
+// newService connects to the database and the external APIs, migrates the schema and creates the sync service
+func newService(cfg *config.Config) *syncer.Service {
 	// Initialize logger
 	gormLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags),
@@ -49,42 +95,24 @@ func main() {
 
 	// Initialize clients and repository
 	ethClient := etherscan.NewClient(&cfg.EtherscanConfig)
-	binClient := binance.NewClient(&cfg.BinanceConfig)
 	nodeClient, err := ethereum.NewClient(&cfg.EthereumConfig)
 	if err != nil {
 		log.Fatalf("Failed to connect to Ethereum node: %v", err)
 	}
+	candles := binance.NewCandleStore(db)
+	priceProvider, err := price.New(cfg, nodeClient, candles)
+	if err != nil {
+		log.Fatalf("Failed to create price providers: %v", err)
+	}
 	repo := syncer.NewRepository(db)
 
 	// Auto migrate database schema
 	if err := repo.AutoMigrate(); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
-
-	service := syncer.NewService(cfg, ethClient, binClient, nodeClient, repo)
-
-	// Start historical sync from Uniswap v3 deployment block
-	log.Printf("Starting historical sync from block %d", cfg.UniswapStartBlock)
-	if err := service.StartSync(context.Background(), cfg.UniswapStartBlock); err != nil {
-		log.Fatalf("Failed to start historical sync: %v", err)
+	if err := candles.AutoMigrate(); err != nil {
+		log.Fatalf("Failed to migrate price candles: %v", err)
 	}
 
-	txHandler := handlers.NewTransactionHandler(service)
-
-	// Create API server
-	go func() {
-		routes := api.NewServer(txHandler).RegisterRoutes()
-
-		log.Println("Starting server on ", cfg.Port)
-		if err := routes.Start(cfg.Port); err != nil {
-			log.Printf("HTTP server error: %v", err)
-		}
-	}()
-
-	// Handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	<-sigChan
-	log.Println("Shutting down gracefully...")
+	return syncer.NewService(cfg, ethClient, priceProvider, nodeClient, repo)
 }
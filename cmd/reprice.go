@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"uniswap-fee-tracker/internal/syncer"
+)
+
+// runReprice parses the reprice subcommand flags, reprices the selected range and prints the changes
+func runReprice(service *syncer.Service, args []string) error {
+	var opts syncer.RepriceOptions
+	var providers string
+
+	flags := flag.NewFlagSet("reprice", flag.ContinueOnError)
+	flags.Func("from-block", "lowest block number to reprice", blockFlag(&opts.FromBlock))
+	flags.Func("to-block", "highest block number to reprice", blockFlag(&opts.ToBlock))
+	flags.Func("from-time", "earliest transaction time to reprice (RFC3339)", timeFlag(&opts.FromTime))
+	flags.Func("to-time", "latest transaction time to reprice (RFC3339)", timeFlag(&opts.ToTime))
+	flags.StringVar(&providers, "providers", "", "comma separated price providers to use in priority order (default PRICE_PROVIDERS)")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "report the changes without writing them")
+	flags.IntVar(&opts.BatchSize, "batch-size", 500, "transactions loaded and written per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	for _, name := range strings.Split(providers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Providers = append(opts.Providers, name)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := service.Reprice(ctx, opts)
+	if err != nil {
+		return err
+	}
+	printRepriceReport(report)
+	return nil
+}
+
+// printRepriceReport writes the report summary and its changes as a table to stdout
+func printRepriceReport(report *syncer.RepriceReport) {
+	action := "Repriced"
+	if report.DryRun {
+		action = "Dry run, would reprice"
+	}
+	fmt.Printf("%s %d of %d transactions (%d unchanged, %d failed)\n",
+		action, report.Changed, report.Checked, report.Unchanged, report.Failed)
+	if len(report.Changes) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TX HASH\tBLOCK\tETH PRICE\tFEE USDT\tSOURCE")
+	for _, change := range report.Changes {
+		fmt.Fprintf(w, "%s\t%d\t%s -> %s\t%s -> %s\t%s -> %s\n",
+			change.TxHash, change.BlockNumber,
+			formatAmount(change.OldETHPrice), formatAmount(change.NewETHPrice),
+			formatAmount(change.OldFeeUSDT), formatAmount(change.NewFeeUSDT),
+			orNone(change.OldSource), orNone(change.NewSource))
+	}
+	w.Flush()
+	if report.Changed > len(report.Changes) {
+		fmt.Printf("... and %d more\n", report.Changed-len(report.Changes))
+	}
+}
+
+// blockFlag parses a block number flag into an optional value
+func blockFlag(target **uint64) func(string) error {
+	return func(value string) error {
+		block, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*target = &block
+		return nil
+	}
+}
+
+// timeFlag parses an RFC3339 time flag into an optional value
+func timeFlag(target **time.Time) func(string) error {
+	return func(value string) error {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		*target = &t
+		return nil
+	}
+}
+
+// formatAmount returns a price or fee with 6 decimals, or "-" when unset
+func formatAmount(v *big.Float) string {
+	if v == nil {
+		return "-"
+	}
+	return v.Text('f', 6)
+}
+
+// orNone returns the price source, or "-" when unset
+func orNone(source string) string {
+	if source == "" {
+		return "-"
+	}
+	return source
+}
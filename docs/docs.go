@@ -15,9 +15,1196 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/v1/addresses/{address}/fees": {
+            "get": {
+                "description": "Sum up the fees of the transactions an address sent to the tracked pools, optionally over a time window.\nWith transactions=true a page of those transactions is returned as well, paginated like the search endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "addresses"
+                ],
+                "summary": "Get the fees paid by an address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sender address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest transaction time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest transaction time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trailing window such as 24h or 720h, instead of from_time",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return a page of the address's transactions",
+                        "name": "transactions",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "block_number",
+                            "timestamp",
+                            "fee_usdt"
+                        ],
+                        "type": "string",
+                        "default": "block_number",
+                        "description": "Sort field of the transactions",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order of the transactions",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AddressFeesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/reconcile": {
+            "post": {
+                "description": "Refetch ETH prices for FAILED and PENDING_PRICE transactions in a block range.\nTransactions still backing off after a failed attempt are skipped unless force is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reprice failed transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Block range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconcileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReconcileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/reprice": {
+            "post": {
+                "description": "Recompute the ETH price and USDT fee of every stored transaction in a block or time range\nwith the given price providers. With dry_run the changes are reported without being written.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reprice stored transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Range and price providers",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RepriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RepriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/historical": {
+            "post": {
+                "description": "Queue a historical sync of a tracked pool over a block range on the historical sync workers.\nTransactions already stored in the range are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Schedule a backfill",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Pool and block range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BackfillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/historical/pause": {
+            "post": {
+                "description": "Stop every running historical sync range after its current batch and keep queued ranges from starting.\nPaused ranges continue from their last processed block once resumed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause historical sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncControlResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/historical/resume": {
+            "post": {
+                "description": "Requeue every incomplete historical sync range after a pause.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume historical sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncControlResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/historical/{id}/cancel": {
+            "post": {
+                "description": "Stop a running range after its current batch, or keep a queued, paused or failed one from running again.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cancel a historical sync range",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sync progress ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/live/pause": {
+            "post": {
+                "description": "Stop live sync after the block being processed. Pausing a paused live sync does nothing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause live sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncControlResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sync/live/resume": {
+            "post": {
+                "description": "Restart a paused live sync from the last tracked block, catching up on the blocks mined meanwhile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume live sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncControlResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks": {
+            "get": {
+                "description": "List every webhook subscription. Secrets are not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Notify an endpoint of every newly stored transaction matching all of the given rules.\nPayloads are signed with HMAC-SHA256 over \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" in the X-Webhook-Signature header.\nThe secret is only returned in this response; it is generated when not given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Subscribe a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Endpoint and rules",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/{id}": {
+            "delete": {
+                "description": "Remove a webhook subscription along with its pending and past deliveries.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "List the most recent deliveries of a webhook, e.g. status=DEAD for the dead-lettered ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "DELIVERED",
+                            "DEAD"
+                        ],
+                        "type": "string",
+                        "description": "Delivery status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of deliveries (max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/{id}/deliveries/{deliveryId}/retry": {
+            "post": {
+                "description": "Requeue a DEAD delivery for immediate delivery with a fresh attempt budget.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Retry a dead-lettered webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pools": {
+            "get": {
+                "description": "List the Uniswap V3 pools whose transactions are tracked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pools"
+                ],
+                "summary": "List tracked pools",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PoolResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/fees": {
+            "get": {
+                "description": "Count, sum, mean, median and p90/p95/p99 of the transaction fees in ETH and USDT, and the average gas price, per time bucket.\nBuckets are aligned to the Unix epoch; at most 1000 buckets are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get fee statistics over time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "1h",
+                        "description": "Bucket width such as 15m, 1h or 1d",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range (RFC3339); defaults to 24 hours before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exclusive end of the time range (RFC3339); defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pool address",
+                        "name": "pool",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeeStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream/transactions": {
+            "get": {
+                "description": "Push each transaction as soon as live sync stores it, as Server-Sent Events named \"transaction\".\nClients that fall behind are sent an \"error\" event and disconnected; they should reconnect and backfill with the search endpoint.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream newly processed transactions (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Minimum fee in USDT",
+                        "name": "min_fee_usdt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pool address",
+                        "name": "pool",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender or called contract address",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream/transactions/ws": {
+            "get": {
+                "description": "Push each transaction as soon as live sync stores it as a JSON text message.\nClients that fall behind are closed with status 1008 (policy violation) and should reconnect.",
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream newly processed transactions (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Minimum fee in USDT",
+                        "name": "min_fee_usdt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pool address",
+                        "name": "pool",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sender or called contract address",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sync/status": {
+            "get": {
+                "description": "Report how far live sync trails the chain tip and the progress of every historical sync range,\nwith ETAs estimated from the throughput of the last 10 minutes.\nNode errors are reported in live.error rather than failing the request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get sync status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SyncStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transactions": {
+            "get": {
+                "description": "List stored transactions filtered by block range, time range, fee, status, finality, pool, sender, target contract and revert status, using cursor-based pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Search transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lowest block number to include",
+                        "name": "from_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highest block number to include",
+                        "name": "to_block",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest transaction time (RFC3339)",
+                        "name": "from_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest transaction time (RFC3339)",
+                        "name": "to_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum fee in USDT",
+                        "name": "min_fee_usdt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum fee in USDT",
+                        "name": "max_fee_usdt",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PROCESSED",
+                            "PENDING_PRICE",
+                            "FAILED"
+                        ],
+                        "type": "string",
+                        "description": "Processing status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "UNCONFIRMED",
+                            "FINALIZED"
+                        ],
+                        "type": "string",
+                        "description": "Finality status",
+                        "name": "finality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pool address",
+                        "name": "pool",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction sender address",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Address of the contract called by the transaction, e.g. a router",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only reverted (true) or successful (false) transactions",
+                        "name": "reverted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "block_number",
+                            "timestamp",
+                            "fee_usdt"
+                        ],
+                        "type": "string",
+                        "default": "block_number",
+                        "description": "Sort field",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/transactions/batch": {
+            "post": {
+                "description": "Look up fees for up to 1000 transaction hashes in a single request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Look up many transactions",
+                "parameters": [
+                    {
+                        "description": "Transaction hashes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchTransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/transactions/{txHash}": {
             "get": {
-                "description": "Get the transaction fee in USDT for a specific Uniswap WETH-USDC transaction",
+                "description": "Get the transaction fee in USDT for a specific transaction of a tracked Uniswap pool.\nTransactions not yet reached by the sync are fetched from the node and stored on demand.\nWhen the ETH price is unavailable they are stored FAILED, without USDT figures, until the reconciler prices them.\nThe fee is also converted to the currencies configured in FEE_CURRENCIES; conversions that failed or\nof currencies added after the transaction was stored are filled in by the price reconciler.",
                 "consumes": [
                     "application/json"
                 ],
@@ -35,39 +1222,713 @@ const docTemplate = `{
                         "name": "txHash",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the fee in this configured currency (e.g. EUR); all configured currencies are returned by default",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AddressFeesResponse": {
+            "description": "Fee totals of the transactions an address sent to the tracked pools, with an optional page of transactions",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address\n@Description Sender address, lowercase",
+                    "type": "string"
+                },
+                "average_fee_eth": {
+                    "description": "Average fee in ETH\n@Description Average transaction fee in ETH, absent without transactions",
+                    "type": "string"
+                },
+                "average_fee_usdt": {
+                    "description": "Average fee in USDT\n@Description Average fee of the priced transactions in USDT, absent without priced transactions",
+                    "type": "string"
+                },
+                "first_seen": {
+                    "description": "First seen\n@Description Time of the address's first transaction in the window",
+                    "type": "string"
+                },
+                "from_time": {
+                    "description": "Start of the window\n@Description Earliest transaction time included, absent when unbounded",
+                    "type": "string"
+                },
+                "last_seen": {
+                    "description": "Last seen\n@Description Time of the address's last transaction in the window",
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "description": "Cursor for the next page\n@Description Pass as the cursor parameter to fetch the next page of transactions; empty on the last page",
+                    "type": "string"
+                },
+                "priced_count": {
+                    "description": "Priced transaction count\n@Description Transactions whose USDT fee is known; the USDT figures only cover these",
+                    "type": "integer"
+                },
+                "reverted_count": {
+                    "description": "Reverted transaction count\n@Description Reverted transactions, whose fee was paid without swapping",
+                    "type": "integer"
+                },
+                "to_time": {
+                    "description": "End of the window\n@Description Latest transaction time included, absent when unbounded",
+                    "type": "string"
+                },
+                "total_fee_eth": {
+                    "description": "Total fee in ETH\n@Description Sum of the transaction fees in ETH",
+                    "type": "string"
+                },
+                "total_fee_usdt": {
+                    "description": "Total fee in USDT\n@Description Sum of the transaction fees in USDT",
+                    "type": "string"
+                },
+                "transaction_count": {
+                    "description": "Transaction count\n@Description Transactions sent by the address in the window, including reverted and unpriced ones",
+                    "type": "integer"
+                },
+                "transactions": {
+                    "description": "Transactions\n@Description Page of the address's transactions, only returned when transactions=true",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionResponse"
+                    }
+                }
+            }
+        },
+        "models.BackfillRequest": {
+            "description": "Pool and block range to sync",
+            "type": "object",
+            "required": [
+                "from_block",
+                "pool",
+                "to_block"
+            ],
+            "properties": {
+                "from_block": {
+                    "description": "First block of the range\n@Description Lowest block number to sync",
+                    "type": "integer"
+                },
+                "pool": {
+                    "description": "Pool address\n@Description Address of a tracked pool",
+                    "type": "string"
+                },
+                "to_block": {
+                    "description": "Last block of the range\n@Description Highest block number to sync, at most the latest block",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchResultStatus": {
+            "type": "string",
+            "enum": [
+                "FOUND",
+                "PENDING",
+                "NOT_FOUND"
+            ],
+            "x-enum-varnames": [
+                "BatchResultFound",
+                "BatchResultPending",
+                "BatchResultNotFound"
+            ]
+        },
+        "models.BatchTransactionRequest": {
+            "description": "List of transaction hashes to look up",
+            "type": "object",
+            "required": [
+                "tx_hashes"
+            ],
+            "properties": {
+                "tx_hashes": {
+                    "description": "Transaction hashes\n@Description Transaction hashes to look up (at most 1000)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BatchTransactionResponse": {
+            "description": "Lookup results keyed by the requested transaction hash",
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "Results keyed by transaction hash\n@Description One entry per requested hash",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.BatchTransactionResult"
+                    }
+                }
+            }
+        },
+        "models.BatchTransactionResult": {
+            "description": "Lookup outcome for one transaction hash",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Lookup status\n@Description FOUND, PENDING or NOT_FOUND",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BatchResultStatus"
+                        }
+                    ]
+                },
+                "transaction": {
+                    "description": "Transaction details\n@Description Present when the transaction is stored",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TransactionResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "description": "Error response when the API request fails",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error message\n@Description Description of what went wrong",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeeResponse": {
+            "description": "Fee and ETH price in a configured currency",
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "Currency\n@Description Currency code, e.g. EUR or BTC",
+                    "type": "string"
+                },
+                "eth_price": {
+                    "description": "ETH price\n@Description Price of 1 ETH in the currency at transaction time",
+                    "type": "string"
+                },
+                "fee": {
+                    "description": "Fee\n@Description Transaction fee in the currency",
+                    "type": "string"
+                },
+                "price_source": {
+                    "description": "Price source\n@Description Price provider the conversion rate was taken from",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeeStatsBucketResponse": {
+            "description": "Count, sum, mean and percentiles of the fees of the transactions in a bucket. ETH figures cover every transaction, USDT figures only the priced ones; statistics are absent for empty buckets.",
+            "type": "object",
+            "properties": {
+                "average_gas_price": {
+                    "description": "Average gas price\n@Description Average effective gas price in Wei",
+                    "type": "string"
+                },
+                "count": {
+                    "description": "Transaction count",
+                    "type": "integer"
+                },
+                "mean_fee_eth": {
+                    "description": "Mean fee in ETH",
+                    "type": "string"
+                },
+                "mean_fee_usdt": {
+                    "description": "Mean fee in USDT",
+                    "type": "string"
+                },
+                "median_fee_eth": {
+                    "description": "Median fee in ETH",
+                    "type": "string"
+                },
+                "median_fee_usdt": {
+                    "description": "Median fee in USDT",
+                    "type": "string"
+                },
+                "p90_fee_eth": {
+                    "description": "90th percentile fee in ETH",
+                    "type": "string"
+                },
+                "p90_fee_usdt": {
+                    "description": "90th percentile fee in USDT",
+                    "type": "string"
+                },
+                "p95_fee_eth": {
+                    "description": "95th percentile fee in ETH",
+                    "type": "string"
+                },
+                "p95_fee_usdt": {
+                    "description": "95th percentile fee in USDT",
+                    "type": "string"
+                },
+                "p99_fee_eth": {
+                    "description": "99th percentile fee in ETH",
+                    "type": "string"
+                },
+                "p99_fee_usdt": {
+                    "description": "99th percentile fee in USDT",
+                    "type": "string"
+                },
+                "priced_count": {
+                    "description": "Priced transaction count\n@Description Transactions whose USDT fee is known",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Bucket start\n@Description Start of the bucket; it ends where the next one starts",
+                    "type": "string"
+                },
+                "sum_fee_eth": {
+                    "description": "Sum of the fees in ETH",
+                    "type": "string"
+                },
+                "sum_fee_usdt": {
+                    "description": "Sum of the fees in USDT",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeeStatsResponse": {
+            "description": "Fee statistics per time bucket",
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Buckets\n@Description One entry per bucket in chronological order, including buckets without transactions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeeStatsBucketResponse"
+                    }
+                },
+                "from": {
+                    "description": "Start of the time range\n@Description Start of the first bucket",
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "Bucket width\n@Description Width of each bucket, e.g. 1h0m0s",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "End of the time range\n@Description Exclusive end of the time range",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LiveSyncStatusResponse": {
+            "type": "object",
+            "properties": {
+                "blocks_per_second": {
+                    "description": "Recent processing speed",
+                    "type": "number"
+                },
+                "error": {
+                    "description": "Error querying the node",
+                    "type": "string"
+                },
+                "eta_seconds": {
+                    "description": "Estimated seconds to catch up with the tip",
+                    "type": "integer"
+                },
+                "lag_blocks": {
+                    "description": "Blocks behind the tip",
+                    "type": "integer"
+                },
+                "lag_seconds": {
+                    "description": "Seconds between the last processed block and the tip",
+                    "type": "integer"
+                },
+                "last_tracked_block": {
+                    "description": "Last block processed by live sync",
+                    "type": "integer"
+                },
+                "last_tracked_time": {
+                    "description": "Time of the last processed block",
+                    "type": "string"
+                },
+                "paused": {
+                    "description": "Whether live sync is paused",
+                    "type": "boolean"
+                },
+                "tip_block": {
+                    "description": "Latest block of the node",
+                    "type": "integer"
+                },
+                "tip_time": {
+                    "description": "Time of the latest block",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PoolResponse": {
+            "description": "Tracked pool and its tokens",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Pool address\n@Description Address of the pool contract",
+                    "type": "string"
+                },
+                "fee_tier": {
+                    "description": "Fee tier\n@Description Pool fee in hundredths of a bip, e.g. 500 = 0.05%",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Pool name\n@Description Human readable pool name",
+                    "type": "string"
+                },
+                "start_block": {
+                    "description": "Start block\n@Description Block the historical sync of this pool starts from",
+                    "type": "integer"
+                },
+                "token0": {
+                    "description": "Token0 symbol\n@Description Symbol of the pool's token0",
+                    "type": "string"
+                },
+                "token1": {
+                    "description": "Token1 symbol\n@Description Symbol of the pool's token1",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceChangeResponse": {
+            "description": "Pricing of a transaction before and after repricing",
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "description": "Block number",
+                    "type": "integer"
+                },
+                "new_eth_price": {
+                    "description": "New ETH price in USDT",
+                    "type": "string"
+                },
+                "new_fee_usdt": {
+                    "description": "New fee in USDT",
+                    "type": "string"
+                },
+                "new_price_source": {
+                    "description": "New price source",
+                    "type": "string"
+                },
+                "old_eth_price": {
+                    "description": "Previous ETH price in USDT",
+                    "type": "string"
+                },
+                "old_fee_usdt": {
+                    "description": "Previous fee in USDT",
+                    "type": "string"
+                },
+                "old_price_source": {
+                    "description": "Previous price source",
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "description": "Transaction hash",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReconcileRequest": {
+            "description": "Block range whose unpriced transactions are repriced",
+            "type": "object",
+            "required": [
+                "from_block",
+                "to_block"
+            ],
+            "properties": {
+                "force": {
+                    "description": "Ignore retry backoff\n@Description Also retry transactions whose next attempt is not due yet",
+                    "type": "boolean"
+                },
+                "from_block": {
+                    "description": "First block of the range\n@Description Lowest block number to reprice",
+                    "type": "integer"
+                },
+                "to_block": {
+                    "description": "Last block of the range\n@Description Highest block number to reprice",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReconcileResponse": {
+            "description": "Number of transactions checked, repriced and still failing",
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Transactions checked\n@Description FAILED and PENDING_PRICE transactions found in the range",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Transactions still failing\n@Description Transactions whose price could not be fetched; they are retried later",
+                    "type": "integer"
+                },
+                "repriced": {
+                    "description": "Transactions repriced\n@Description Transactions whose fee was computed",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RepriceRequest": {
+            "description": "Range of stored transactions to reprice and the price providers to use",
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Dry run\n@Description Report the changes without writing them",
+                    "type": "boolean"
+                },
+                "from_block": {
+                    "description": "First block of the range\n@Description Lowest block number to reprice",
+                    "type": "integer"
+                },
+                "from_time": {
+                    "description": "Start of the time range\n@Description Earliest transaction time to reprice (RFC3339)",
+                    "type": "string"
+                },
+                "providers": {
+                    "description": "Price providers\n@Description Configured price providers to use in priority order (binance, coinbase, kraken or uniswap); defaults to PRICE_PROVIDERS",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.TransactionResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
+                },
+                "to_block": {
+                    "description": "Last block of the range\n@Description Highest block number to reprice",
+                    "type": "integer"
+                },
+                "to_time": {
+                    "description": "End of the time range\n@Description Latest transaction time to reprice (RFC3339)",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RepriceResponse": {
+            "description": "Number of transactions checked, changed, unchanged and failed, with the first changes",
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Transactions changed\n@Description Transactions whose price, fee or price source changed",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Price changes\n@Description The first 100 changed transactions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceChangeResponse"
                     }
+                },
+                "checked": {
+                    "description": "Transactions checked\n@Description Stored transactions found in the range",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "Dry run\n@Description True when the changes were only reported",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Transactions failed\n@Description Transactions whose price could not be fetched; they keep their stored price",
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "description": "Transactions unchanged\n@Description Transactions priced the same by the selected providers",
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "description": "Error response when the API request fails",
+        },
+        "models.SwapResponse": {
+            "description": "Swap amounts and implied execution price decoded from a pool Swap event",
+            "type": "object",
+            "properties": {
+                "amount0": {
+                    "description": "Token0 amount\n@Description Decimal-adjusted token0 amount; positive when paid into the pool, negative when paid out",
+                    "type": "string"
+                },
+                "amount1": {
+                    "description": "Token1 amount\n@Description Decimal-adjusted token1 amount; positive when paid into the pool, negative when paid out",
+                    "type": "string"
+                },
+                "execution_price": {
+                    "description": "Execution price\n@Description Price implied by the swapped amounts, expressed in price_unit",
+                    "type": "string"
+                },
+                "log_index": {
+                    "description": "Log index\n@Description Position of the Swap event within the block",
+                    "type": "integer"
+                },
+                "pool_address": {
+                    "description": "Pool address\n@Description Address of the pool that emitted the event",
+                    "type": "string"
+                },
+                "price_unit": {
+                    "description": "Price unit\n@Description Unit of execution_price, e.g. USDC/WETH",
+                    "type": "string"
+                },
+                "recipient": {
+                    "description": "Swap recipient\n@Description Address that received the output tokens",
+                    "type": "string"
+                },
+                "sender": {
+                    "description": "Swap sender\n@Description Address that initiated the swap (usually a router)",
+                    "type": "string"
+                },
+                "sqrt_price_x96": {
+                    "description": "Pool sqrt price after the swap\n@Description sqrtPriceX96 of the pool after the swap",
+                    "type": "string"
+                },
+                "tick": {
+                    "description": "Pool tick after the swap\n@Description Current tick of the pool after the swap",
+                    "type": "integer"
+                },
+                "token0": {
+                    "description": "Token0 symbol\n@Description Symbol of the pool's token0",
+                    "type": "string"
+                },
+                "token1": {
+                    "description": "Token1 symbol\n@Description Symbol of the pool's token1",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SyncControlResponse": {
+            "type": "object",
+            "properties": {
+                "historical_paused": {
+                    "description": "Whether historical sync is paused",
+                    "type": "boolean"
+                },
+                "live_paused": {
+                    "description": "Whether live sync is paused",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SyncProgressResponse": {
             "type": "object",
             "properties": {
+                "blocks_per_second": {
+                    "description": "Recent processing speed of a running range",
+                    "type": "number"
+                },
+                "completed_at": {
+                    "description": "Completion time",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Creation time",
+                    "type": "string"
+                },
+                "end_block": {
+                    "description": "Last block of the range",
+                    "type": "integer"
+                },
                 "error": {
-                    "description": "Error message\n@Description Description of what went wrong",
+                    "description": "Error of a failed, paused or cancelled range",
+                    "type": "string"
+                },
+                "eta_seconds": {
+                    "description": "Estimated seconds until a running range completes",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Sync progress ID",
+                    "type": "integer"
+                },
+                "last_processed_block": {
+                    "description": "Last block synced",
+                    "type": "integer"
+                },
+                "percent_complete": {
+                    "description": "Share of the range synced",
+                    "type": "number"
+                },
+                "pool_address": {
+                    "description": "Pool address",
+                    "type": "string"
+                },
+                "start_block": {
+                    "description": "Range start; blocks after it are synced",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "RUNNING, COMPLETED, FAILED, PAUSED or CANCELLED",
+                    "type": "string"
+                },
+                "transactions_processed": {
+                    "description": "Transactions stored",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Last update time",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SyncStatusResponse": {
+            "description": "Live sync lag behind the chain tip and the progress of every historical sync range",
+            "type": "object",
+            "properties": {
+                "historical": {
+                    "description": "Historical sync ranges\n@Description One entry per sync progress record, ordered by pool and start block",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncProgressResponse"
+                    }
+                },
+                "historical_eta_seconds": {
+                    "description": "Historical sync ETA\n@Description Estimated seconds until every running range completes, from the throughput of the last 10 minutes",
+                    "type": "integer"
+                },
+                "historical_paused": {
+                    "description": "Whether historical sync is paused",
+                    "type": "boolean"
+                },
+                "live": {
+                    "description": "Live sync",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LiveSyncStatusResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.TransactionListResponse": {
+            "description": "Paginated list of transactions matching the search filters",
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Cursor for the next page\n@Description Pass as the cursor parameter to fetch the next page; empty on the last page",
                     "type": "string"
+                },
+                "transactions": {
+                    "description": "Transactions in this page\n@Description Transactions matching the filters, in the requested order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionResponse"
+                    }
                 }
             }
         },
@@ -75,10 +1936,22 @@ const docTemplate = `{
             "description": "Response containing transaction details including gas fees",
             "type": "object",
             "properties": {
+                "base_fee_per_gas": {
+                    "description": "Base fee per gas\n@Description Base fee of the transaction's block in Wei; 0 before the London fork",
+                    "type": "string"
+                },
                 "block_number": {
                     "description": "Block number\n@Description The block number in which this transaction was included",
                     "type": "integer"
                 },
+                "burned_fee_eth": {
+                    "description": "Burned fee in ETH\n@Description Part of the fee burned as base fee (gas used * base fee), in ETH",
+                    "type": "string"
+                },
+                "burned_fee_usdt": {
+                    "description": "Burned fee in USDT\n@Description Part of the fee burned as base fee, in USDT",
+                    "type": "string"
+                },
                 "eth_price": {
                     "description": "ETH price in USDT\n@Description ETH/USDT price at transaction time",
                     "type": "string"
@@ -91,6 +1964,25 @@ const docTemplate = `{
                     "description": "Fee in USDT\n@Description Transaction fee converted to USDT",
                     "type": "string"
                 },
+                "fees": {
+                    "description": "Fees in other currencies\n@Description Fee converted to each currency configured in FEE_CURRENCIES, or to the requested currency only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeeResponse"
+                    }
+                },
+                "finality": {
+                    "description": "Finality\n@Description UNCONFIRMED while the block can still be reorganized away or the fee is unpriced (the fee may change), FINALIZED afterwards",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/syncer.FinalityStatus"
+                        }
+                    ]
+                },
+                "from": {
+                    "description": "Sender\n@Description Address that sent the transaction",
+                    "type": "string"
+                },
                 "gas_price": {
                     "description": "Gas price\n@Description Price per unit of gas in Wei",
                     "type": "string"
@@ -99,6 +1991,38 @@ const docTemplate = `{
                     "description": "Gas used\n@Description Amount of gas used by this transaction",
                     "type": "string"
                 },
+                "max_fee_per_gas": {
+                    "description": "Max fee per gas\n@Description maxFeePerGas of EIP-1559 transactions in Wei",
+                    "type": "string"
+                },
+                "max_priority_fee_per_gas": {
+                    "description": "Max priority fee per gas\n@Description maxPriorityFeePerGas of EIP-1559 transactions in Wei",
+                    "type": "string"
+                },
+                "nonce": {
+                    "description": "Nonce\n@Description Nonce of the transaction sender",
+                    "type": "integer"
+                },
+                "pool_address": {
+                    "description": "Pool address\n@Description First tracked pool the transaction interacted with",
+                    "type": "string"
+                },
+                "pool_eth_price": {
+                    "description": "On-chain ETH price\n@Description ETH price derived from the sqrtPriceX96 of the tracked WETH/stablecoin pool at the transaction's block",
+                    "type": "string"
+                },
+                "price_interval": {
+                    "description": "Price interval\n@Description Candle interval the ETH price was taken from (1s, 1m, 5m or 1h); coarser intervals are used for dates without 1s data",
+                    "type": "string"
+                },
+                "price_source": {
+                    "description": "Price source\n@Description Price provider the ETH price was taken from (binance, coinbase, kraken or uniswap)",
+                    "type": "string"
+                },
+                "reverted": {
+                    "description": "Reverted\n@Description True when the transaction reverted; its fee was paid without executing a swap",
+                    "type": "boolean"
+                },
                 "status": {
                     "description": "Transaction status\n@Description Current processing status of the transaction",
                     "allOf": [
@@ -107,16 +2031,181 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "swaps": {
+                    "description": "Decoded swaps\n@Description Uniswap V3 Swap events emitted by the pool in this transaction",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SwapResponse"
+                    }
+                },
                 "timestamp": {
                     "description": "Transaction timestamp\n@Description When this transaction was processed",
                     "type": "string"
                 },
+                "tip_fee_eth": {
+                    "description": "Tip in ETH\n@Description Priority fee paid to the block builder (gas used * (gas price - base fee)), in ETH",
+                    "type": "string"
+                },
+                "tip_fee_usdt": {
+                    "description": "Tip in USDT\n@Description Priority fee paid to the block builder, in USDT",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Target contract\n@Description Contract called by the transaction, usually a router or aggregator; empty for contract creations",
+                    "type": "string"
+                },
+                "transaction_index": {
+                    "description": "Transaction index\n@Description Position of the transaction within its block",
+                    "type": "integer"
+                },
                 "tx_hash": {
                     "description": "Transaction hash\n@Description Unique identifier of the transaction",
                     "type": "string"
+                },
+                "tx_type": {
+                    "description": "Transaction type\n@Description EIP-2718 transaction type: 0 legacy, 1 access list, 2 EIP-1559, 3 blob, 4 set code",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDeliveryResponse"
+                    }
+                }
+            }
+        },
+        "models.WebhookDeliveryResponse": {
+            "description": "Notification of one transaction to a subscription and its delivery attempts",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Delivery attempts made",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Time the delivery was queued",
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "description": "Time the receiver acknowledged the delivery",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Delivery ID, sent in the X-Webhook-Delivery header",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Error of the last failed attempt",
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "Time of the next attempt of pending deliveries",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "PENDING, DELIVERED or DEAD",
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "description": "Subscription ID",
+                    "type": "integer"
+                },
+                "tx_hash": {
+                    "description": "Transaction hash",
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookListResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookResponse"
+                    }
+                }
+            }
+        },
+        "models.WebhookRequest": {
+            "description": "Endpoint to notify and the rules transactions must all match",
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Address\n@Description Only notify transactions sent by or calling this address",
+                    "type": "string"
+                },
+                "min_fee_usdt": {
+                    "description": "Minimum fee in USDT\n@Description Only notify transactions whose fee is at least this many USDT; unpriced transactions never match",
+                    "type": "string"
+                },
+                "min_gas_price": {
+                    "description": "Minimum gas price in Wei\n@Description Only notify transactions whose gas price is at least this many Wei",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Signing secret\n@Description HMAC-SHA256 key of the X-Webhook-Signature header; generated when empty",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Endpoint URL\n@Description HTTP(S) URL the signed JSON payloads are POSTed to",
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookResponse": {
+            "description": "Webhook subscription; the secret is only returned on creation",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Sender or called contract",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Creation time; only transactions mined afterwards are notified",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Subscription ID",
+                    "type": "integer"
+                },
+                "min_fee_usdt": {
+                    "description": "Minimum fee in USDT",
+                    "type": "string"
+                },
+                "min_gas_price": {
+                    "description": "Minimum gas price in Wei",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Signing secret",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Endpoint URL",
+                    "type": "string"
                 }
             }
         },
+        "syncer.FinalityStatus": {
+            "type": "string",
+            "enum": [
+                "UNCONFIRMED",
+                "FINALIZED"
+            ],
+            "x-enum-varnames": [
+                "FinalityUnconfirmed",
+                "FinalityFinalized"
+            ]
+        },
         "syncer.TransactionStatus": {
             "type": "string",
             "enum": [
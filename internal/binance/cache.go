@@ -0,0 +1,237 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// CachedClient is a Client that serves klines from a cache and can load them in bulk ahead of time
+type CachedClient interface {
+	Client
+	// Prefetch loads the klines containing the given times, fetching missing ones up to MaxKlinesLimit per request
+	Prefetch(ctx context.Context, symbol string, timestamps []time.Time) error
+}
+
+// cachedClient serves klines from an in-process LRU, then the candle store, then Binance
+type cachedClient struct {
+	client Client
+	store  CandleStore // Optional
+	recent *klineLRU
+}
+
+// NewCachedClient wraps client with an LRU of size klines backed by store. A nil store only caches in memory.
+func NewCachedClient(client Client, store CandleStore, size int) CachedClient {
+	return &cachedClient{
+		client: client,
+		store:  store,
+		recent: newKlineLRU(size),
+	}
+}
+
+// GetPrice returns the kline containing timestamp from the cache, falling back to Binance on a miss.
+// Cached klines are looked up along IntervalLadder, finest first. Klines that have not closed yet are
+// not cached, as their prices still change.
+func (c *cachedClient) GetPrice(ctx context.Context, symbol string, timestamp time.Time) (*KlineData, error) {
+	if kline, ok := c.cached(symbol, timestamp); ok {
+		return kline, nil
+	}
+
+	kline, err := c.client.GetPrice(ctx, symbol, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if kline.Closed() {
+		c.save(symbol, kline.Interval, []*KlineData{kline})
+		c.recent.add(klineKey(symbol, kline.Interval, kline.OpenTime), kline)
+	}
+	return kline, nil
+}
+
+// cached returns the finest cached kline containing timestamp from the LRU or the store
+func (c *cachedClient) cached(symbol string, timestamp time.Time) (*KlineData, bool) {
+	for _, interval := range IntervalLadder {
+		if kline, ok := c.recent.get(klineKey(symbol, interval, candleStart(interval, timestamp))); ok {
+			return kline, true
+		}
+	}
+	if c.store == nil {
+		return nil, false
+	}
+
+	for _, interval := range IntervalLadder {
+		openTime := candleStart(interval, timestamp)
+		klines, err := c.store.GetCandles(symbol, interval, []time.Time{openTime})
+		if err != nil {
+			log.Printf("Failed to read cached %s kline at %s: %v", symbol, openTime.Format(time.RFC3339), err)
+			return nil, false
+		}
+		if len(klines) > 0 {
+			c.recent.add(klineKey(symbol, interval, openTime), klines[0])
+			return klines[0], true
+		}
+	}
+	return nil, false
+}
+
+// GetKlines fetches klines from Binance and stores the closed ones for later lookups
+func (c *cachedClient) GetKlines(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]*KlineData, error) {
+	klines, err := c.client.GetKlines(ctx, symbol, interval, start, limit)
+	if err != nil {
+		return nil, err
+	}
+	c.save(symbol, interval, klines)
+	return klines, nil
+}
+
+// Prefetch loads the klines containing the given times into the cache. Times already cached are skipped;
+// the others are covered by as few requests of MaxKlinesLimit consecutive klines as possible, falling back
+// along IntervalLadder for times the finer intervals have no data for.
+func (c *cachedClient) Prefetch(ctx context.Context, symbol string, timestamps []time.Time) error {
+	missing := c.uncached(symbol, timestamps)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	total, requests := len(missing), 0
+	for _, interval := range IntervalLadder {
+		if len(missing) == 0 {
+			break
+		}
+		var sent int
+		var err error
+		missing, sent, err = c.prefetchInterval(ctx, symbol, interval, missing)
+		requests += sent
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("Prefetched %s klines for %d timestamps with %d requests (%d without data)", symbol, total, requests, len(missing))
+	return nil
+}
+
+// prefetchInterval fetches the interval's klines containing the sorted timestamps, returning the timestamps
+// no kline was found for and the number of requests sent
+func (c *cachedClient) prefetchInterval(ctx context.Context, symbol, interval string, timestamps []time.Time) ([]time.Time, int, error) {
+	duration := intervalDurations[interval]
+	var notFound []time.Time
+	requests := 0
+	for i := 0; i < len(timestamps); {
+		start := candleStart(interval, timestamps[i])
+		klines, err := c.GetKlines(ctx, symbol, interval, start, MaxKlinesLimit)
+		if err != nil {
+			return nil, requests, fmt.Errorf("failed to prefetch %s %s klines from %s: %w", symbol, interval, start.Format(time.RFC3339), err)
+		}
+		requests++
+
+		byOpenTime := make(map[int64]*KlineData, len(klines))
+		for _, kline := range klines {
+			byOpenTime[kline.OpenTime.UnixMilli()] = kline
+		}
+
+		// Resolve every timestamp covered by this window
+		end := start.Add(MaxKlinesLimit * duration)
+		for ; i < len(timestamps) && timestamps[i].Before(end); i++ {
+			openTime := candleStart(interval, timestamps[i])
+			if kline, ok := byOpenTime[openTime.UnixMilli()]; ok {
+				if kline.Closed() {
+					c.recent.add(klineKey(symbol, interval, openTime), kline)
+				}
+			} else {
+				notFound = append(notFound, timestamps[i])
+			}
+		}
+	}
+	return notFound, requests, nil
+}
+
+// uncached returns the sorted, distinct timestamps without a cached kline in the LRU or the store,
+// promoting stored ones into the LRU
+func (c *cachedClient) uncached(symbol string, timestamps []time.Time) []time.Time {
+	seen := make(map[int64]bool, len(timestamps))
+	var missing []time.Time
+	for _, timestamp := range timestamps {
+		timestamp = timestamp.UTC()
+		if seen[timestamp.UnixMilli()] {
+			continue
+		}
+		seen[timestamp.UnixMilli()] = true
+		if !c.inLRU(symbol, timestamp) {
+			missing = append(missing, timestamp)
+		}
+	}
+
+	if c.store != nil {
+		for _, interval := range IntervalLadder {
+			missing = c.removeStored(symbol, interval, missing)
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Before(missing[j]) })
+	return missing
+}
+
+// inLRU reports whether a kline of any ladder interval containing timestamp is in the LRU
+func (c *cachedClient) inLRU(symbol string, timestamp time.Time) bool {
+	for _, interval := range IntervalLadder {
+		if _, ok := c.recent.get(klineKey(symbol, interval, candleStart(interval, timestamp))); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStored drops the timestamps whose kline of the given interval is stored, promoting it into the LRU
+func (c *cachedClient) removeStored(symbol, interval string, timestamps []time.Time) []time.Time {
+	stored := make(map[int64]bool)
+	for i := 0; i < len(timestamps); i += MaxKlinesLimit {
+		end := i + MaxKlinesLimit
+		if end > len(timestamps) {
+			end = len(timestamps)
+		}
+		openTimes := make([]time.Time, 0, end-i)
+		for _, timestamp := range timestamps[i:end] {
+			openTimes = append(openTimes, candleStart(interval, timestamp))
+		}
+		klines, err := c.store.GetCandles(symbol, interval, openTimes)
+		if err != nil {
+			log.Printf("Failed to read cached %s klines: %v", symbol, err)
+			return timestamps
+		}
+		for _, kline := range klines {
+			stored[kline.OpenTime.UnixMilli()] = true
+			c.recent.add(klineKey(symbol, interval, kline.OpenTime), kline)
+		}
+	}
+
+	remaining := timestamps[:0]
+	for _, timestamp := range timestamps {
+		if !stored[candleStart(interval, timestamp).UnixMilli()] {
+			remaining = append(remaining, timestamp)
+		}
+	}
+	return remaining
+}
+
+// save stores the closed klines, logging failures since the cache is only an optimization
+func (c *cachedClient) save(symbol, interval string, klines []*KlineData) {
+	if c.store == nil {
+		return
+	}
+	closed := make([]*KlineData, 0, len(klines))
+	for _, kline := range klines {
+		if kline.Closed() {
+			closed = append(closed, kline)
+		}
+	}
+	if err := c.store.SaveCandles(symbol, interval, closed); err != nil {
+		log.Printf("Failed to cache %d %s klines: %v", len(closed), symbol, err)
+	}
+}
+
+// klineKey identifies a kline by symbol, interval and open time
+func klineKey(symbol, interval string, openTime time.Time) string {
+	return fmt.Sprintf("%s/%s/%d", symbol, interval, openTime.UnixMilli())
+}
@@ -0,0 +1,200 @@
+package binance
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient serves klines of every interval priced at the open time's unix seconds. 1s klines only
+// exist from since1s onwards.
+type fakeClient struct {
+	since1s     time.Time
+	priceCalls  int
+	klinesCalls int
+}
+
+func fakeKline(interval string, openTime time.Time) *KlineData {
+	price := big.NewFloat(float64(openTime.Unix() % 10000))
+	return &KlineData{
+		OpenTime:  openTime,
+		Close:     price,
+		CloseTime: openTime.Add(intervalDurations[interval] - time.Millisecond),
+		Interval:  interval,
+	}
+}
+
+func (c *fakeClient) GetPrice(_ context.Context, _ string, timestamp time.Time) (*KlineData, error) {
+	c.priceCalls++
+	if timestamp.Before(c.since1s) {
+		return fakeKline(Interval1m, candleStart(Interval1m, timestamp)), nil
+	}
+	return fakeKline(Interval1s, timestamp), nil
+}
+
+func (c *fakeClient) GetKlines(_ context.Context, _ string, interval string, start time.Time, limit int) ([]*KlineData, error) {
+	c.klinesCalls++
+	klines := make([]*KlineData, 0, limit)
+	for i := 0; i < limit; i++ {
+		openTime := start.Add(time.Duration(i) * intervalDurations[interval])
+		if interval == Interval1s && openTime.Before(c.since1s) {
+			continue
+		}
+		klines = append(klines, fakeKline(interval, openTime))
+	}
+	return klines, nil
+}
+
+// memoryStore is an in-memory CandleStore
+type memoryStore struct {
+	mu      sync.Mutex
+	candles map[string]*KlineData
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{candles: make(map[string]*KlineData)}
+}
+
+func (s *memoryStore) GetCandles(symbol, interval string, openTimes []time.Time) ([]*KlineData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var klines []*KlineData
+	for _, openTime := range openTimes {
+		if kline, ok := s.candles[klineKey(symbol, interval, openTime)]; ok {
+			klines = append(klines, kline)
+		}
+	}
+	return klines, nil
+}
+
+func (s *memoryStore) SaveCandles(symbol, interval string, klines []*KlineData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, kline := range klines {
+		s.candles[klineKey(symbol, interval, kline.OpenTime)] = kline
+	}
+	return nil
+}
+
+func (s *memoryStore) AutoMigrate() error {
+	return nil
+}
+
+func TestCachedClientServesRepeatedLookupsFromCache(t *testing.T) {
+	upstream := &fakeClient{}
+	store := newMemoryStore()
+	client := NewCachedClient(upstream, store, 100)
+	timestamp := time.Unix(1700000000, 0)
+
+	for i := 0; i < 3; i++ {
+		kline, err := client.GetPrice(context.Background(), "ETHUSDT", timestamp)
+		require.NoError(t, err)
+		assert.Equal(t, "0", kline.Close.String())
+	}
+	assert.Equal(t, 1, upstream.priceCalls)
+	assert.Len(t, store.candles, 1)
+
+	// A new process starts with an empty LRU but finds the kline in the store
+	restarted := NewCachedClient(upstream, store, 100)
+	_, err := restarted.GetPrice(context.Background(), "ETHUSDT", timestamp)
+	require.NoError(t, err)
+	assert.Equal(t, 1, upstream.priceCalls)
+}
+
+func TestCachedClientPrefetch(t *testing.T) {
+	upstream := &fakeClient{}
+	store := newMemoryStore()
+	client := NewCachedClient(upstream, store, 1000)
+
+	// One block every 12 seconds over 2400 seconds, in no particular order
+	start := time.Unix(1700000000, 0)
+	var timestamps []time.Time
+	for offset := 2400; offset >= 0; offset -= 12 {
+		timestamps = append(timestamps, start.Add(time.Duration(offset)*time.Second))
+	}
+
+	require.NoError(t, client.Prefetch(context.Background(), "ETHUSDT", timestamps))
+	// Windows of 1000 klines starting at +0, +1008 and +2016
+	assert.Equal(t, 3, upstream.klinesCalls)
+	assert.Len(t, store.candles, 3*MaxKlinesLimit)
+
+	for _, timestamp := range timestamps {
+		kline, err := client.GetPrice(context.Background(), "ETHUSDT", timestamp)
+		require.NoError(t, err)
+		assert.Equal(t, timestamp.Unix(), kline.OpenTime.Unix())
+	}
+	assert.Equal(t, 0, upstream.priceCalls)
+
+	// Everything is cached now
+	require.NoError(t, client.Prefetch(context.Background(), "ETHUSDT", timestamps))
+	assert.Equal(t, 3, upstream.klinesCalls)
+}
+
+func TestCachedClientPrefetchFallsBackToCoarserIntervals(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	upstream := &fakeClient{since1s: start.Add(time.Hour)}
+	client := NewCachedClient(upstream, newMemoryStore(), 1000)
+
+	// Timestamps before since1s can only be priced with 1m klines
+	old := []time.Time{start, start.Add(5 * time.Minute), start.Add(15 * time.Minute)}
+	recent := start.Add(2 * time.Hour)
+	require.NoError(t, client.Prefetch(context.Background(), "ETHUSDT", append(old, recent)))
+	// 1s windows for the old and the recent times, then a single 1m window for the old times
+	assert.Equal(t, 3, upstream.klinesCalls)
+
+	for _, timestamp := range old {
+		kline, err := client.GetPrice(context.Background(), "ETHUSDT", timestamp)
+		require.NoError(t, err)
+		assert.Equal(t, Interval1m, kline.Interval)
+		assert.True(t, kline.Contains(timestamp))
+	}
+	kline, err := client.GetPrice(context.Background(), "ETHUSDT", recent)
+	require.NoError(t, err)
+	assert.Equal(t, Interval1s, kline.Interval)
+	assert.Equal(t, 0, upstream.priceCalls)
+}
+
+func TestCachedClientSkipsOpenKlines(t *testing.T) {
+	upstream := &fakeClient{}
+	store := newMemoryStore()
+	client := NewCachedClient(upstream, store, 100)
+	now := time.Now()
+
+	// The candle of the current second is still open and is fetched again on every lookup
+	for i := 0; i < 2; i++ {
+		_, err := client.GetPrice(context.Background(), "ETHUSDT", now)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, upstream.priceCalls)
+
+	// Of the minute candles reaching the current time, only the closed ones are stored
+	start := candleStart(Interval1m, now).Add(-2 * time.Minute)
+	klines, err := client.GetKlines(context.Background(), "ETHUSDT", Interval1m, start, 5)
+	require.NoError(t, err)
+	assert.Len(t, klines, 5)
+	assert.Len(t, store.candles, 2)
+	for _, kline := range store.candles {
+		assert.True(t, kline.CloseTime.Before(now))
+	}
+}
+
+func TestKlineLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newKlineLRU(2)
+	cache.add("a", &KlineData{})
+	cache.add("b", &KlineData{})
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	cache.add("c", &KlineData{})
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+	_, ok = cache.get("c")
+	assert.True(t, ok)
+}
@@ -2,19 +2,48 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/metrics"
 	"uniswap-fee-tracker/internal/utils"
 
 	"github.com/go-resty/resty/v2"
 	"golang.org/x/time/rate"
 )
 
+// Kline intervals used to price transactions
+const (
+	Interval1s = "1s"
+	Interval1m = "1m"
+	Interval5m = "5m"
+	Interval1h = "1h"
+)
+
+// IntervalLadder lists the intervals GetPrice tries, finest first. 1s klines only exist for recent dates,
+// so older timestamps fall back to coarser candles.
+var IntervalLadder = []string{Interval1s, Interval1m, Interval5m, Interval1h}
+
+// intervalDurations maps the ladder intervals to their length
+var intervalDurations = map[string]time.Duration{
+	Interval1s: time.Second,
+	Interval1m: time.Minute,
+	Interval5m: 5 * time.Minute,
+	Interval1h: time.Hour,
+}
+
+// ErrNoKlines is returned when no interval of the ladder has a kline containing the requested time
+var ErrNoKlines = errors.New("no klines available")
+
+// MaxKlinesLimit is the maximum number of klines returned by a single /klines request
+const MaxKlinesLimit = 1000
+
 // Client defines methods for interacting with Binance API
 type Client interface {
 	GetPrice(ctx context.Context, symbol string, timestamp time.Time) (*KlineData, error)
+	GetKlines(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]*KlineData, error)
 }
 
 type client struct {
@@ -41,43 +70,75 @@ func NewClient(cfg *config.BinanceConfig) Client {
 	}
 }
 
-// GetPrice fetches the price data for a given symbol at a specific timestamp
+// GetPrice fetches the kline containing timestamp, using the finest interval of IntervalLadder that has data
 func (c *client) GetPrice(ctx context.Context, symbol string, timestamp time.Time) (*KlineData, error) {
+	for _, interval := range IntervalLadder {
+		klines, err := c.GetKlines(ctx, symbol, interval, candleStart(interval, timestamp), 1)
+		if err != nil {
+			return nil, err
+		}
+		// Binance returns the first later kline when none exists at the start time
+		if len(klines) > 0 && klines[0].Contains(timestamp) {
+			return klines[0], nil
+		}
+	}
+	return nil, fmt.Errorf("%s at %s: %w", symbol, timestamp.UTC().Format(time.RFC3339), ErrNoKlines)
+}
+
+// candleStart returns the open time of the interval's candle containing timestamp
+func candleStart(interval string, timestamp time.Time) time.Time {
+	return timestamp.UTC().Truncate(intervalDurations[interval])
+}
+
+// GetKlines fetches up to limit consecutive klines of the given interval opening at or after start
+func (c *client) GetKlines(ctx context.Context, symbol string, interval string, start time.Time, limit int) ([]*KlineData, error) {
 	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientBinance, 1); err != nil {
 		return nil, fmt.Errorf("rate limiter wait: %w", err)
 	}
 
 	var klines [][]interface{}
 
+	sent := time.Now()
 	resp, err := c.httpClient.R().
 		SetContext(ctx).
 		SetQueryParams(map[string]string{
 			"symbol":    symbol,
-			"interval":  "1s",
-			"startTime": strconv.FormatInt(timestamp.UnixMilli(), 10),
-			"limit":     "1",
+			"interval":  interval,
+			"startTime": strconv.FormatInt(start.UnixMilli(), 10),
+			"limit":     strconv.Itoa(limit),
 		}).
 		SetResult(&klines).
 		Get("/klines")
 
-	if err != nil || !resp.IsSuccess() || len(klines) == 0 {
-		return nil, fmt.Errorf("failed to fetch price data: %v", err)
+	if err == nil && !resp.IsSuccess() {
+		err = fmt.Errorf("status %d", resp.StatusCode())
+	}
+	metrics.ObserveRequest(metrics.ClientBinance, "klines", sent, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price data: %w", err)
 	}
 
-	// Parse the kline data into a struct
-	k := klines[0]
-	return &KlineData{
-		OpenTime:                 time.UnixMilli(utils.MustParseInt64(k[0])),
-		Open:                     utils.MustParseBigFloat(k[1]),
-		High:                     utils.MustParseBigFloat(k[2]),
-		Low:                      utils.MustParseBigFloat(k[3]),
-		Close:                    utils.MustParseBigFloat(k[4]),
-		Volume:                   utils.MustParseBigFloat(k[5]),
-		CloseTime:                time.UnixMilli(utils.MustParseInt64(k[6])),
-		QuoteAssetVolume:         utils.MustParseBigFloat(k[7]),
-		NumberOfTrades:           utils.MustParseInt64(k[8]),
-		TakerBuyBaseAssetVolume:  utils.MustParseBigFloat(k[9]),
-		TakerBuyQuoteAssetVolume: utils.MustParseBigFloat(k[10]),
-	}, nil
+	// Parse the kline data into structs
+	result := make([]*KlineData, 0, len(klines))
+	for _, k := range klines {
+		if len(k) < 11 {
+			return nil, fmt.Errorf("malformed kline with %d fields", len(k))
+		}
+		result = append(result, &KlineData{
+			OpenTime:                 time.UnixMilli(utils.MustParseInt64(k[0])),
+			Open:                     utils.MustParseBigFloat(k[1]),
+			High:                     utils.MustParseBigFloat(k[2]),
+			Low:                      utils.MustParseBigFloat(k[3]),
+			Close:                    utils.MustParseBigFloat(k[4]),
+			Volume:                   utils.MustParseBigFloat(k[5]),
+			CloseTime:                time.UnixMilli(utils.MustParseInt64(k[6])),
+			QuoteAssetVolume:         utils.MustParseBigFloat(k[7]),
+			NumberOfTrades:           utils.MustParseInt64(k[8]),
+			TakerBuyBaseAssetVolume:  utils.MustParseBigFloat(k[9]),
+			TakerBuyQuoteAssetVolume: utils.MustParseBigFloat(k[10]),
+			Interval:                 interval,
+		})
+	}
+	return result, nil
 }
@@ -0,0 +1,61 @@
+package binance
+
+import (
+	"container/list"
+	"sync"
+)
+
+// klineLRU is a fixed-size, concurrency-safe cache of recently used klines
+type klineLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	kline *KlineData
+}
+
+func newKlineLRU(size int) *klineLRU {
+	return &klineLRU{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached kline for key and marks it as recently used
+func (c *klineLRU) get(key string) (*KlineData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).kline, true
+}
+
+// add caches the kline for key, evicting the least recently used entry when full
+func (c *klineLRU) add(key string, kline *KlineData) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).kline = kline
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, kline: kline})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
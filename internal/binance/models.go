@@ -18,4 +18,45 @@ type KlineData struct {
 	NumberOfTrades           int64
 	TakerBuyBaseAssetVolume  *big.Float
 	TakerBuyQuoteAssetVolume *big.Float
+	Interval                 string // Kline interval, e.g. 1s or 1m
+}
+
+// Contains reports whether timestamp falls within the kline
+func (k *KlineData) Contains(timestamp time.Time) bool {
+	duration, ok := intervalDurations[k.Interval]
+	if !ok {
+		return !timestamp.Before(k.OpenTime) && !timestamp.After(k.CloseTime)
+	}
+	return !timestamp.Before(k.OpenTime) && timestamp.Before(k.OpenTime.Add(duration))
+}
+
+// Closed reports whether the kline's candle has closed. The prices of an open candle still change.
+func (k *KlineData) Closed() bool {
+	return k.CloseTime.Before(time.Now())
+}
+
+// VWAP returns the volume-weighted average price of the kline, or its close when nothing was traded
+func (k *KlineData) VWAP() *big.Float {
+	if k.Volume == nil || k.Volume.Sign() == 0 || k.QuoteAssetVolume == nil {
+		return k.Close
+	}
+	return new(big.Float).Quo(k.QuoteAssetVolume, k.Volume)
+}
+
+// PriceCandle is a kline persisted in the price_candles table. Decimal fields are stored as exact strings.
+type PriceCandle struct {
+	Symbol                   string    `gorm:"primaryKey;type:varchar(20)"`
+	Interval                 string    `gorm:"primaryKey;column:kline_interval;type:varchar(8)"`
+	OpenTime                 time.Time `gorm:"primaryKey"`
+	CloseTime                time.Time
+	Open                     string `gorm:"type:numeric(38,18)"`
+	High                     string `gorm:"type:numeric(38,18)"`
+	Low                      string `gorm:"type:numeric(38,18)"`
+	Close                    string `gorm:"type:numeric(38,18)"`
+	Volume                   string `gorm:"type:numeric(38,18)"`
+	QuoteAssetVolume         string `gorm:"type:numeric(38,18)"`
+	NumberOfTrades           int64
+	TakerBuyBaseAssetVolume  string `gorm:"type:numeric(38,18)"`
+	TakerBuyQuoteAssetVolume string `gorm:"type:numeric(38,18)"`
+	CreatedAt                time.Time
 }
@@ -0,0 +1,114 @@
+package binance
+
+import (
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CandleStore persists fetched klines so they are only requested from Binance once
+type CandleStore interface {
+	GetCandles(symbol, interval string, openTimes []time.Time) ([]*KlineData, error)
+	SaveCandles(symbol, interval string, klines []*KlineData) error
+	AutoMigrate() error
+}
+
+type candleStore struct {
+	db *gorm.DB
+}
+
+// NewCandleStore creates a CandleStore backed by the price_candles table
+func NewCandleStore(db *gorm.DB) CandleStore {
+	return &candleStore{db: db}
+}
+
+// GetCandles returns the stored klines opening at any of the given times
+func (s *candleStore) GetCandles(symbol, interval string, openTimes []time.Time) ([]*KlineData, error) {
+	var candles []PriceCandle
+	if len(openTimes) == 0 {
+		return nil, nil
+	}
+	err := s.db.Where("symbol = ? AND kline_interval = ? AND open_time IN ?", symbol, interval, openTimes).
+		Order("open_time").
+		Find(&candles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	klines := make([]*KlineData, 0, len(candles))
+	for _, candle := range candles {
+		klines = append(klines, candle.toKline())
+	}
+	return klines, nil
+}
+
+// SaveCandles stores klines, leaving already stored ones untouched
+func (s *candleStore) SaveCandles(symbol, interval string, klines []*KlineData) error {
+	if len(klines) == 0 {
+		return nil
+	}
+	candles := make([]PriceCandle, 0, len(klines))
+	for _, kline := range klines {
+		candles = append(candles, newPriceCandle(symbol, interval, kline))
+	}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(candles, 500).Error
+}
+
+// AutoMigrate creates or updates the price_candles table
+func (s *candleStore) AutoMigrate() error {
+	return s.db.AutoMigrate(&PriceCandle{})
+}
+
+func newPriceCandle(symbol, interval string, kline *KlineData) PriceCandle {
+	return PriceCandle{
+		Symbol:                   symbol,
+		Interval:                 interval,
+		OpenTime:                 kline.OpenTime.UTC(),
+		CloseTime:                kline.CloseTime.UTC(),
+		Open:                     formatDecimal(kline.Open),
+		High:                     formatDecimal(kline.High),
+		Low:                      formatDecimal(kline.Low),
+		Close:                    formatDecimal(kline.Close),
+		Volume:                   formatDecimal(kline.Volume),
+		QuoteAssetVolume:         formatDecimal(kline.QuoteAssetVolume),
+		NumberOfTrades:           kline.NumberOfTrades,
+		TakerBuyBaseAssetVolume:  formatDecimal(kline.TakerBuyBaseAssetVolume),
+		TakerBuyQuoteAssetVolume: formatDecimal(kline.TakerBuyQuoteAssetVolume),
+	}
+}
+
+func (c PriceCandle) toKline() *KlineData {
+	return &KlineData{
+		OpenTime:                 c.OpenTime,
+		Open:                     parseDecimal(c.Open),
+		High:                     parseDecimal(c.High),
+		Low:                      parseDecimal(c.Low),
+		Close:                    parseDecimal(c.Close),
+		Volume:                   parseDecimal(c.Volume),
+		CloseTime:                c.CloseTime,
+		QuoteAssetVolume:         parseDecimal(c.QuoteAssetVolume),
+		NumberOfTrades:           c.NumberOfTrades,
+		TakerBuyBaseAssetVolume:  parseDecimal(c.TakerBuyBaseAssetVolume),
+		TakerBuyQuoteAssetVolume: parseDecimal(c.TakerBuyQuoteAssetVolume),
+		Interval:                 c.Interval,
+	}
+}
+
+// formatDecimal returns the shortest decimal representation of v, or "0" when unset
+func formatDecimal(v *big.Float) string {
+	if v == nil {
+		return "0"
+	}
+	return v.Text('f', -1)
+}
+
+// parseDecimal parses a stored decimal, returning nil when it is invalid
+func parseDecimal(s string) *big.Float {
+	v, ok := new(big.Float).SetString(s)
+	if !ok {
+		return nil
+	}
+	return v
+}
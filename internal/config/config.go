@@ -1,20 +1,109 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// addressPattern matches a 20-byte hex Ethereum address
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
 type Config struct {
 	Port                string
 	DBUri               string
-	UniswapV3Pool       string
-	UniswapStartBlock   uint64
+	Pools               []PoolConfig
 	EtherscanConfig     EtherscanConfig
 	BinanceConfig       BinanceConfig
+	CoinbaseConfig      CoinbaseConfig
+	KrakenConfig        KrakenConfig
+	PriceProviders      []string // Price providers in priority order
+	RecordPoolPrice     bool     // Store the on-chain pool ETH price alongside the provider price
 	EthereumConfig      EthereumConfig
+	FinalityConfig      FinalityConfig
+	HistoricalConfig    HistoricalConfig
+	ReconcileConfig     ReconcileConfig
+	WebhookConfig       WebhookConfig
+	AdminAPIKey         string // Enables the admin API when set
 	PriceFetchBatchSize int
+
+	// Currencies fees are converted to in addition to USDT
+	FeeCurrencies []string
+}
+
+// ReconcileConfig controls the background retry of transactions whose price could not be fetched
+type ReconcileConfig struct {
+	Interval time.Duration
+}
+
+// WebhookConfig controls the delivery of webhook notifications
+type WebhookConfig struct {
+	PollInterval time.Duration // How often due deliveries are sent
+	Timeout      time.Duration // Timeout of a single delivery request
+	MaxAttempts  int           // Failed attempts after which a delivery is dead-lettered
+}
+
+// Historical sync backends
+const (
+	HistoricalBackendEtherscan = "etherscan" // Token transfers from the Etherscan tokentx API
+	HistoricalBackendLogs      = "logs"      // Pool logs scanned with eth_getLogs on the Ethereum node
+)
+
+// HistoricalConfig selects and tunes the historical sync backend
+type HistoricalConfig struct {
+	Backend   string
+	LogWindow uint64 // Initial number of blocks per eth_getLogs call
+	MaxWindow uint64 // Upper bound the window grows to while few logs are returned
+	Chunks    int    // Number of block ranges each pool's backfill is split into
+	Workers   int    // Maximum number of chunks synced concurrently
+}
+
+// Block tags the node can report as final
+const (
+	BlockTagSafe      = "safe"
+	BlockTagFinalized = "finalized"
+)
+
+// FinalityConfig controls when stored transactions are considered final
+type FinalityConfig struct {
+	ConfirmationDepth uint64        // Blocks a transaction must be buried under, used when BlockTag is empty
+	BlockTag          string        // Optional node block tag (safe or finalized) used instead of ConfirmationDepth
+	PromoteInterval   time.Duration // How often unconfirmed transactions are checked
+}
+
+// TokenConfig describes an ERC-20 token held by a pool
+type TokenConfig struct {
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// PoolConfig describes a Uniswap V3 pool to track
+type PoolConfig struct {
+	Name        string      `json:"name"`
+	Address     string      `json:"address"`
+	Token0      TokenConfig `json:"token0"`
+	Token1      TokenConfig `json:"token1"`
+	FeeTier     uint32      `json:"fee_tier"`     // In hundredths of a bip, e.g. 500 = 0.05%
+	StartBlock  uint64      `json:"start_block"`  // Block to start the historical sync from
+	InvertPrice bool        `json:"invert_price"` // Quote prices in token0 per token1
+}
+
+// DefaultPools is tracked when TRACKED_POOLS is not set
+var DefaultPools = []PoolConfig{
+	{
+		Name:        "WETH-USDC-0.05%",
+		Address:     "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", // Uniswap V3 USDC/WETH pool
+		Token0:      TokenConfig{Symbol: "USDC", Decimals: 6},
+		Token1:      TokenConfig{Symbol: "WETH", Decimals: 18},
+		FeeTier:     500,
+		StartBlock:  12376729, // Uniswap V3 deployment block
+		InvertPrice: true,
+	},
 }
 
 // HTTPClientConfig contains common configuration for HTTP clients with rate limiting
@@ -34,17 +123,86 @@ type EtherscanConfig struct {
 
 type BinanceConfig struct {
 	HTTPClientConfig
+	CacheSize  int    // Klines kept in the in-process LRU in front of the price_candles table
+	PriceField string // Candle field used as the price: close, open or vwap
+}
+
+type CoinbaseConfig struct {
+	HTTPClientConfig
+}
+
+type KrakenConfig struct {
+	HTTPClientConfig
 }
 
+// DefaultPriceProviders is used when PRICE_PROVIDERS is not set
+var DefaultPriceProviders = []string{"binance", "coinbase", "kraken"}
+
+// SupportedFeeCurrencies lists the currencies FEE_CURRENCIES may convert fees to
+var SupportedFeeCurrencies = []string{"USD", "EUR", "GBP", "BTC"}
+
 type EthereumConfig struct {
 	InfuraAPIKey string
 	HTTPClientConfig
 }
 
 func LoadConfig() (*Config, error) {
+	historical, err := loadHistorical()
+	if err != nil {
+		return nil, err
+	}
+
+	priceProviders, err := loadPriceProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	feeCurrencies, err := loadFeeCurrencies()
+	if err != nil {
+		return nil, err
+	}
+
+	recordPoolPrice := false
+	if raw := os.Getenv("RECORD_POOL_PRICE"); raw != "" {
+		recordPoolPrice, err = strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("RECORD_POOL_PRICE must be a boolean, got %q", raw)
+		}
+	}
+
+	klineCacheSize := 10000
+	if raw := os.Getenv("KLINE_CACHE_SIZE"); raw != "" {
+		klineCacheSize, err = strconv.Atoi(raw)
+		if err != nil || klineCacheSize < 0 {
+			return nil, fmt.Errorf("KLINE_CACHE_SIZE must be a non-negative integer, got %q", raw)
+		}
+	}
+
+	priceField := strings.ToLower(os.Getenv("BINANCE_PRICE_FIELD"))
+	switch priceField {
+	case "":
+		priceField = "close"
+	case "close", "open", "vwap":
+	default:
+		return nil, fmt.Errorf("BINANCE_PRICE_FIELD must be close, open or vwap, got %q", priceField)
+	}
+
+	reconcileInterval := 5 * time.Minute
+	if raw := os.Getenv("PRICE_RECONCILE_INTERVAL"); raw != "" {
+		reconcileInterval, err = time.ParseDuration(raw)
+		if err != nil || reconcileInterval <= 0 {
+			return nil, fmt.Errorf("PRICE_RECONCILE_INTERVAL must be a positive duration, got %q", raw)
+		}
+	}
+
+	webhook, err := loadWebhook()
+	if err != nil {
+		return nil, err
+	}
+
 	// Required environment variables
 	etherscanAPIKey := os.Getenv("ETHERSCAN_API_KEY")
-	if etherscanAPIKey == "" {
+	if etherscanAPIKey == "" && historical.Backend == HistoricalBackendEtherscan {
 		return nil, fmt.Errorf("ETHERSCAN_API_KEY environment variable is required")
 	}
 
@@ -58,11 +216,20 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("DB_URI environment variable is required")
 	}
 
+	pools, err := loadPools()
+	if err != nil {
+		return nil, err
+	}
+
+	finality, err := loadFinality()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		Port:              ":8080",
-		DBUri:             dbURI,
-		UniswapV3Pool:     "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", // Uniswap V3 USDC/WETH pool
-		UniswapStartBlock: 12376729,                                     // Uniswap V3 deployment block
+		Port:  ":8080",
+		DBUri: dbURI,
+		Pools: pools,
 		EthereumConfig: EthereumConfig{
 			InfuraAPIKey: infuraAPIKey,
 			HTTPClientConfig: HTTPClientConfig{
@@ -94,7 +261,234 @@ func LoadConfig() (*Config, error) {
 				RateBurst:  50,   // Allow larger bursts for Binance
 				Timeout:    10 * time.Second,
 			},
+			CacheSize:  klineCacheSize,
+			PriceField: priceField,
 		},
+		CoinbaseConfig: CoinbaseConfig{
+			HTTPClientConfig: HTTPClientConfig{
+				BaseURL:    "https://api.exchange.coinbase.com",
+				RetryCount: 3,
+				RetryWait:  time.Second,
+				RateLimit:  10.0, // Coinbase public limit: 10 requests per second
+				RateBurst:  10,
+				Timeout:    10 * time.Second,
+			},
+		},
+		KrakenConfig: KrakenConfig{
+			HTTPClientConfig: HTTPClientConfig{
+				BaseURL:    "https://api.kraken.com",
+				RetryCount: 3,
+				RetryWait:  time.Second,
+				RateLimit:  1.0, // Kraken public endpoints allow roughly one call per second
+				RateBurst:  5,
+				Timeout:    10 * time.Second,
+			},
+		},
+		PriceProviders:      priceProviders,
+		RecordPoolPrice:     recordPoolPrice,
+		FinalityConfig:      finality,
+		HistoricalConfig:    historical,
+		ReconcileConfig:     ReconcileConfig{Interval: reconcileInterval},
+		WebhookConfig:       webhook,
+		AdminAPIKey:         os.Getenv("ADMIN_API_KEY"),
 		PriceFetchBatchSize: 100,
+		FeeCurrencies:       feeCurrencies,
 	}, nil
 }
+
+// loadPools reads the tracked pools from the TRACKED_POOLS JSON array, falling back to DefaultPools
+func loadPools() ([]PoolConfig, error) {
+	raw := os.Getenv("TRACKED_POOLS")
+	if raw == "" {
+		return DefaultPools, nil
+	}
+
+	var pools []PoolConfig
+	if err := json.Unmarshal([]byte(raw), &pools); err != nil {
+		return nil, fmt.Errorf("TRACKED_POOLS must be a JSON array of pools: %w", err)
+	}
+	if len(pools) == 0 {
+		return nil, fmt.Errorf("TRACKED_POOLS must contain at least one pool")
+	}
+
+	seen := make(map[string]bool)
+	for i, pool := range pools {
+		if !addressPattern.MatchString(pool.Address) {
+			return nil, fmt.Errorf("TRACKED_POOLS[%d]: invalid address %q", i, pool.Address)
+		}
+		if seen[strings.ToLower(pool.Address)] {
+			return nil, fmt.Errorf("TRACKED_POOLS[%d]: duplicate address %s", i, pool.Address)
+		}
+		seen[strings.ToLower(pool.Address)] = true
+		if pool.Token0.Symbol == "" || pool.Token1.Symbol == "" {
+			return nil, fmt.Errorf("TRACKED_POOLS[%d]: token symbols are required", i)
+		}
+		if pool.Token0.Decimals <= 0 || pool.Token1.Decimals <= 0 {
+			return nil, fmt.Errorf("TRACKED_POOLS[%d]: token decimals are required", i)
+		}
+		if pool.StartBlock == 0 {
+			return nil, fmt.Errorf("TRACKED_POOLS[%d]: start_block is required", i)
+		}
+		if pool.Name == "" {
+			pools[i].Name = fmt.Sprintf("%s-%s-%d", pool.Token0.Symbol, pool.Token1.Symbol, pool.FeeTier)
+		}
+	}
+	return pools, nil
+}
+
+// loadFinality reads CONFIRMATION_DEPTH and FINALITY_BLOCK_TAG
+func loadFinality() (FinalityConfig, error) {
+	finality := FinalityConfig{
+		ConfirmationDepth: 12,
+		PromoteInterval:   12 * time.Second, // One mainnet slot
+	}
+
+	if raw := os.Getenv("CONFIRMATION_DEPTH"); raw != "" {
+		depth, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return finality, fmt.Errorf("CONFIRMATION_DEPTH must be a non-negative integer: %w", err)
+		}
+		finality.ConfirmationDepth = depth
+	}
+
+	switch tag := os.Getenv("FINALITY_BLOCK_TAG"); tag {
+	case "", BlockTagSafe, BlockTagFinalized:
+		finality.BlockTag = tag
+	default:
+		return finality, fmt.Errorf("FINALITY_BLOCK_TAG must be %q or %q, got %q", BlockTagSafe, BlockTagFinalized, tag)
+	}
+	return finality, nil
+}
+
+// loadHistorical reads HISTORICAL_SYNC_BACKEND, LOG_SCAN_WINDOW, LOG_SCAN_MAX_WINDOW,
+// HISTORICAL_SYNC_CHUNKS and HISTORICAL_SYNC_WORKERS
+func loadHistorical() (HistoricalConfig, error) {
+	historical := HistoricalConfig{
+		Backend:   HistoricalBackendEtherscan,
+		LogWindow: 2000,
+		MaxWindow: 10000,
+		Chunks:    8,
+		Workers:   4,
+	}
+
+	switch backend := os.Getenv("HISTORICAL_SYNC_BACKEND"); backend {
+	case "":
+	case HistoricalBackendEtherscan, HistoricalBackendLogs:
+		historical.Backend = backend
+	default:
+		return historical, fmt.Errorf("HISTORICAL_SYNC_BACKEND must be %q or %q, got %q",
+			HistoricalBackendEtherscan, HistoricalBackendLogs, backend)
+	}
+
+	for name, target := range map[string]*uint64{
+		"LOG_SCAN_WINDOW":     &historical.LogWindow,
+		"LOG_SCAN_MAX_WINDOW": &historical.MaxWindow,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		window, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || window == 0 {
+			return historical, fmt.Errorf("%s must be a positive integer, got %q", name, raw)
+		}
+		*target = window
+	}
+	for name, target := range map[string]*int{
+		"HISTORICAL_SYNC_CHUNKS":  &historical.Chunks,
+		"HISTORICAL_SYNC_WORKERS": &historical.Workers,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			return historical, fmt.Errorf("%s must be a positive integer, got %q", name, raw)
+		}
+		*target = value
+	}
+	if historical.LogWindow > historical.MaxWindow {
+		return historical, fmt.Errorf("LOG_SCAN_WINDOW (%d) is greater than LOG_SCAN_MAX_WINDOW (%d)",
+			historical.LogWindow, historical.MaxWindow)
+	}
+	return historical, nil
+}
+
+// loadWebhook reads WEBHOOK_POLL_INTERVAL and WEBHOOK_MAX_ATTEMPTS
+func loadWebhook() (WebhookConfig, error) {
+	webhook := WebhookConfig{
+		PollInterval: 5 * time.Second,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  10,
+	}
+
+	if raw := os.Getenv("WEBHOOK_POLL_INTERVAL"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval <= 0 {
+			return webhook, fmt.Errorf("WEBHOOK_POLL_INTERVAL must be a positive duration, got %q", raw)
+		}
+		webhook.PollInterval = interval
+	}
+	if raw := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil || attempts <= 0 {
+			return webhook, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be a positive integer, got %q", raw)
+		}
+		webhook.MaxAttempts = attempts
+	}
+	return webhook, nil
+}
+
+// loadPriceProviders reads the comma separated PRICE_PROVIDERS priority list
+func loadPriceProviders() ([]string, error) {
+	raw := os.Getenv("PRICE_PROVIDERS")
+	if raw == "" {
+		return DefaultPriceProviders, nil
+	}
+
+	var providers []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		switch name {
+		case "binance", "coinbase", "kraken", "uniswap":
+		default:
+			return nil, fmt.Errorf("PRICE_PROVIDERS: unknown provider %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("PRICE_PROVIDERS: duplicate provider %q", name)
+		}
+		seen[name] = true
+		providers = append(providers, name)
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("PRICE_PROVIDERS must list at least one provider")
+	}
+	return providers, nil
+}
+
+// loadFeeCurrencies reads the comma separated FEE_CURRENCIES list, empty by default
+func loadFeeCurrencies() ([]string, error) {
+	var currencies []string
+	seen := make(map[string]bool)
+	for _, currency := range strings.Split(os.Getenv("FEE_CURRENCIES"), ",") {
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if currency == "" {
+			continue
+		}
+		if !slices.Contains(SupportedFeeCurrencies, currency) {
+			return nil, fmt.Errorf("FEE_CURRENCIES: unsupported currency %q, expected one of %s",
+				currency, strings.Join(SupportedFeeCurrencies, ", "))
+		}
+		if seen[currency] {
+			return nil, fmt.Errorf("FEE_CURRENCIES: duplicate currency %q", currency)
+		}
+		seen[currency] = true
+		currencies = append(currencies, currency)
+	}
+	return currencies, nil
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPools(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("TRACKED_POOLS", "")
+		pools, err := loadPools()
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultPools, pools)
+	})
+
+	t.Run("custom pools", func(t *testing.T) {
+		t.Setenv("TRACKED_POOLS", `[
+			{"address": "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", "token0": {"symbol": "USDC", "decimals": 6}, "token1": {"symbol": "WETH", "decimals": 18}, "fee_tier": 500, "start_block": 12376729, "invert_price": true},
+			{"name": "WBTC-WETH-0.3%", "address": "0xCBCdF9626bC03E24f779434178A73a0B4bad62eD", "token0": {"symbol": "WBTC", "decimals": 8}, "token1": {"symbol": "WETH", "decimals": 18}, "fee_tier": 3000, "start_block": 12369621}
+		]`)
+		pools, err := loadPools()
+		assert.NoError(t, err)
+		assert.Len(t, pools, 2)
+		assert.Equal(t, "USDC-WETH-500", pools[0].Name)
+		assert.True(t, pools[0].InvertPrice)
+		assert.Equal(t, "WBTC-WETH-0.3%", pools[1].Name)
+		assert.Equal(t, 8, pools[1].Token0.Decimals)
+		assert.Equal(t, uint32(3000), pools[1].FeeTier)
+	})
+
+	invalid := map[string]string{
+		"not json":         `{`,
+		"empty":            `[]`,
+		"bad address":      `[{"address": "0x1234", "token0": {"symbol": "A", "decimals": 6}, "token1": {"symbol": "B", "decimals": 18}, "start_block": 1}]`,
+		"missing decimals": `[{"address": "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", "token0": {"symbol": "USDC"}, "token1": {"symbol": "WETH", "decimals": 18}, "start_block": 1}]`,
+		"missing start":    `[{"address": "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", "token0": {"symbol": "USDC", "decimals": 6}, "token1": {"symbol": "WETH", "decimals": 18}}]`,
+		"duplicate": `[
+			{"address": "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640", "token0": {"symbol": "USDC", "decimals": 6}, "token1": {"symbol": "WETH", "decimals": 18}, "start_block": 1},
+			{"address": "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", "token0": {"symbol": "USDC", "decimals": 6}, "token1": {"symbol": "WETH", "decimals": 18}, "start_block": 1}
+		]`,
+	}
+	for name, raw := range invalid {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TRACKED_POOLS", raw)
+			_, err := loadPools()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadFinality(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("CONFIRMATION_DEPTH", "")
+		t.Setenv("FINALITY_BLOCK_TAG", "")
+		finality, err := loadFinality()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(12), finality.ConfirmationDepth)
+		assert.Empty(t, finality.BlockTag)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		t.Setenv("CONFIRMATION_DEPTH", "64")
+		t.Setenv("FINALITY_BLOCK_TAG", "finalized")
+		finality, err := loadFinality()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(64), finality.ConfirmationDepth)
+		assert.Equal(t, BlockTagFinalized, finality.BlockTag)
+	})
+
+	t.Run("invalid depth", func(t *testing.T) {
+		t.Setenv("CONFIRMATION_DEPTH", "-1")
+		_, err := loadFinality()
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		t.Setenv("CONFIRMATION_DEPTH", "")
+		t.Setenv("FINALITY_BLOCK_TAG", "latest")
+		_, err := loadFinality()
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadHistorical(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("HISTORICAL_SYNC_BACKEND", "")
+		t.Setenv("LOG_SCAN_WINDOW", "")
+		t.Setenv("LOG_SCAN_MAX_WINDOW", "")
+		t.Setenv("HISTORICAL_SYNC_CHUNKS", "")
+		t.Setenv("HISTORICAL_SYNC_WORKERS", "")
+		historical, err := loadHistorical()
+		assert.NoError(t, err)
+		assert.Equal(t, HistoricalBackendEtherscan, historical.Backend)
+		assert.Equal(t, uint64(2000), historical.LogWindow)
+		assert.Equal(t, 8, historical.Chunks)
+		assert.Equal(t, 4, historical.Workers)
+	})
+
+	t.Run("logs backend", func(t *testing.T) {
+		t.Setenv("HISTORICAL_SYNC_BACKEND", "logs")
+		t.Setenv("LOG_SCAN_WINDOW", "500")
+		t.Setenv("LOG_SCAN_MAX_WINDOW", "5000")
+		t.Setenv("HISTORICAL_SYNC_CHUNKS", "16")
+		t.Setenv("HISTORICAL_SYNC_WORKERS", "2")
+		historical, err := loadHistorical()
+		assert.NoError(t, err)
+		assert.Equal(t, HistoricalBackendLogs, historical.Backend)
+		assert.Equal(t, uint64(500), historical.LogWindow)
+		assert.Equal(t, uint64(5000), historical.MaxWindow)
+		assert.Equal(t, 16, historical.Chunks)
+		assert.Equal(t, 2, historical.Workers)
+	})
+
+	invalid := map[string]map[string]string{
+		"unknown backend": {"HISTORICAL_SYNC_BACKEND": "graph"},
+		"zero window":     {"LOG_SCAN_WINDOW": "0"},
+		"window too big":  {"LOG_SCAN_WINDOW": "20000"},
+		"zero workers":    {"HISTORICAL_SYNC_WORKERS": "0"},
+		"bad chunks":      {"HISTORICAL_SYNC_CHUNKS": "many"},
+	}
+	for name, env := range invalid {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HISTORICAL_SYNC_BACKEND", "")
+			t.Setenv("LOG_SCAN_WINDOW", "")
+			t.Setenv("LOG_SCAN_MAX_WINDOW", "")
+			t.Setenv("HISTORICAL_SYNC_CHUNKS", "")
+			t.Setenv("HISTORICAL_SYNC_WORKERS", "")
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+			_, err := loadHistorical()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadPriceProviders(t *testing.T) {
+	t.Setenv("PRICE_PROVIDERS", "")
+	providers, err := loadPriceProviders()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultPriceProviders, providers)
+
+	t.Setenv("PRICE_PROVIDERS", " Kraken, binance ")
+	providers, err = loadPriceProviders()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kraken", "binance"}, providers)
+
+	t.Setenv("PRICE_PROVIDERS", "uniswap")
+	providers, err = loadPriceProviders()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uniswap"}, providers)
+
+	for _, invalid := range []string{"ftx", "binance,binance", ","} {
+		t.Setenv("PRICE_PROVIDERS", invalid)
+		_, err := loadPriceProviders()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLoadFeeCurrencies(t *testing.T) {
+	t.Setenv("FEE_CURRENCIES", "")
+	currencies, err := loadFeeCurrencies()
+	assert.NoError(t, err)
+	assert.Empty(t, currencies)
+
+	t.Setenv("FEE_CURRENCIES", " eur, BTC ")
+	currencies, err = loadFeeCurrencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "BTC"}, currencies)
+
+	for _, invalid := range []string{"JPY", "EUR,eur", "USDT"} {
+		t.Setenv("FEE_CURRENCIES", invalid)
+		_, err := loadFeeCurrencies()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLoadWebhook(t *testing.T) {
+	t.Setenv("WEBHOOK_POLL_INTERVAL", "")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "")
+	webhook, err := loadWebhook()
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, webhook.PollInterval)
+	assert.Equal(t, 10, webhook.MaxAttempts)
+
+	t.Setenv("WEBHOOK_POLL_INTERVAL", "30s")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	webhook, err = loadWebhook()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, webhook.PollInterval)
+	assert.Equal(t, 3, webhook.MaxAttempts)
+
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+	_, err = loadWebhook()
+	assert.Error(t, err)
+}
@@ -2,19 +2,47 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"time"
 	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/metrics"
 
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/time/rate"
 )
 
-// Client represents an Ethereum node client
-type Client struct {
+// maxBatchSize is the maximum number of calls sent in a single JSON-RPC batch
+const maxBatchSize = 100
+
+// ErrNotFound is returned when the requested transaction or block does not exist on the node
+var ErrNotFound = errors.New("not found")
+
+// Client defines methods for interacting with an Ethereum node
+type Client interface {
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetBlockNumberByTag(ctx context.Context, tag string) (uint64, error)
+	GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error)
+	GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error)
+	GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]*types.Receipt, error)
+	GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
+	GetTransactionReceipts(ctx context.Context, txHashes []string) ([]*types.Receipt, error)
+	GetTransactions(ctx context.Context, txHashes []string) ([]*types.Transaction, error)
+	GetHeaders(ctx context.Context, numbers []uint64) ([]*types.Header, error)
+	GetLogs(ctx context.Context, address string, topic string, fromBlock, toBlock uint64) ([]types.Log, error)
+	CallContract(ctx context.Context, address string, data []byte, blockNumber uint64) ([]byte, error)
+	Close()
+}
+
+// client represents an Ethereum node client
+type client struct {
 	cfg     *config.EthereumConfig
 	client  *ethclient.Client
 	httpURL string
@@ -22,7 +50,7 @@ type Client struct {
 }
 
 // NewClient creates a new Ethereum client
-func NewClient(cfg *config.EthereumConfig) (*Client, error) {
+func NewClient(cfg *config.EthereumConfig) (Client, error) {
 	if cfg.InfuraAPIKey == "" {
 		return nil, fmt.Errorf("infura API key cannot be empty")
 	}
@@ -30,7 +58,7 @@ func NewClient(cfg *config.EthereumConfig) (*Client, error) {
 	httpURL := fmt.Sprintf("%s/%s", cfg.BaseURL, cfg.InfuraAPIKey)
 	log.Printf("Initializing Ethereum client with endpoint: %s", httpURL)
 
-	client, err := ethclient.Dial(httpURL)
+	ethClient, err := ethclient.Dial(httpURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
 	}
@@ -40,19 +68,22 @@ func NewClient(cfg *config.EthereumConfig) (*Client, error) {
 	// Create rate limiter using configuration
 	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.RateBurst)
 
-	return &Client{
+	return &client{
 		cfg:     cfg,
-		client:  client,
+		client:  ethClient,
 		httpURL: httpURL,
 		limiter: limiter,
 	}, nil
 }
 
-// retry executes a function with exponential backoff retry logic
-func retry(attempts int, delay time.Duration, fn func() error) error {
+// retry executes a function with exponential backoff retry logic, recording each attempt as a request of
+// the given JSON-RPC method
+func retry(method string, attempts int, delay time.Duration, fn func() error) error {
 	var err error
 	for i := 0; i < attempts; i++ {
+		start := time.Now()
 		err = fn()
+		metrics.ObserveRequest(metrics.ClientEthereum, method, start, err)
 		if err == nil {
 			return nil
 		}
@@ -63,14 +94,14 @@ func retry(attempts int, delay time.Duration, fn func() error) error {
 }
 
 // GetLatestBlockNumber returns the latest block number from the Ethereum network
-func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+func (c *client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
 		return 0, fmt.Errorf("rate limiter wait: %w", err)
 	}
 
 	var blockNumber uint64
-	err := retry(c.cfg.RetryCount, time.Second, func() error {
+	err := retry("eth_blockNumber", c.cfg.RetryCount, time.Second, func() error {
 		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 		defer cancel()
 		var err error
@@ -85,15 +116,48 @@ func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
 	return blockNumber, nil
 }
 
+// GetBlockNumberByTag returns the number of the block the node reports for a "safe" or "finalized" tag
+func (c *client) GetBlockNumberByTag(ctx context.Context, tag string) (uint64, error) {
+	var number rpc.BlockNumber
+	switch tag {
+	case "safe":
+		number = rpc.SafeBlockNumber
+	case "finalized":
+		number = rpc.FinalizedBlockNumber
+	default:
+		return 0, fmt.Errorf("unsupported block tag %q", tag)
+	}
+
+	// Wait for rate limiter
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
+		return 0, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	var header *types.Header
+	err := retry("eth_getBlockByNumber", c.cfg.RetryCount, time.Second, func() error {
+		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+		var err error
+		header, err = c.client.HeaderByNumber(withTimeout, big.NewInt(number.Int64()))
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s block: %w", tag, err)
+	}
+
+	return header.Number.Uint64(), nil
+}
+
 // GetBlockByNumber retrieves a block by its number
-func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+func (c *client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
 	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
 		return nil, fmt.Errorf("rate limiter wait: %w", err)
 	}
 
 	var block *types.Block
-	err := retry(c.cfg.RetryCount, time.Second, func() error {
+	err := retry("eth_getBlockByNumber", c.cfg.RetryCount, time.Second, func() error {
 		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 		defer cancel()
 		var err error
@@ -110,14 +174,14 @@ func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*types.Bl
 }
 
 // GetBlockReceipts retrieves all transaction receipts for a block using eth_getBlockReceipts
-func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]*types.Receipt, error) {
+func (c *client) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]*types.Receipt, error) {
 	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
 		return nil, fmt.Errorf("rate limiter wait: %w", err)
 	}
 
 	var receipts []*types.Receipt
-	err := retry(c.cfg.RetryCount, time.Second, func() error {
+	err := retry("eth_getBlockReceipts", c.cfg.RetryCount, time.Second, func() error {
 		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
 		defer cancel()
 		blockHex := fmt.Sprintf("0x%x", blockNumber)
@@ -132,8 +196,227 @@ func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]*t
 	return receipts, nil
 }
 
+// GetHeaderByNumber retrieves a block header by its number
+func (c *client) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	// Wait for rate limiter
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	var header *types.Header
+	err := retry("eth_getBlockByNumber", c.cfg.RetryCount, time.Second, func() error {
+		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+		var err error
+		header, err = c.client.HeaderByNumber(withTimeout, new(big.Int).SetUint64(number))
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", number, err)
+	}
+
+	return header, nil
+}
+
+// GetTransactionReceipt retrieves the receipt of a mined transaction.
+// It returns ErrNotFound when the transaction is unknown or still pending.
+func (c *client) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	// Wait for rate limiter
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	var receipt *types.Receipt
+	notFound := false
+	err := retry("eth_getTransactionReceipt", c.cfg.RetryCount, time.Second, func() error {
+		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+		var err error
+		receipt, err = c.client.TransactionReceipt(withTimeout, common.HexToHash(txHash))
+		if errors.Is(err, geth.NotFound) {
+			// Not retryable: the node does not know the transaction
+			notFound = true
+			return nil
+		}
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt for transaction %s: %w", txHash, err)
+	}
+	if notFound {
+		return nil, fmt.Errorf("receipt for transaction %s: %w", txHash, ErrNotFound)
+	}
+
+	return receipt, nil
+}
+
+// GetTransactionReceipts retrieves the receipts of many mined transactions using batched JSON-RPC calls.
+// Receipts are returned in the order of txHashes; ErrNotFound is returned if any transaction is unknown.
+func (c *client) GetTransactionReceipts(ctx context.Context, txHashes []string) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(txHashes))
+	elems := make([]rpc.BatchElem, len(txHashes))
+	for i, txHash := range txHashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{common.HexToHash(txHash)},
+			Result: &receipts[i],
+		}
+	}
+
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+	for i, receipt := range receipts {
+		if receipt == nil {
+			return nil, fmt.Errorf("receipt for transaction %s: %w", txHashes[i], ErrNotFound)
+		}
+	}
+	return receipts, nil
+}
+
+// GetTransactions retrieves the bodies of many transactions using batched JSON-RPC calls.
+// Transactions are returned in the order of txHashes; ErrNotFound is returned if any transaction is unknown.
+func (c *client) GetTransactions(ctx context.Context, txHashes []string) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, len(txHashes))
+	elems := make([]rpc.BatchElem, len(txHashes))
+	for i, txHash := range txHashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{common.HexToHash(txHash)},
+			Result: &txs[i],
+		}
+	}
+
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	for i, tx := range txs {
+		if tx == nil {
+			return nil, fmt.Errorf("transaction %s: %w", txHashes[i], ErrNotFound)
+		}
+	}
+	return txs, nil
+}
+
+// GetHeaders retrieves many block headers using batched JSON-RPC calls, in the order of numbers
+func (c *client) GetHeaders(ctx context.Context, numbers []uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, number := range numbers {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(number), false},
+			Result: &headers[i],
+		}
+	}
+
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, fmt.Errorf("failed to get headers: %w", err)
+	}
+	for i, header := range headers {
+		if header == nil {
+			return nil, fmt.Errorf("header %d: %w", numbers[i], ErrNotFound)
+		}
+	}
+	return headers, nil
+}
+
+// batchCall sends the calls in batches of maxBatchSize, retrying failed batches. Each call of a batch
+// counts against the rate limit, so batches are no larger than the limiter's burst.
+func (c *client) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	batchSize := max(min(maxBatchSize, c.limiter.Burst()), 1)
+	for start := 0; start < len(elems); start += batchSize {
+		end := min(start+batchSize, len(elems))
+		batch := elems[start:end]
+
+		// Wait for rate limiter
+		if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, len(batch)); err != nil {
+			return fmt.Errorf("rate limiter wait: %w", err)
+		}
+
+		err := retry(batch[0].Method, c.cfg.RetryCount, time.Second, func() error {
+			withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+			defer cancel()
+			if err := c.client.Client().BatchCallContext(withTimeout, batch); err != nil {
+				return err
+			}
+			for _, elem := range batch {
+				if elem.Error != nil {
+					return elem.Error
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLogs retrieves the logs emitted by a contract within an inclusive block range.
+// When topic is non-empty only logs whose first topic matches are returned.
+func (c *client) GetLogs(ctx context.Context, address string, topic string, fromBlock, toBlock uint64) ([]types.Log, error) {
+	// Wait for rate limiter
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	query := geth.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{common.HexToAddress(address)},
+	}
+	if topic != "" {
+		query.Topics = [][]common.Hash{{common.HexToHash(topic)}}
+	}
+
+	var logs []types.Log
+	err := retry("eth_getLogs", c.cfg.RetryCount, time.Second, func() error {
+		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+		var err error
+		logs, err = c.client.FilterLogs(withTimeout, query)
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs for blocks %d-%d: %w", fromBlock, toBlock, err)
+	}
+
+	return logs, nil
+}
+
+// CallContract executes a read-only contract call (eth_call) against the state at the end of the given block
+func (c *client) CallContract(ctx context.Context, address string, data []byte, blockNumber uint64) ([]byte, error) {
+	// Wait for rate limiter
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEthereum, 1); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	to := common.HexToAddress(address)
+	msg := geth.CallMsg{To: &to, Data: data}
+
+	var result []byte
+	err := retry("eth_call", c.cfg.RetryCount, time.Second, func() error {
+		withTimeout, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+		var err error
+		result, err = c.client.CallContract(withTimeout, msg, new(big.Int).SetUint64(blockNumber))
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s at block %d: %w", address, blockNumber, err)
+	}
+
+	return result, nil
+}
+
 // Close closes the client connection
-func (c *Client) Close() {
+func (c *client) Close() {
 	if c.client != nil {
 		c.client.Close()
 	}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/metrics"
 
 	"github.com/go-resty/resty/v2"
 	"golang.org/x/time/rate"
@@ -42,12 +44,13 @@ func NewClient(cfg *config.EtherscanConfig) Client {
 
 func (c *client) GetTokenTransfers(ctx context.Context, address string, startBlock, endBlock uint64) ([]TokenTransfer, error) {
 	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
+	if err := metrics.Wait(ctx, c.limiter, metrics.ClientEtherscan, 1); err != nil {
 		return nil, fmt.Errorf("rate limiter wait: %w", err)
 	}
 
 	var response EtherscanResponse[TokenTransfer]
 
+	start := time.Now()
 	_, err := c.client.R().
 		SetContext(ctx).
 		SetQueryParams(map[string]string{
@@ -62,11 +65,13 @@ func (c *client) GetTokenTransfers(ctx context.Context, address string, startBlo
 		Get("")
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get token transfers: %w", err)
+		err = fmt.Errorf("failed to get token transfers: %w", err)
+	} else if response.Status != "1" {
+		err = fmt.Errorf("api error: %s", response.Message)
 	}
-
-	if response.Status != "1" {
-		return nil, fmt.Errorf("api error: %s", response.Message)
+	metrics.ObserveRequest(metrics.ClientEtherscan, "tokentx", start, err)
+	if err != nil {
+		return nil, err
 	}
 
 	return response.Result, nil
@@ -16,7 +16,7 @@ func TestGetTokenTransfers(t *testing.T) {
 	client := NewClient(&testConfig.EtherscanConfig)
 
 	// Define test parameters
-	address := testConfig.UniswapV3Pool
+	address := testConfig.Pools[0].Address
 	startBlock := uint64(0)
 	endBlock := uint64(21823108)
 
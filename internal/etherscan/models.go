@@ -50,6 +50,26 @@ func (t TokenTransfer) GetTimeStamp() time.Time {
 	return time.Unix(timestampInt, 0) // Convert Unix timestamp to time.Time
 }
 
+// GetNonce converts the Nonce field from string to uint64
+func (t TokenTransfer) GetNonce() uint64 {
+	nonce, err := strconv.ParseUint(t.Nonce, 10, 64)
+	if err != nil {
+		fmt.Printf("Failed to parse Nonce '%s' to uint64: %v\n", t.Nonce, err)
+		return 0 // Return 0 on error
+	}
+	return nonce
+}
+
+// GetTransactionIndex converts the TransactionIndex field from string to uint
+func (t TokenTransfer) GetTransactionIndex() uint {
+	index, err := strconv.ParseUint(t.TransactionIndex, 10, 32)
+	if err != nil {
+		fmt.Printf("Failed to parse TransactionIndex '%s' to uint: %v\n", t.TransactionIndex, err)
+		return 0 // Return 0 on error
+	}
+	return uint(index)
+}
+
 // GetGasUsed converts the GasUsed field from string to uint64
 func (t TokenTransfer) GetGasUsed() *big.Int {
 	gasUsed, ok := big.NewInt(0).SetString(t.GasUsed, 10)
@@ -0,0 +1,143 @@
+// Package metrics defines the Prometheus metrics of the sync, the external API clients and the HTTP API
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+const namespace = "uniswap_fee_tracker"
+
+// Client labels of the external API clients
+const (
+	ClientEtherscan = "etherscan"
+	ClientBinance   = "binance"
+	ClientEthereum  = "ethereum"
+)
+
+// Sync labels of the blocks processed counter
+const (
+	SyncLive       = "live"
+	SyncHistorical = "historical"
+)
+
+var (
+	// liveTip and liveProcessed are the latest block of the node and the last block processed by live sync
+	liveTip       atomic.Uint64
+	liveProcessed atomic.Uint64
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "live_sync_tip_block",
+		Help:      "Latest block reported by the node to live sync.",
+	}, func() float64 { return float64(liveTip.Load()) })
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "live_sync_last_processed_block",
+		Help:      "Last block processed by live sync.",
+	}, func() float64 { return float64(liveProcessed.Load()) })
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "live_sync_lag_blocks",
+		Help:      "Blocks live sync is behind the latest block of the node.",
+	}, liveSyncLag)
+
+	// BlocksProcessed counts the blocks processed by live and historical sync
+	BlocksProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_processed_total",
+		Help:      "Blocks processed, by sync.",
+	}, []string{"sync"})
+
+	// TransactionsSaved counts the transactions written to the database by their price status. Transactions
+	// that were already stored are not counted again.
+	TransactionsSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_saved_total",
+		Help:      "Transactions saved, by price status.",
+	}, []string{"status"})
+
+	clientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_requests_total",
+		Help:      "Requests sent to external APIs, by client and method.",
+	}, []string{"client", "method"})
+
+	clientErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_request_errors_total",
+		Help:      "Failed requests to external APIs, by client and method.",
+	}, []string{"client", "method"})
+
+	clientDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "client_request_duration_seconds",
+		Help:      "Latency of requests to external APIs, by client and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"client", "method"})
+
+	rateLimitWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "client_rate_limit_wait_seconds",
+		Help:      "Time spent waiting for the rate limiter of external API clients, by client.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+	}, []string{"client"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP API requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// liveSyncLag returns the blocks between the last block processed by live sync and the tip
+func liveSyncLag() float64 {
+	tip, processed := liveTip.Load(), liveProcessed.Load()
+	if processed == 0 || tip <= processed {
+		return 0
+	}
+	return float64(tip - processed)
+}
+
+// ObserveLiveSyncTip records the latest block reported by the node
+func ObserveLiveSyncTip(block uint64) {
+	liveTip.Store(block)
+}
+
+// ObserveLiveSyncBlock records the last block processed by live sync
+func ObserveLiveSyncBlock(block uint64) {
+	liveProcessed.Store(block)
+}
+
+// ObserveRequest records a request of an external API client that started at start and failed with err,
+// nil on success
+func ObserveRequest(client, method string, start time.Time, err error) {
+	clientRequests.WithLabelValues(client, method).Inc()
+	clientDuration.WithLabelValues(client, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		clientErrors.WithLabelValues(client, method).Inc()
+	}
+}
+
+// Wait blocks until the client's rate limiter allows n requests, recording the time spent waiting. n must not
+// exceed the limiter's burst.
+func Wait(ctx context.Context, limiter *rate.Limiter, client string, n int) error {
+	start := time.Now()
+	err := limiter.WaitN(ctx, n)
+	rateLimitWait.WithLabelValues(client).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// ObserveHTTPRequest records an HTTP API request by its route template, e.g. /api/v1/transactions/:txHash
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestObserveRequest(t *testing.T) {
+	requests := testutil.ToFloat64(clientRequests.WithLabelValues(ClientBinance, "test"))
+	errs := testutil.ToFloat64(clientErrors.WithLabelValues(ClientBinance, "test"))
+
+	ObserveRequest(ClientBinance, "test", time.Now(), nil)
+	ObserveRequest(ClientBinance, "test", time.Now(), errors.New("status 429"))
+
+	assert.Equal(t, requests+2, testutil.ToFloat64(clientRequests.WithLabelValues(ClientBinance, "test")))
+	assert.Equal(t, errs+1, testutil.ToFloat64(clientErrors.WithLabelValues(ClientBinance, "test")))
+}
+
+func TestWait(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 3)
+	require.NoError(t, Wait(context.Background(), limiter, ClientEtherscan, 1))
+	require.NoError(t, Wait(context.Background(), limiter, ClientEtherscan, 2))
+
+	// The limiter's tokens are used up, so waiting longer than the deadline fails right away
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Error(t, Wait(ctx, limiter, ClientEtherscan, 1))
+	assert.Equal(t, 1, testutil.CollectAndCount(rateLimitWait))
+}
+
+func TestLiveSyncLag(t *testing.T) {
+	ObserveLiveSyncBlock(100)
+	ObserveLiveSyncTip(112)
+	assert.Equal(t, 12.0, liveSyncLag())
+
+	// The node may report a tip the processor has already passed
+	ObserveLiveSyncTip(99)
+	assert.Zero(t, liveSyncLag())
+}
+
+func TestSyncProgressCollector(t *testing.T) {
+	collector := NewSyncProgressCollector(func() ([]SyncProgress, error) {
+		return []SyncProgress{{
+			ID:                    7,
+			PoolAddress:           "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			Status:                "RUNNING",
+			EndBlock:              2000,
+			LastProcessedBlock:    1250,
+			Ratio:                 0.25,
+			TransactionsProcessed: 42,
+		}}, nil
+	})
+
+	expected := `
+# HELP uniswap_fee_tracker_historical_sync_progress_ratio Share of the blocks of a historical sync range already synced.
+# TYPE uniswap_fee_tracker_historical_sync_progress_ratio gauge
+uniswap_fee_tracker_historical_sync_progress_ratio{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"} 0.25
+# HELP uniswap_fee_tracker_historical_sync_status Status of a historical sync range, 1 for its current status.
+# TYPE uniswap_fee_tracker_historical_sync_status gauge
+uniswap_fee_tracker_historical_sync_status{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",status="CANCELLED"} 0
+uniswap_fee_tracker_historical_sync_status{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",status="COMPLETED"} 0
+uniswap_fee_tracker_historical_sync_status{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",status="FAILED"} 0
+uniswap_fee_tracker_historical_sync_status{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",status="PAUSED"} 0
+uniswap_fee_tracker_historical_sync_status{id="7",pool="0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",status="RUNNING"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"uniswap_fee_tracker_historical_sync_progress_ratio", "uniswap_fee_tracker_historical_sync_status"))
+	assert.Equal(t, 9, testutil.CollectAndCount(collector))
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SyncProgress is the state of a historical sync range
+type SyncProgress struct {
+	ID                    uint
+	PoolAddress           string
+	Status                string
+	EndBlock              uint64
+	LastProcessedBlock    uint64
+	Ratio                 float64 // Share of the range synced, from 0 to 1
+	TransactionsProcessed uint64
+}
+
+var (
+	syncProgressLabels = []string{"id", "pool"}
+
+	syncProgressRatio = prometheus.NewDesc(prometheus.BuildFQName(namespace, "historical_sync", "progress_ratio"),
+		"Share of the blocks of a historical sync range already synced.", syncProgressLabels, nil)
+	syncProgressLastBlock = prometheus.NewDesc(prometheus.BuildFQName(namespace, "historical_sync", "last_processed_block"),
+		"Last block synced of a historical sync range.", syncProgressLabels, nil)
+	syncProgressEndBlock = prometheus.NewDesc(prometheus.BuildFQName(namespace, "historical_sync", "end_block"),
+		"Last block of a historical sync range.", syncProgressLabels, nil)
+	syncProgressTransactions = prometheus.NewDesc(prometheus.BuildFQName(namespace, "historical_sync", "transactions"),
+		"Transactions stored by a historical sync range.", syncProgressLabels, nil)
+	syncProgressStatus = prometheus.NewDesc(prometheus.BuildFQName(namespace, "historical_sync", "status"),
+		"Status of a historical sync range, 1 for its current status.", append(syncProgressLabels, "status"), nil)
+)
+
+// syncProgressStatuses are the statuses exported for every range, so a status change does not leave a stale series
+var syncProgressStatuses = []string{"RUNNING", "COMPLETED", "FAILED", "PAUSED", "CANCELLED"}
+
+// syncProgressCollector exports the historical sync ranges, listed from the database on each scrape
+type syncProgressCollector struct {
+	list func() ([]SyncProgress, error)
+}
+
+// NewSyncProgressCollector returns a collector exporting the historical sync ranges returned by list
+func NewSyncProgressCollector(list func() ([]SyncProgress, error)) prometheus.Collector {
+	return &syncProgressCollector{list: list}
+}
+
+func (c *syncProgressCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- syncProgressRatio
+	ch <- syncProgressLastBlock
+	ch <- syncProgressEndBlock
+	ch <- syncProgressTransactions
+	ch <- syncProgressStatus
+}
+
+func (c *syncProgressCollector) Collect(ch chan<- prometheus.Metric) {
+	progresses, err := c.list()
+	if err != nil {
+		log.Printf("Failed to list sync progress for metrics: %v", err)
+		ch <- prometheus.NewInvalidMetric(syncProgressRatio, err)
+		return
+	}
+
+	for _, progress := range progresses {
+		id := strconv.FormatUint(uint64(progress.ID), 10)
+		ch <- prometheus.MustNewConstMetric(syncProgressRatio, prometheus.GaugeValue, progress.Ratio, id, progress.PoolAddress)
+		ch <- prometheus.MustNewConstMetric(syncProgressLastBlock, prometheus.GaugeValue,
+			float64(progress.LastProcessedBlock), id, progress.PoolAddress)
+		ch <- prometheus.MustNewConstMetric(syncProgressEndBlock, prometheus.GaugeValue,
+			float64(progress.EndBlock), id, progress.PoolAddress)
+		ch <- prometheus.MustNewConstMetric(syncProgressTransactions, prometheus.GaugeValue,
+			float64(progress.TransactionsProcessed), id, progress.PoolAddress)
+		for _, status := range syncProgressStatuses {
+			value := 0.0
+			if status == progress.Status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(syncProgressStatus, prometheus.GaugeValue, value, id, progress.PoolAddress, status)
+		}
+	}
+}
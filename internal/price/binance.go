@@ -0,0 +1,69 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+	"uniswap-fee-tracker/internal/binance"
+)
+
+// binanceProvider prices pairs with Binance klines, using the finest interval available
+type binanceProvider struct {
+	client binance.Client
+	field  string // Candle field the price is taken from
+}
+
+// NewBinanceProvider creates a provider backed by the Binance kline API that prices with the given
+// candle field (close, open or vwap), defaulting to close
+func NewBinanceProvider(client binance.Client, field string) Provider {
+	if field == "" {
+		field = FieldClose
+	}
+	return &binanceProvider{client: client, field: field}
+}
+
+func (p *binanceProvider) Name() string {
+	return SourceBinance
+}
+
+// GetPrice returns the configured field of the finest kline containing the requested time
+func (p *binanceProvider) GetPrice(ctx context.Context, req Request) (*Quote, error) {
+	kline, err := p.client.GetPrice(ctx, req.Pair.Base+req.Pair.Quote, req.Timestamp)
+	if err != nil {
+		if errors.Is(err, binance.ErrNoKlines) {
+			return nil, fmt.Errorf("%w: %w", ErrNoPrice, err)
+		}
+		return nil, err
+	}
+
+	var price *big.Float
+	switch p.field {
+	case FieldOpen:
+		price = kline.Open
+	case FieldVWAP:
+		price = kline.VWAP()
+	default:
+		price = kline.Close
+	}
+	if price == nil {
+		return nil, fmt.Errorf("kline without %s price: %w", p.field, ErrNoPrice)
+	}
+	return &Quote{
+		Pair:      req.Pair,
+		Price:     price,
+		Timestamp: kline.OpenTime,
+		Interval:  kline.Interval,
+		Source:    SourceBinance,
+	}, nil
+}
+
+// Prefetch loads the klines of many timestamps in bulk when the client is cached
+func (p *binanceProvider) Prefetch(ctx context.Context, pair Pair, timestamps []time.Time) error {
+	cached, ok := p.client.(binance.CachedClient)
+	if !ok {
+		return nil
+	}
+	return cached.Prefetch(ctx, pair.Base+pair.Quote, timestamps)
+}
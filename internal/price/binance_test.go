@@ -0,0 +1,109 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/binance"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinanceProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/klines", r.URL.Path)
+		assert.Equal(t, "ETHUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "1700000000000", r.URL.Query().Get("startTime"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[[1700000000000,"2040.10","2041.00","2039.90","2040.55","12.5",1700000000999,"25500.1",42,"6.1","12450.3","0"]]`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(&config.BinanceConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	provider := NewBinanceProvider(client, FieldClose)
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, SourceBinance, quote.Source)
+	assert.Equal(t, "2040.55", quote.Price.Text('f', 2))
+	assert.Equal(t, int64(1700000000), quote.Timestamp.Unix())
+	assert.Equal(t, binance.Interval1s, quote.Interval)
+}
+
+func TestBinanceProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code":0,"msg":"Service unavailable from a restricted location"}`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(&config.BinanceConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := NewBinanceProvider(client, FieldClose).GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.Error(t, err)
+}
+
+func TestBinanceProviderFallsBackToCoarserIntervals(t *testing.T) {
+	// 2021-05-05 21:42:11 UTC, before 1s and 1m klines are available in this fake
+	timestamp := time.Unix(1620250931, 0)
+	var intervals []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		interval := r.URL.Query().Get("interval")
+		intervals = append(intervals, interval)
+		w.Header().Set("Content-Type", "application/json")
+		switch interval {
+		case "5m":
+			// The 5m candle opening at 21:40:00
+			assert.Equal(t, "1620250800000", r.URL.Query().Get("startTime"))
+			w.Write([]byte(`[[1620250800000,"3480.00","3490.00","3470.00","3485.50","100",1620251099999,"348000",900,"50","174000"]]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(&config.BinanceConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	quote, err := NewBinanceProvider(client, FieldClose).GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: timestamp})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1s", "1m", "5m"}, intervals)
+	assert.Equal(t, binance.Interval5m, quote.Interval)
+	assert.Equal(t, "3485.50", quote.Price.Text('f', 2))
+	assert.Equal(t, int64(1620250800), quote.Timestamp.Unix())
+}
+
+func TestBinanceProviderIgnoresLaterKlines(t *testing.T) {
+	// Binance returns the first kline after startTime when none exists at it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[[1800000000000,"1.0","1.0","1.0","1.0","1",1800000000999,"1",1,"1","1"]]`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(&config.BinanceConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := NewBinanceProvider(client, FieldClose).GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1620250931, 0)})
+	assert.ErrorIs(t, err, ErrNoPrice)
+	assert.ErrorIs(t, err, binance.ErrNoKlines)
+}
+
+func TestBinanceProviderPriceField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[[1700000000000,"2040.00","2041.00","2039.00","2040.50","10",1700000000999,"20403",42,"5","10201.5"]]`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient(&config.BinanceConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	req := Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)}
+	for field, expected := range map[string]string{
+		FieldClose: "2040.50",
+		FieldOpen:  "2040.00",
+		FieldVWAP:  "2040.30", // Quote volume / base volume
+	} {
+		quote, err := NewBinanceProvider(client, field).GetPrice(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, expected, quote.Price.Text('f', 2), field)
+	}
+}
@@ -0,0 +1,87 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/utils"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+// coinbaseGranularity is the smallest candle size offered by Coinbase, in seconds
+const coinbaseGranularity = 60
+
+// coinbaseProvider prices pairs with Coinbase Exchange candles
+type coinbaseProvider struct {
+	httpClient *resty.Client
+	limiter    *rate.Limiter
+}
+
+// NewCoinbaseProvider creates a provider backed by the Coinbase Exchange candles API
+func NewCoinbaseProvider(cfg *config.CoinbaseConfig) Provider {
+	httpClient := resty.New().
+		SetBaseURL(cfg.HTTPClientConfig.BaseURL).
+		SetTimeout(cfg.HTTPClientConfig.Timeout).
+		SetRetryCount(cfg.HTTPClientConfig.RetryCount).
+		SetRetryWaitTime(cfg.HTTPClientConfig.RetryWait)
+
+	return &coinbaseProvider{
+		httpClient: httpClient,
+		limiter:    rate.NewLimiter(rate.Limit(cfg.HTTPClientConfig.RateLimit), cfg.HTTPClientConfig.RateBurst),
+	}
+}
+
+func (p *coinbaseProvider) Name() string {
+	return SourceCoinbase
+}
+
+// GetPrice returns the close of the 1m candle containing the requested time
+func (p *coinbaseProvider) GetPrice(ctx context.Context, req Request) (*Quote, error) {
+	// Wait for rate limiter
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	start := req.Timestamp.UTC().Truncate(coinbaseGranularity * time.Second)
+	end := start.Add(coinbaseGranularity * time.Second)
+
+	// Each candle is [time, low, high, open, close, volume], newest first
+	var candles [][]interface{}
+	resp, err := p.httpClient.R().
+		SetContext(ctx).
+		SetPathParam("product", req.Pair.Base+"-"+req.Pair.Quote).
+		SetQueryParams(map[string]string{
+			"granularity": fmt.Sprint(coinbaseGranularity),
+			"start":       start.Format(time.RFC3339),
+			"end":         end.Format(time.RFC3339),
+		}).
+		SetResult(&candles).
+		Get("/products/{product}/candles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candles: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("failed to fetch candles: status %d", resp.StatusCode())
+	}
+
+	for _, candle := range candles {
+		if len(candle) < 5 || utils.MustParseInt64(candle[0]) != start.Unix() {
+			continue
+		}
+		closePrice, err := utils.ParseBigFloat(candle[4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid close price: %w", err)
+		}
+		return &Quote{
+			Pair:      req.Pair,
+			Price:     closePrice,
+			Timestamp: start,
+			Interval:  "1m",
+			Source:    SourceCoinbase,
+		}, nil
+	}
+	return nil, fmt.Errorf("no %s candle at %s: %w", req.Pair, start.Format(time.RFC3339), ErrNoPrice)
+}
@@ -0,0 +1,57 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinbaseProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/products/ETH-USDT/candles", r.URL.Path)
+		assert.Equal(t, "60", r.URL.Query().Get("granularity"))
+		assert.Equal(t, "2023-11-14T22:13:00Z", r.URL.Query().Get("start"))
+		w.Header().Set("Content-Type", "application/json")
+		// Newest first: [time, low, high, open, close, volume]
+		w.Write([]byte(`[[1700000040,2040.1,2042.3,2041.0,2042.0,3.2],[1699999980,2039.5,2041.2,2040.0,2041.15,5.1]]`))
+	}))
+	defer server.Close()
+
+	provider := NewCoinbaseProvider(&config.CoinbaseConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+
+	// 22:13:20 falls in the candle opening at 22:13:00
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, SourceCoinbase, quote.Source)
+	assert.Equal(t, "2041.15", quote.Price.Text('f', 2))
+	assert.Equal(t, int64(1699999980), quote.Timestamp.Unix())
+}
+
+func TestCoinbaseProviderMissingCandle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	provider := NewCoinbaseProvider(&config.CoinbaseConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.ErrorIs(t, err, ErrNoPrice)
+}
+
+func TestCoinbaseProviderHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := NewCoinbaseProvider(&config.CoinbaseConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.ErrorContains(t, err, "status 429")
+}
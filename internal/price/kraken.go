@@ -0,0 +1,109 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/utils"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+// krakenInterval is the candle size requested from Kraken, in minutes
+const krakenInterval = 1
+
+// krakenOHLCResponse is the body of the Kraken OHLC endpoint. Result maps the pair name to its candles
+// and also holds a "last" cursor.
+type krakenOHLCResponse struct {
+	Error  []string               `json:"error"`
+	Result map[string]interface{} `json:"result"`
+}
+
+// krakenProvider prices pairs with Kraken OHLC candles. Kraken only serves the most recent 720 candles
+// of an interval, so it is mainly useful as a fallback for live prices.
+type krakenProvider struct {
+	httpClient *resty.Client
+	limiter    *rate.Limiter
+}
+
+// NewKrakenProvider creates a provider backed by the Kraken OHLC API
+func NewKrakenProvider(cfg *config.KrakenConfig) Provider {
+	httpClient := resty.New().
+		SetBaseURL(cfg.HTTPClientConfig.BaseURL).
+		SetTimeout(cfg.HTTPClientConfig.Timeout).
+		SetRetryCount(cfg.HTTPClientConfig.RetryCount).
+		SetRetryWaitTime(cfg.HTTPClientConfig.RetryWait)
+
+	return &krakenProvider{
+		httpClient: httpClient,
+		limiter:    rate.NewLimiter(rate.Limit(cfg.HTTPClientConfig.RateLimit), cfg.HTTPClientConfig.RateBurst),
+	}
+}
+
+func (p *krakenProvider) Name() string {
+	return SourceKraken
+}
+
+// GetPrice returns the close of the 1m candle containing the requested time
+func (p *krakenProvider) GetPrice(ctx context.Context, req Request) (*Quote, error) {
+	// Wait for rate limiter
+	if err := p.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait: %w", err)
+	}
+
+	start := req.Timestamp.UTC().Truncate(krakenInterval * time.Minute)
+
+	var body krakenOHLCResponse
+	resp, err := p.httpClient.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"pair":     req.Pair.Base + req.Pair.Quote,
+			"interval": strconv.Itoa(krakenInterval),
+			// since is exclusive, so ask for candles from just before the one we need
+			"since": strconv.FormatInt(start.Unix()-1, 10),
+		}).
+		SetResult(&body).
+		Get("/0/public/OHLC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OHLC: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("failed to fetch OHLC: status %d", resp.StatusCode())
+	}
+	if len(body.Error) > 0 {
+		return nil, fmt.Errorf("failed to fetch OHLC: %s", strings.Join(body.Error, "; "))
+	}
+
+	// Each candle is [time, open, high, low, close, vwap, volume, count]
+	for key, value := range body.Result {
+		if key == "last" {
+			continue
+		}
+		candles, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, raw := range candles {
+			candle, ok := raw.([]interface{})
+			if !ok || len(candle) < 5 || utils.MustParseInt64(candle[0]) != start.Unix() {
+				continue
+			}
+			closePrice, err := utils.ParseBigFloat(candle[4])
+			if err != nil {
+				return nil, fmt.Errorf("invalid close price: %w", err)
+			}
+			return &Quote{
+				Pair:      req.Pair,
+				Price:     closePrice,
+				Timestamp: start,
+				Interval:  "1m",
+				Source:    SourceKraken,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s candle at %s: %w", req.Pair, start.Format(time.RFC3339), ErrNoPrice)
+}
@@ -0,0 +1,61 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKrakenProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/0/public/OHLC", r.URL.Path)
+		assert.Equal(t, "ETHUSDT", r.URL.Query().Get("pair"))
+		assert.Equal(t, "1", r.URL.Query().Get("interval"))
+		assert.Equal(t, "1699999979", r.URL.Query().Get("since"))
+		w.Header().Set("Content-Type", "application/json")
+		// [time, open, high, low, close, vwap, volume, count]
+		w.Write([]byte(`{"error":[],"result":{"ETHUSDT":[
+			[1699999980,"2040.00","2041.20","2039.50","2041.05","2040.6","5.1",12],
+			[1700000040,"2041.05","2042.30","2040.10","2042.00","2041.2","3.2",8]
+		],"last":1700000040}}`))
+	}))
+	defer server.Close()
+
+	provider := NewKrakenProvider(&config.KrakenConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, SourceKraken, quote.Source)
+	assert.Equal(t, "2041.05", quote.Price.Text('f', 2))
+	assert.Equal(t, int64(1699999980), quote.Timestamp.Unix())
+}
+
+func TestKrakenProviderAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"error":["EQuery:Unknown asset pair"]}`))
+	}))
+	defer server.Close()
+
+	provider := NewKrakenProvider(&config.KrakenConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.ErrorContains(t, err, "EQuery:Unknown asset pair")
+}
+
+func TestKrakenProviderMissingCandle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"error":[],"result":{"ETHUSDT":[],"last":1700000040}}`))
+	}))
+	defer server.Close()
+
+	provider := NewKrakenProvider(&config.KrakenConfig{HTTPClientConfig: testHTTPConfig(server.URL)})
+	_, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.ErrorIs(t, err, ErrNoPrice)
+}
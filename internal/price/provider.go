@@ -0,0 +1,181 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"uniswap-fee-tracker/internal/binance"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/ethereum"
+)
+
+// Provider names used in PRICE_PROVIDERS and recorded as the price source of transactions
+const (
+	SourceBinance  = "binance"
+	SourceCoinbase = "coinbase"
+	SourceKraken   = "kraken"
+	SourceUniswap  = "uniswap" // Derived on-chain from a tracked WETH/stablecoin pool
+)
+
+// Candle fields a price can be taken from
+const (
+	FieldClose = "close"
+	FieldOpen  = "open"
+	FieldVWAP  = "vwap" // Volume-weighted average price
+)
+
+// ErrNoPrice is returned when a provider has no price for the requested pair and time
+var ErrNoPrice = errors.New("no price available")
+
+// Pair identifies a base asset priced in a quote asset
+type Pair struct {
+	Base  string
+	Quote string
+}
+
+// ETHUSDT is the pair used to convert fees paid in ETH
+var ETHUSDT = Pair{Base: "ETH", Quote: "USDT"}
+
+func (p Pair) String() string {
+	return p.Base + "/" + p.Quote
+}
+
+// Request describes the price to look up
+type Request struct {
+	Pair        Pair
+	Timestamp   time.Time
+	BlockNumber uint64 // Block the price is needed for, used by on-chain providers
+}
+
+// Quote is a price returned by a provider
+type Quote struct {
+	Pair      Pair
+	Price     *big.Float
+	Timestamp time.Time // Open time of the candle the price was taken from
+	Interval  string    // Candle interval the price was taken from, empty for on-chain prices
+	Source    string
+}
+
+// Provider fetches the historical price of a pair
+type Provider interface {
+	Name() string
+	GetPrice(ctx context.Context, req Request) (*Quote, error)
+}
+
+// Prefetcher is implemented by providers that can load many prices in bulk ahead of GetPrice calls
+type Prefetcher interface {
+	Prefetch(ctx context.Context, pair Pair, timestamps []time.Time) error
+}
+
+// chain queries providers in priority order and returns the first price found
+type chain struct {
+	providers []Provider
+}
+
+// NewChain returns a provider that falls back to the next provider whenever one fails
+func NewChain(providers ...Provider) Provider {
+	return &chain{providers: providers}
+}
+
+func (c *chain) Name() string {
+	names := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// GetPrice returns the quote of the first provider that succeeds, or the errors of all providers
+func (c *chain) GetPrice(ctx context.Context, req Request) (*Quote, error) {
+	var errs []error
+	for _, provider := range c.providers {
+		quote, err := provider.GetPrice(ctx, req)
+		if err == nil {
+			return quote, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return nil, fmt.Errorf("failed to get %s price at %s: %w", req.Pair, req.Timestamp.UTC().Format(time.RFC3339), errors.Join(errs...))
+}
+
+// Prefetch prefetches the prices of every provider that supports it
+func (c *chain) Prefetch(ctx context.Context, pair Pair, timestamps []time.Time) error {
+	var errs []error
+	for _, provider := range c.providers {
+		if prefetcher, ok := provider.(Prefetcher); ok {
+			if err := prefetcher.Prefetch(ctx, pair, timestamps); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Select returns a chain of the named providers, in the given order, picked from the given providers
+// and the members of chains among them
+func Select(names []string, providers ...Provider) (Provider, error) {
+	available := make(map[string]Provider)
+	for _, provider := range providers {
+		if c, ok := provider.(*chain); ok {
+			for _, member := range c.providers {
+				available[member.Name()] = member
+			}
+			continue
+		}
+		if provider != nil {
+			available[provider.Name()] = provider
+		}
+	}
+
+	selected := make([]Provider, 0, len(names))
+	for _, name := range names {
+		provider, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("price provider %q is not configured", name)
+		}
+		selected = append(selected, provider)
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("at least one price provider is required")
+	}
+	return NewChain(selected...), nil
+}
+
+// New builds the provider chain configured in cfg.PriceProviders. The node client is used by the
+// on-chain uniswap provider and candles, when not nil, persists the klines fetched from Binance.
+func New(cfg *config.Config, nodeClient ethereum.Client, candles binance.CandleStore) (Provider, error) {
+	providers := make([]Provider, 0, len(cfg.PriceProviders))
+	for _, name := range cfg.PriceProviders {
+		switch name {
+		case SourceBinance:
+			client := binance.NewCachedClient(binance.NewClient(&cfg.BinanceConfig), candles, cfg.BinanceConfig.CacheSize)
+			providers = append(providers, NewBinanceProvider(client, cfg.BinanceConfig.PriceField))
+		case SourceCoinbase:
+			providers = append(providers, NewCoinbaseProvider(&cfg.CoinbaseConfig))
+		case SourceKraken:
+			providers = append(providers, NewKrakenProvider(&cfg.KrakenConfig))
+		case SourceUniswap:
+			pool, ok := FindPricePool(cfg.Pools)
+			if !ok {
+				return nil, fmt.Errorf("price provider %q requires a tracked WETH/stablecoin pool", name)
+			}
+			provider, err := NewUniswapProvider(nodeClient, pool)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		default:
+			return nil, fmt.Errorf("unknown price provider %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one price provider is required")
+	}
+	return NewChain(providers...), nil
+}
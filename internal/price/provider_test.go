@@ -0,0 +1,110 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTPConfig points a provider at an httptest server without retries or throttling
+func testHTTPConfig(url string) config.HTTPClientConfig {
+	return config.HTTPClientConfig{
+		BaseURL:   url,
+		RateLimit: 100,
+		RateBurst: 10,
+		Timeout:   5 * time.Second,
+	}
+}
+
+// staticProvider returns a fixed price or error
+type staticProvider struct {
+	name  string
+	price *big.Float
+	err   error
+	calls int
+}
+
+func (p *staticProvider) Name() string {
+	return p.name
+}
+
+func (p *staticProvider) GetPrice(_ context.Context, req Request) (*Quote, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Quote{Pair: req.Pair, Price: p.price, Timestamp: req.Timestamp, Source: p.name}, nil
+}
+
+func TestChainFallsBackInPriorityOrder(t *testing.T) {
+	failing := &staticProvider{name: "first", err: errors.New("geo-blocked")}
+	second := &staticProvider{name: "second", price: big.NewFloat(2000)}
+	third := &staticProvider{name: "third", price: big.NewFloat(2001)}
+	provider := NewChain(failing, second, third)
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, "second", quote.Source)
+	assert.Equal(t, "2000", quote.Price.String())
+	assert.Equal(t, 1, failing.calls)
+	assert.Equal(t, 0, third.calls)
+	assert.Equal(t, "first,second,third", provider.Name())
+}
+
+func TestChainReturnsAllErrors(t *testing.T) {
+	provider := NewChain(
+		&staticProvider{name: "first", err: errors.New("geo-blocked")},
+		&staticProvider{name: "second", err: ErrNoPrice},
+	)
+
+	_, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoPrice)
+	assert.Contains(t, err.Error(), "first: geo-blocked")
+}
+
+func TestNew(t *testing.T) {
+	provider, err := New(&config.Config{PriceProviders: []string{SourceKraken, SourceBinance}}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "kraken,binance", provider.Name())
+
+	_, err = New(&config.Config{PriceProviders: []string{"ftx"}}, nil, nil)
+	assert.Error(t, err)
+
+	_, err = New(&config.Config{PriceProviders: []string{SourceUniswap}, Pools: config.DefaultPools}, nil, nil)
+	assert.NoError(t, err)
+
+	_, err = New(&config.Config{PriceProviders: []string{SourceUniswap}, Pools: []config.PoolConfig{{
+		Name:   "WBTC-WETH",
+		Token0: config.TokenConfig{Symbol: "WBTC", Decimals: 8},
+		Token1: config.TokenConfig{Symbol: "WETH", Decimals: 18},
+	}}}, nil, nil)
+	assert.Error(t, err)
+
+	_, err = New(&config.Config{}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestSelect(t *testing.T) {
+	binance := &staticProvider{name: SourceBinance, price: big.NewFloat(2000)}
+	kraken := &staticProvider{name: SourceKraken, price: big.NewFloat(2001)}
+	uniswap := &staticProvider{name: SourceUniswap, price: big.NewFloat(1999)}
+
+	provider, err := Select([]string{SourceUniswap, SourceKraken}, NewChain(binance, kraken), uniswap)
+	require.NoError(t, err)
+	assert.Equal(t, "uniswap,kraken", provider.Name())
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, SourceUniswap, quote.Source)
+	assert.Equal(t, 0, binance.calls)
+
+	_, err = Select([]string{SourceCoinbase}, NewChain(binance, kraken))
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/ethereum"
+)
+
+const (
+	// uniswapSwapTopic is the topic0 of Uniswap V3 Swap events, whose third data word is sqrtPriceX96
+	uniswapSwapTopic = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
+	// pricePrecision is the big.Float precision used for sqrtPriceX96 arithmetic
+	pricePrecision = 256
+)
+
+// slot0Selector is the selector of the pool's slot0() view, whose first return word is sqrtPriceX96
+var slot0Selector = []byte{0x38, 0x50, 0xc7, 0xbd}
+
+// usdStablecoins are priced at par with USD, so a pool pairing WETH with any of them prices ETH in USDT
+var usdStablecoins = map[string]bool{"USDC": true, "USDT": true, "DAI": true}
+
+// uniswapProvider derives the ETH price from the sqrtPriceX96 of a Uniswap V3 WETH/stablecoin pool
+type uniswapProvider struct {
+	client ethereum.Client
+	pool   config.PoolConfig
+	stable string // Symbol of the pool's stablecoin
+}
+
+// FindPricePool returns the first pool pairing WETH with a USD stablecoin
+func FindPricePool(pools []config.PoolConfig) (config.PoolConfig, bool) {
+	for _, pool := range pools {
+		if _, ok := stablecoinOf(pool); ok {
+			return pool, true
+		}
+	}
+	return config.PoolConfig{}, false
+}
+
+// NewUniswapProvider creates a provider pricing ETH from the state of a WETH/stablecoin pool at each block
+func NewUniswapProvider(client ethereum.Client, pool config.PoolConfig) (Provider, error) {
+	stable, ok := stablecoinOf(pool)
+	if !ok {
+		return nil, fmt.Errorf("pool %s does not pair WETH with a USD stablecoin", pool.Name)
+	}
+	return &uniswapProvider{client: client, pool: pool, stable: stable}, nil
+}
+
+func (p *uniswapProvider) Name() string {
+	return SourceUniswap
+}
+
+// GetPrice returns the pool price at the end of the requested block, taken from the block's last Swap
+// event or, when the pool was not traded in the block, from slot0
+func (p *uniswapProvider) GetPrice(ctx context.Context, req Request) (*Quote, error) {
+	if !isETH(req.Pair.Base) || !usdStablecoins[strings.ToUpper(req.Pair.Quote)] {
+		return nil, fmt.Errorf("pool %s cannot price %s: %w", p.pool.Name, req.Pair, ErrNoPrice)
+	}
+	if req.BlockNumber == 0 {
+		return nil, fmt.Errorf("block number required for on-chain price: %w", ErrNoPrice)
+	}
+
+	sqrtPriceX96, err := p.sqrtPriceX96(ctx, req.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if sqrtPriceX96.Sign() == 0 {
+		return nil, fmt.Errorf("pool %s is not initialized at block %d: %w", p.pool.Name, req.BlockNumber, ErrNoPrice)
+	}
+
+	return &Quote{
+		Pair:      Pair{Base: "ETH", Quote: p.stable},
+		Price:     ETHPriceFromSqrtPriceX96(sqrtPriceX96, p.pool),
+		Timestamp: req.Timestamp,
+		Source:    SourceUniswap,
+	}, nil
+}
+
+// sqrtPriceX96 returns the pool's sqrtPriceX96 at the end of the given block
+func (p *uniswapProvider) sqrtPriceX96(ctx context.Context, blockNumber uint64) (*big.Int, error) {
+	logs, err := p.client.GetLogs(ctx, p.pool.Address, uniswapSwapTopic, blockNumber, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swaps of block %d: %w", blockNumber, err)
+	}
+	for i := len(logs) - 1; i >= 0; i-- {
+		if !logs[i].Removed && len(logs[i].Data) >= 3*32 {
+			return new(big.Int).SetBytes(logs[i].Data[64:96]), nil
+		}
+	}
+
+	result, err := p.client.CallContract(ctx, p.pool.Address, slot0Selector, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call slot0: %w", err)
+	}
+	if len(result) < 32 {
+		return nil, fmt.Errorf("slot0 returned %d bytes", len(result))
+	}
+	return new(big.Int).SetBytes(result[:32]), nil
+}
+
+// ETHPriceFromSqrtPriceX96 converts a pool's sqrtPriceX96 into the decimal-adjusted price of its WETH
+// token in the other token. sqrtPriceX96 encodes sqrt(token1/token0) in raw units as a Q64.96 number.
+func ETHPriceFromSqrtPriceX96(sqrtPriceX96 *big.Int, pool config.PoolConfig) *big.Float {
+	sqrtPrice := new(big.Float).SetPrec(pricePrecision).SetInt(sqrtPriceX96)
+	q96 := new(big.Float).SetPrec(pricePrecision).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))
+	sqrtPrice.Quo(sqrtPrice, q96)
+
+	// token1 per token0, adjusted from raw units by 10^(decimals0 - decimals1)
+	price := new(big.Float).SetPrec(pricePrecision).Mul(sqrtPrice, sqrtPrice)
+	price.Mul(price, pow10(pool.Token0.Decimals))
+	price.Quo(price, pow10(pool.Token1.Decimals))
+
+	if isETH(pool.Token0.Symbol) {
+		return price
+	}
+	return new(big.Float).SetPrec(pricePrecision).Quo(big.NewFloat(1).SetPrec(pricePrecision), price)
+}
+
+// stablecoinOf returns the symbol of the pool's stablecoin when the pool pairs WETH with one
+func stablecoinOf(pool config.PoolConfig) (string, bool) {
+	token0, token1 := strings.ToUpper(pool.Token0.Symbol), strings.ToUpper(pool.Token1.Symbol)
+	switch {
+	case isETH(token0) && usdStablecoins[token1]:
+		return token1, true
+	case isETH(token1) && usdStablecoins[token0]:
+		return token0, true
+	}
+	return "", false
+}
+
+func isETH(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+	return symbol == "ETH" || symbol == "WETH"
+}
+
+func pow10(exponent int) *big.Float {
+	return new(big.Float).SetPrec(pricePrecision).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+}
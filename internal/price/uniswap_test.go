@@ -0,0 +1,123 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sqrtPriceX96 values for ETH at 2000 USD
+const (
+	usdcWETHSqrtPriceX96 = "1771595571142957102961017161607260" // token0 USDC (6), token1 WETH (18)
+	wethUSDTSqrtPriceX96 = "3543191142285914205922034"          // token0 WETH (18), token1 USDT (6)
+)
+
+var wethUSDTPool = config.PoolConfig{
+	Name:    "WETH-USDT-0.05%",
+	Address: "0x11b815efB8f581194ae79006d24E0d814B7697F6",
+	Token0:  config.TokenConfig{Symbol: "WETH", Decimals: 18},
+	Token1:  config.TokenConfig{Symbol: "USDT", Decimals: 6},
+}
+
+// fakeNode serves swap logs and slot0 for a single pool
+type fakeNode struct {
+	ethereum.Client
+	logs  []types.Log
+	slot0 *big.Int
+	calls int
+}
+
+func (n *fakeNode) GetLogs(context.Context, string, string, uint64, uint64) ([]types.Log, error) {
+	return n.logs, nil
+}
+
+func (n *fakeNode) CallContract(_ context.Context, _ string, data []byte, _ uint64) ([]byte, error) {
+	n.calls++
+	if common.Bytes2Hex(data) != "3850c7bd" {
+		return nil, fmt.Errorf("unexpected call data %x", data)
+	}
+	return common.LeftPadBytes(n.slot0.Bytes(), 32), nil
+}
+
+func mustBigInt(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok)
+	return v
+}
+
+// swapLog builds a Swap event whose sqrtPriceX96 word is set
+func swapLog(sqrtPriceX96 *big.Int) types.Log {
+	data := make([]byte, 5*32)
+	copy(data[64:96], common.LeftPadBytes(sqrtPriceX96.Bytes(), 32))
+	return types.Log{Topics: []common.Hash{common.HexToHash(uniswapSwapTopic)}, Data: data}
+}
+
+func TestETHPriceFromSqrtPriceX96(t *testing.T) {
+	price := ETHPriceFromSqrtPriceX96(mustBigInt(t, usdcWETHSqrtPriceX96), config.DefaultPools[0])
+	assert.Equal(t, "2000.00", price.Text('f', 2))
+
+	price = ETHPriceFromSqrtPriceX96(mustBigInt(t, wethUSDTSqrtPriceX96), wethUSDTPool)
+	assert.Equal(t, "2000.00", price.Text('f', 2))
+}
+
+func TestUniswapProviderUsesLastSwapOfBlock(t *testing.T) {
+	node := &fakeNode{logs: []types.Log{
+		swapLog(big.NewInt(1)),
+		swapLog(mustBigInt(t, usdcWETHSqrtPriceX96)),
+	}}
+	provider, err := NewUniswapProvider(node, config.DefaultPools[0])
+	require.NoError(t, err)
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0), BlockNumber: 18000000})
+	require.NoError(t, err)
+	assert.Equal(t, SourceUniswap, quote.Source)
+	assert.Equal(t, Pair{Base: "ETH", Quote: "USDC"}, quote.Pair)
+	assert.Equal(t, "2000.00", quote.Price.Text('f', 2))
+	assert.Equal(t, 0, node.calls)
+}
+
+func TestUniswapProviderFallsBackToSlot0(t *testing.T) {
+	node := &fakeNode{slot0: mustBigInt(t, wethUSDTSqrtPriceX96)}
+	provider, err := NewUniswapProvider(node, wethUSDTPool)
+	require.NoError(t, err)
+
+	quote, err := provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0), BlockNumber: 18000000})
+	require.NoError(t, err)
+	assert.Equal(t, "2000.00", quote.Price.Text('f', 2))
+	assert.Equal(t, 1, node.calls)
+}
+
+func TestUniswapProviderRejectsUnsupportedRequests(t *testing.T) {
+	provider, err := NewUniswapProvider(&fakeNode{}, config.DefaultPools[0])
+	require.NoError(t, err)
+
+	_, err = provider.GetPrice(context.Background(), Request{Pair: Pair{Base: "BTC", Quote: "USDT"}, BlockNumber: 1})
+	assert.ErrorIs(t, err, ErrNoPrice)
+
+	_, err = provider.GetPrice(context.Background(), Request{Pair: ETHUSDT, Timestamp: time.Unix(1700000000, 0)})
+	assert.ErrorIs(t, err, ErrNoPrice)
+}
+
+func TestFindPricePool(t *testing.T) {
+	wbtcWETH := config.PoolConfig{
+		Name:   "WBTC-WETH",
+		Token0: config.TokenConfig{Symbol: "WBTC", Decimals: 8},
+		Token1: config.TokenConfig{Symbol: "WETH", Decimals: 18},
+	}
+
+	pool, ok := FindPricePool([]config.PoolConfig{wbtcWETH, wethUSDTPool})
+	require.True(t, ok)
+	assert.Equal(t, wethUSDTPool.Name, pool.Name)
+
+	_, ok = FindPricePool([]config.PoolConfig{wbtcWETH})
+	assert.False(t, ok)
+}
@@ -0,0 +1,48 @@
+package syncer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AddressFeeFilter selects the transactions sent by an address whose fees are summarized
+type AddressFeeFilter struct {
+	Address  string
+	FromTime *time.Time
+	ToTime   *time.Time
+}
+
+// AddressFeeSummary aggregates the fees an address paid for its transactions. Unpriced transactions are
+// counted in TransactionCount but only contribute to the ETH figures.
+type AddressFeeSummary struct {
+	TransactionCount int64
+	PricedCount      int64 // Transactions with a USDT fee
+	RevertedCount    int64
+	TotalFeeETH      *BigFloat
+	TotalFeeUSDT     *BigFloat
+	AverageFeeETH    *BigFloat
+	AverageFeeUSDT   *BigFloat
+	FirstSeen        *time.Time
+	LastSeen         *time.Time
+}
+
+// Normalize validates the filter and lowercases its address
+func (f *AddressFeeFilter) Normalize() error {
+	if !addressPattern.MatchString(f.Address) {
+		return fmt.Errorf("%w: invalid address %q", ErrInvalidFilter, f.Address)
+	}
+	f.Address = strings.ToLower(f.Address)
+	if f.FromTime != nil && f.ToTime != nil && f.FromTime.After(*f.ToTime) {
+		return fmt.Errorf("%w: from_time is after to_time", ErrInvalidFilter)
+	}
+	return nil
+}
+
+// AddressFeeSummary returns the fees paid by the transactions an address sent in the filter's time window
+func (s *Service) AddressFeeSummary(filter AddressFeeFilter) (*AddressFeeSummary, error) {
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAddressFeeSummary(filter)
+}
@@ -0,0 +1,141 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"uniswap-fee-tracker/internal/price"
+)
+
+// FeeCurrencies returns the currencies fees are converted to in addition to USDT
+func (s *Service) FeeCurrencies() []string {
+	return s.config.FeeCurrencies
+}
+
+// MissingFeesFilter selects priced transactions without a fee conversion for some of the currencies
+type MissingFeesFilter struct {
+	Currencies  []string
+	AfterBlock  uint64 // Keyset cursor: only transactions after (AfterBlock, AfterTxHash)
+	AfterTxHash string
+	Limit       int
+}
+
+// convertFees converts the fees of priced transactions mined in the same block to every configured
+// currency, with one ETH quote per currency. Failures are only logged: missing conversions are filled
+// in by the price reconciler.
+func (s *Service) convertFees(ctx context.Context, txs []*Transaction, blockNumber uint64, timestamp time.Time) {
+	if !hasFeeETH(txs) {
+		return
+	}
+	for _, currency := range s.config.FeeCurrencies {
+		quote, err := s.currencyPrice(ctx, currency, blockNumber, timestamp)
+		if err != nil {
+			log.Printf("Failed to convert fees of block %d to %s: %v", blockNumber, currency, err)
+			continue
+		}
+		for _, tx := range txs {
+			tx.ApplyCurrencyQuote(quote)
+		}
+	}
+}
+
+// backfillFeeCurrencies converts the fees of the next batch of priced transactions after the cursor that
+// miss a configured currency, e.g. one added after they were stored, and saves the new conversions. The
+// cursor wraps around once every transaction was visited, so a conversion that failed is only retried on
+// the next lap. It returns the number of transactions converted.
+func (s *Service) backfillFeeCurrencies(ctx context.Context, cursor *MissingFeesFilter) (int, error) {
+	if len(s.config.FeeCurrencies) == 0 {
+		return 0, nil
+	}
+	cursor.Currencies = s.config.FeeCurrencies
+	cursor.Limit = reconcileBatchSize
+	txs, err := s.repo.GetTransactionsMissingFees(*cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions missing fees: %w", err)
+	}
+	if len(txs) < cursor.Limit {
+		cursor.AfterBlock, cursor.AfterTxHash = 0, ""
+	} else {
+		last := txs[len(txs)-1]
+		cursor.AfterBlock, cursor.AfterTxHash = last.BlockNumber, last.TxHash
+	}
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	byTimestamp := make(map[int64][]*Transaction)
+	var times []time.Time
+	for _, tx := range txs {
+		ts := tx.Timestamp.Unix()
+		if _, ok := byTimestamp[ts]; !ok {
+			times = append(times, tx.Timestamp)
+		}
+		byTimestamp[ts] = append(byTimestamp[ts], tx)
+	}
+	s.prefetchFeeCurrencies(ctx, times)
+
+	var converted []*Transaction
+	for _, timestamp := range times {
+		group := byTimestamp[timestamp.Unix()]
+		changed := make(map[*Transaction]bool)
+		for _, currency := range s.config.FeeCurrencies {
+			var missing []*Transaction
+			for _, tx := range group {
+				if _, ok := tx.Fee(currency); !ok {
+					missing = append(missing, tx)
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			quote, err := s.currencyPrice(ctx, currency, group[0].BlockNumber, timestamp)
+			if err != nil {
+				log.Printf("Failed to convert fees of block %d to %s: %v", group[0].BlockNumber, currency, err)
+				continue
+			}
+			for _, tx := range missing {
+				tx.ApplyCurrencyQuote(quote)
+				changed[tx] = true
+			}
+		}
+		for _, tx := range group {
+			if changed[tx] {
+				converted = append(converted, tx)
+			}
+		}
+	}
+	if len(converted) == 0 {
+		return 0, nil
+	}
+	if err := s.repo.UpdateTransactionPrices(converted); err != nil {
+		return 0, fmt.Errorf("failed to save converted fees: %w", err)
+	}
+	return len(converted), nil
+}
+
+// currencyPrice returns the price of ETH in currency at the given block from the configured price providers
+func (s *Service) currencyPrice(ctx context.Context, currency string, blockNumber uint64, timestamp time.Time) (*price.Quote, error) {
+	return s.priceProvider.GetPrice(ctx, price.Request{
+		Pair:        price.Pair{Base: "ETH", Quote: currency},
+		Timestamp:   timestamp,
+		BlockNumber: blockNumber,
+	})
+}
+
+// prefetchFeeCurrencies lets the price provider load the ETH prices of every configured currency in bulk
+func (s *Service) prefetchFeeCurrencies(ctx context.Context, timestamps []time.Time) {
+	for _, currency := range s.config.FeeCurrencies {
+		prefetchPrices(ctx, s.priceProvider, price.Pair{Base: "ETH", Quote: currency}, timestamps)
+	}
+}
+
+// hasFeeETH reports whether any of the transactions has its ETH fee computed
+func hasFeeETH(txs []*Transaction) bool {
+	for _, tx := range txs {
+		if tx.FeeETH != nil && tx.FeeETH.Float != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/price"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// currencyPriceClient prices ETH in each quote currency it knows and fails for the others
+type currencyPriceClient map[string]float64
+
+func (currencyPriceClient) Name() string {
+	return "fake"
+}
+
+func (c currencyPriceClient) GetPrice(_ context.Context, req price.Request) (*price.Quote, error) {
+	value, ok := c[req.Pair.Quote]
+	if !ok {
+		return nil, errors.New("unknown pair")
+	}
+	return &price.Quote{Pair: req.Pair, Price: big.NewFloat(value), Timestamp: req.Timestamp, Source: "fake"}, nil
+}
+
+func TestProcessBlockTransactionsConvertsFees(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}, FeeCurrencies: []string{"EUR", "BTC", "GBP"}}
+	provider := currencyPriceClient{"USDT": 2000, "EUR": 1800, "BTC": 0.05}
+	service := NewService(cfg, nil, provider, chain, repo)
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	tx := repo.transactions[chain.receipts[1][0].TxHash.Hex()]
+	require.NotNil(t, tx)
+	feeETH := new(big.Float).Set(tx.FeeETH.Float)
+
+	eur, ok := tx.Fee("EUR")
+	require.True(t, ok)
+	assert.Equal(t, new(big.Float).Mul(feeETH, big.NewFloat(1800)).Text('f', 6), eur.Fee.Text('f', 6))
+	assert.Equal(t, "1800.000000", eur.ETHPrice.Text('f', 6))
+	assert.Equal(t, tx.BlockNumber, eur.BlockNumber)
+
+	btc, ok := tx.Fee("BTC")
+	require.True(t, ok)
+	assert.Equal(t, new(big.Float).Mul(feeETH, big.NewFloat(0.05)).Text('f', 12), btc.Fee.Text('f', 12))
+
+	// A failed conversion does not affect the USDT fee
+	_, ok = tx.Fee("GBP")
+	assert.False(t, ok)
+	assert.Equal(t, StatusProcessed, tx.Status)
+}
+
+func TestBackfillFeeCurrencies(t *testing.T) {
+	repo := newMemoryRepository()
+	cfg := &config.Config{FeeCurrencies: []string{"EUR", "USD", "GBP"}}
+	service := NewService(cfg, nil, currencyPriceClient{"EUR": 1800, "USD": 2001}, newFakeChain(), repo)
+
+	tx := newUnpricedTransaction("0x01", 10, StatusPendingPrice)
+	tx.ApplyQuote(&price.Quote{Pair: price.ETHUSDT, Price: big.NewFloat(2000), Source: "fake"})
+	tx.ApplyCurrencyQuote(&price.Quote{Pair: price.Pair{Base: "ETH", Quote: "EUR"}, Price: big.NewFloat(1700), Source: "fake"})
+	repo.transactions[tx.TxHash] = tx
+	unpriced := newUnpricedTransaction("0x02", 10, StatusFailed)
+	unpriced.SetFeeETH()
+	repo.transactions[unpriced.TxHash] = unpriced
+
+	var cursor MissingFeesFilter
+	converted, err := service.backfillFeeCurrencies(context.Background(), &cursor)
+	require.NoError(t, err)
+	assert.Equal(t, 1, converted)
+	require.Len(t, tx.Fees, 2)
+	eur, _ := tx.Fee("EUR")
+	assert.Equal(t, "1700.000000", eur.ETHPrice.Text('f', 6), "existing conversions are kept")
+	usd, ok := tx.Fee("USD")
+	require.True(t, ok)
+	assert.Equal(t, "0.042021", usd.Fee.Text('f', 6))
+	assert.Equal(t, 1, repo.priceUpdates)
+
+	// Unpriced transactions are not converted
+	assert.Empty(t, unpriced.Fees)
+
+	// A currency without a price is retried on the next run, without writes while it fails
+	converted, err = service.backfillFeeCurrencies(context.Background(), &cursor)
+	require.NoError(t, err)
+	assert.Zero(t, converted)
+	assert.Equal(t, 1, repo.priceUpdates)
+	_, ok = tx.Fee("GBP")
+	assert.False(t, ok)
+}
+
+func TestBackfillFeeCurrenciesCursor(t *testing.T) {
+	repo := newMemoryRepository()
+	cfg := &config.Config{FeeCurrencies: []string{"GBP"}}
+	service := NewService(cfg, nil, currencyPriceClient{}, newFakeChain(), repo)
+	for n := uint64(1); n <= reconcileBatchSize+1; n++ {
+		tx := newUnpricedTransaction(fmt.Sprintf("0x%04x", n), n, StatusPendingPrice)
+		tx.ApplyQuote(&price.Quote{Pair: price.ETHUSDT, Price: big.NewFloat(2000), Source: "fake"})
+		repo.transactions[tx.TxHash] = tx
+	}
+
+	// Each run visits one batch, then the cursor starts over
+	var cursor MissingFeesFilter
+	_, err := service.backfillFeeCurrencies(context.Background(), &cursor)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(reconcileBatchSize), cursor.AfterBlock)
+	_, err = service.backfillFeeCurrencies(context.Background(), &cursor)
+	require.NoError(t, err)
+	assert.Empty(t, cursor.AfterTxHash)
+}
@@ -0,0 +1,107 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// weiPerETH converts wei amounts to ETH
+var weiPerETH = new(big.Float).SetInt(big.NewInt(1e18))
+
+// SetBaseFee records the base fee of the transaction's block. Blocks before the London fork have none,
+// which is recorded as 0 since their whole fee went to the miner.
+func (tx *Transaction) SetBaseFee(baseFee *big.Int) {
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+	tx.BaseFeePerGas = NewBigInt(new(big.Int).Set(baseFee))
+}
+
+// SetFeeCaps records the transaction type and, for EIP-1559 style transactions, their fee caps
+func (tx *Transaction) SetFeeCaps(ethTx *types.Transaction) {
+	tx.TxType = ethTx.Type()
+	if ethTx.Type() < types.DynamicFeeTxType {
+		return
+	}
+	tx.MaxFeePerGas = NewBigInt(new(big.Int).Set(ethTx.GasFeeCap()))
+	tx.MaxPriorityFeePerGas = NewBigInt(new(big.Int).Set(ethTx.GasTipCap()))
+}
+
+// updateFeeBreakdown splits the fee into the burned base fee and the priority tip paid to the block
+// builder, in ETH and in USDT at ethPrice. It is a no-op until the base fee is known.
+func (tx *Transaction) updateFeeBreakdown(ethPrice *big.Float) {
+	if tx.BaseFeePerGas == nil || tx.BaseFeePerGas.Int == nil {
+		return
+	}
+
+	// The effective gas price is never below the base fee, but guard against inconsistent data
+	burnedPerGas := tx.BaseFeePerGas.Int
+	if burnedPerGas.Cmp(tx.GasPrice.Int) > 0 {
+		burnedPerGas = tx.GasPrice.Int
+	}
+	tipPerGas := new(big.Int).Sub(tx.GasPrice.Int, burnedPerGas)
+
+	burnedETH := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(tx.GasUsed.Int, burnedPerGas)), weiPerETH)
+	tipETH := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(tx.GasUsed.Int, tipPerGas)), weiPerETH)
+	tx.BurnedFeeETH = NewBigFloat(burnedETH)
+	tx.TipFeeETH = NewBigFloat(tipETH)
+	tx.BurnedFeeUSDT = NewBigFloat(new(big.Float).Mul(burnedETH, ethPrice))
+	tx.TipFeeUSDT = NewBigFloat(new(big.Float).Mul(tipETH, ethPrice))
+}
+
+// attachBaseFees looks up the headers of the batch's blocks and records their base fee
+func (s *Service) attachBaseFees(ctx context.Context, txBatch [][]*Transaction) error {
+	var numbers []uint64
+	for _, blockTxs := range txBatch {
+		if len(blockTxs) > 0 {
+			numbers = append(numbers, blockTxs[0].BlockNumber)
+		}
+	}
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	headers, err := s.nodeClient.GetHeaders(ctx, numbers)
+	if err != nil {
+		return fmt.Errorf("failed to get headers: %w", err)
+	}
+	baseFees := make(map[uint64]*big.Int, len(headers))
+	for _, header := range headers {
+		baseFees[header.Number.Uint64()] = header.BaseFee
+	}
+	for _, blockTxs := range txBatch {
+		for _, tx := range blockTxs {
+			tx.SetBaseFee(baseFees[tx.BlockNumber])
+		}
+	}
+	return nil
+}
+
+// attachBodies fetches the bodies of the batch's transactions and records their type, fee caps,
+// sender, target contract and nonce
+func (s *Service) attachBodies(ctx context.Context, txBatch [][]*Transaction) error {
+	var txs []*Transaction
+	var txHashes []string
+	for _, blockTxs := range txBatch {
+		for _, tx := range blockTxs {
+			txs = append(txs, tx)
+			txHashes = append(txHashes, tx.TxHash)
+		}
+	}
+	if len(txHashes) == 0 {
+		return nil
+	}
+
+	bodies, err := s.nodeClient.GetTransactions(ctx, txHashes)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: %w", err)
+	}
+	for i, body := range bodies {
+		txs[i].SetFeeCaps(body)
+		txs[i].SetOrigin(body)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePricesFeeBreakdown(t *testing.T) {
+	newTx := func() *Transaction {
+		return &Transaction{
+			TxHash:   "0x01",
+			GasUsed:  NewBigInt(big.NewInt(100000)),
+			GasPrice: NewBigInt(big.NewInt(32e9)),
+		}
+	}
+
+	// 30 gwei burned and 2 gwei tipped per gas
+	tx := newTx()
+	tx.SetBaseFee(big.NewInt(30e9))
+	tx.UpdatePrices(big.NewFloat(2000))
+	assert.Equal(t, "0.003000000000000000", tx.BurnedFeeETH.Text('f', 18))
+	assert.Equal(t, "6.000000", tx.BurnedFeeUSDT.Text('f', 6))
+	assert.Equal(t, "0.000200000000000000", tx.TipFeeETH.Text('f', 18))
+	assert.Equal(t, "0.400000", tx.TipFeeUSDT.Text('f', 6))
+	assert.Equal(t, "6.400000", tx.FeeUSDT.Text('f', 6))
+
+	// Before London nothing is burned
+	tx = newTx()
+	tx.SetBaseFee(nil)
+	tx.UpdatePrices(big.NewFloat(2000))
+	assert.Equal(t, "0", tx.BaseFeePerGas.String())
+	assert.Equal(t, "0.000000", tx.BurnedFeeUSDT.Text('f', 6))
+	assert.Equal(t, "6.400000", tx.TipFeeUSDT.Text('f', 6))
+
+	// Without the base fee there is no breakdown
+	tx = newTx()
+	tx.UpdatePrices(big.NewFloat(2000))
+	assert.Nil(t, tx.BurnedFeeETH)
+	assert.Nil(t, tx.TipFeeETH)
+}
+
+func TestProcessBlockTransactionsRecordsFeeBreakdown(t *testing.T) {
+	chain := newFakeChain()
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1700000012,
+		Difficulty: big.NewInt(0),
+		BaseFee:    big.NewInt(30e9),
+	}
+	ethTx := types.NewTx(&types.DynamicFeeTx{Nonce: 1, GasFeeCap: big.NewInt(40e9), GasTipCap: big.NewInt(2e9)})
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{ethTx}})
+	swapLog := newSwapLog(testPool.Address, ethTx.Hash(), big.NewInt(-1000_000000), big.NewInt(5e17), 1)
+	chain.blocks[1] = block
+	chain.receipts[1] = []*types.Receipt{{
+		Type:              types.DynamicFeeTxType,
+		TxHash:            ethTx.Hash(),
+		BlockHash:         block.Hash(),
+		BlockNumber:       header.Number,
+		GasUsed:           100000,
+		EffectiveGasPrice: big.NewInt(32e9),
+		Logs:              []*types.Log{swapLog},
+	}}
+
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	tx := repo.transactions[ethTx.Hash().Hex()]
+	require.NotNil(t, tx)
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.TxType)
+	assert.Equal(t, "30000000000", tx.BaseFeePerGas.String())
+	assert.Equal(t, "40000000000", tx.MaxFeePerGas.String())
+	assert.Equal(t, "2000000000", tx.MaxPriorityFeePerGas.String())
+	assert.Equal(t, "6.000000", tx.BurnedFeeUSDT.Text('f', 6))
+	assert.Equal(t, "0.400000", tx.TipFeeUSDT.Text('f', 6))
+}
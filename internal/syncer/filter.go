@@ -0,0 +1,199 @@
+package syncer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFilter is returned when a transaction search is called with invalid parameters
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// addressPattern matches a 20-byte hex Ethereum address
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+const (
+	// DefaultPageSize is the number of transactions returned when no limit is given
+	DefaultPageSize = 50
+	// MaxPageSize is the maximum number of transactions returned in a single page
+	MaxPageSize = 1000
+)
+
+// SortField represents a column transactions can be sorted by
+type SortField string
+
+const (
+	SortByBlockNumber SortField = "block_number"
+	SortByTimestamp   SortField = "timestamp"
+	SortByFeeUSDT     SortField = "fee_usdt"
+)
+
+// SortOrder represents the direction of a sort
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// TransactionFilter describes the criteria used to search stored transactions
+type TransactionFilter struct {
+	FromBlock   *uint64
+	ToBlock     *uint64
+	FromTime    *time.Time
+	ToTime      *time.Time
+	MinFeeUSDT  *big.Float
+	MaxFeeUSDT  *big.Float
+	Status      TransactionStatus
+	Finality    FinalityStatus
+	PoolAddress string
+	FromAddress string // Transaction sender
+	ToAddress   string // Contract called by the transaction
+	Reverted    *bool
+	SortBy      SortField
+	Order       SortOrder
+	Limit       int
+	Cursor      string
+}
+
+// TransactionPage is a single page of transaction search results
+type TransactionPage struct {
+	Transactions []*Transaction
+	NextCursor   string
+}
+
+// transactionCursor identifies the last row of a page for keyset pagination
+type transactionCursor struct {
+	Value  string `json:"v"`
+	TxHash string `json:"h"`
+}
+
+// Normalize applies defaults to the filter and validates its values
+func (f *TransactionFilter) Normalize() error {
+	if f.SortBy == "" {
+		f.SortBy = SortByBlockNumber
+	}
+	if f.Order == "" {
+		f.Order = SortDesc
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultPageSize
+	}
+
+	switch f.SortBy {
+	case SortByBlockNumber, SortByTimestamp, SortByFeeUSDT:
+	default:
+		return fmt.Errorf("%w: unsupported sort field %q", ErrInvalidFilter, f.SortBy)
+	}
+	switch f.Order {
+	case SortAsc, SortDesc:
+	default:
+		return fmt.Errorf("%w: unsupported sort order %q", ErrInvalidFilter, f.Order)
+	}
+	switch f.Status {
+	case "", StatusProcessed, StatusPendingPrice, StatusFailed:
+	default:
+		return fmt.Errorf("%w: unsupported status %q", ErrInvalidFilter, f.Status)
+	}
+	switch f.Finality {
+	case "", FinalityUnconfirmed, FinalityFinalized:
+	default:
+		return fmt.Errorf("%w: unsupported finality %q", ErrInvalidFilter, f.Finality)
+	}
+	if f.PoolAddress != "" && !addressPattern.MatchString(f.PoolAddress) {
+		return fmt.Errorf("%w: invalid pool address %q", ErrInvalidFilter, f.PoolAddress)
+	}
+	f.PoolAddress = strings.ToLower(f.PoolAddress)
+	if f.FromAddress != "" && !addressPattern.MatchString(f.FromAddress) {
+		return fmt.Errorf("%w: invalid from address %q", ErrInvalidFilter, f.FromAddress)
+	}
+	f.FromAddress = strings.ToLower(f.FromAddress)
+	if f.ToAddress != "" && !addressPattern.MatchString(f.ToAddress) {
+		return fmt.Errorf("%w: invalid to address %q", ErrInvalidFilter, f.ToAddress)
+	}
+	f.ToAddress = strings.ToLower(f.ToAddress)
+	if f.Limit < 0 || f.Limit > MaxPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidFilter, MaxPageSize)
+	}
+	if f.FromBlock != nil && f.ToBlock != nil && *f.FromBlock > *f.ToBlock {
+		return fmt.Errorf("%w: from_block is greater than to_block", ErrInvalidFilter)
+	}
+	if f.FromTime != nil && f.ToTime != nil && f.FromTime.After(*f.ToTime) {
+		return fmt.Errorf("%w: from_time is after to_time", ErrInvalidFilter)
+	}
+	if f.MinFeeUSDT != nil && f.MaxFeeUSDT != nil && f.MinFeeUSDT.Cmp(f.MaxFeeUSDT) > 0 {
+		return fmt.Errorf("%w: min_fee_usdt is greater than max_fee_usdt", ErrInvalidFilter)
+	}
+	if f.Cursor != "" {
+		if _, _, err := f.decodeCursor(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortColumn returns the SQL expression used to order results
+func (f *TransactionFilter) sortColumn() string {
+	if f.SortBy == SortByFeeUSDT {
+		// Unpriced transactions have no fee; treat them as zero so they still paginate
+		return "COALESCE(fee_usdt, 0)"
+	}
+	return string(f.SortBy)
+}
+
+// encodeCursor builds the cursor pointing after the given transaction
+func (f *TransactionFilter) encodeCursor(tx *Transaction) string {
+	var value string
+	switch f.SortBy {
+	case SortByTimestamp:
+		value = tx.Timestamp.UTC().Format(time.RFC3339Nano)
+	case SortByFeeUSDT:
+		value = "0"
+		if tx.FeeUSDT != nil && tx.FeeUSDT.Float != nil {
+			value = tx.FeeUSDT.Text('f', 6)
+		}
+	default:
+		value = strconv.FormatUint(tx.BlockNumber, 10)
+	}
+
+	data, _ := json.Marshal(transactionCursor{Value: value, TxHash: tx.TxHash})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the typed sort value and transaction hash stored in the cursor
+func (f *TransactionFilter) decodeCursor() (interface{}, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(f.Cursor)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+	var cursor transactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.TxHash == "" {
+		return nil, "", fmt.Errorf("%w: malformed cursor", ErrInvalidFilter)
+	}
+
+	switch f.SortBy {
+	case SortByTimestamp:
+		ts, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: cursor does not match sort field", ErrInvalidFilter)
+		}
+		return ts, cursor.TxHash, nil
+	case SortByFeeUSDT:
+		if _, ok := new(big.Float).SetString(cursor.Value); !ok {
+			return nil, "", fmt.Errorf("%w: cursor does not match sort field", ErrInvalidFilter)
+		}
+		return cursor.Value, cursor.TxHash, nil
+	default:
+		block, err := strconv.ParseUint(cursor.Value, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: cursor does not match sort field", ErrInvalidFilter)
+		}
+		return block, cursor.TxHash, nil
+	}
+}
@@ -0,0 +1,87 @@
+package syncer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionFilter_Normalize(t *testing.T) {
+	filter := TransactionFilter{}
+	assert.NoError(t, filter.Normalize())
+	assert.Equal(t, SortByBlockNumber, filter.SortBy)
+	assert.Equal(t, SortDesc, filter.Order)
+	assert.Equal(t, DefaultPageSize, filter.Limit)
+
+	filter = TransactionFilter{PoolAddress: "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"}
+	assert.NoError(t, filter.Normalize())
+	assert.Equal(t, "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", filter.PoolAddress)
+
+	filter = TransactionFilter{ToAddress: "0xE592427A0AEce92De3Edee1F18E0157C05861564"}
+	assert.NoError(t, filter.Normalize())
+	assert.Equal(t, "0xe592427a0aece92de3edee1f18e0157c05861564", filter.ToAddress)
+
+	from, to := uint64(200), uint64(100)
+	tests := []struct {
+		name   string
+		filter TransactionFilter
+	}{
+		{"unknown sort field", TransactionFilter{SortBy: "gas_used"}},
+		{"unknown order", TransactionFilter{Order: "sideways"}},
+		{"unknown status", TransactionFilter{Status: "DONE"}},
+		{"unknown finality", TransactionFilter{Finality: "SAFE"}},
+		{"limit too large", TransactionFilter{Limit: MaxPageSize + 1}},
+		{"inverted block range", TransactionFilter{FromBlock: &from, ToBlock: &to}},
+		{"inverted fee range", TransactionFilter{MinFeeUSDT: big.NewFloat(10), MaxFeeUSDT: big.NewFloat(1)}},
+		{"malformed cursor", TransactionFilter{Cursor: "not-a-cursor"}},
+		{"invalid pool address", TransactionFilter{PoolAddress: "0x1234"}},
+		{"invalid from address", TransactionFilter{FromAddress: "vitalik.eth"}},
+		{"invalid to address", TransactionFilter{ToAddress: "0x1234"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Normalize()
+			assert.True(t, errors.Is(err, ErrInvalidFilter), "expected ErrInvalidFilter, got %v", err)
+		})
+	}
+}
+
+func TestTransactionFilter_CursorRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	tx := &Transaction{
+		TxHash:      "0xabc",
+		BlockNumber: 12345678,
+		Timestamp:   ts,
+		FeeUSDT:     NewBigFloat(big.NewFloat(10.5)),
+	}
+
+	tests := []struct {
+		sortBy SortField
+		want   interface{}
+	}{
+		{SortByBlockNumber, uint64(12345678)},
+		{SortByTimestamp, ts},
+		{SortByFeeUSDT, "10.500000"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sortBy), func(t *testing.T) {
+			filter := TransactionFilter{SortBy: tt.sortBy}
+			filter.Cursor = filter.encodeCursor(tx)
+			assert.NoError(t, filter.Normalize())
+
+			value, txHash, err := filter.decodeCursor()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+			assert.Equal(t, "0xabc", txHash)
+		})
+	}
+
+	// A block number cursor cannot be reused when sorting by timestamp
+	filter := TransactionFilter{SortBy: SortByBlockNumber}
+	cursor := filter.encodeCursor(tx)
+	mismatched := TransactionFilter{SortBy: SortByTimestamp, Cursor: cursor}
+	assert.True(t, errors.Is(mismatched.Normalize(), ErrInvalidFilter))
+}
@@ -0,0 +1,65 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// finalizedBlock returns the highest block whose transactions can no longer be reorganized away.
+// It follows the configured node block tag, or trails the latest block by the confirmation depth.
+func (s *Service) finalizedBlock(ctx context.Context) (uint64, error) {
+	cfg := s.config.FinalityConfig
+	if cfg.BlockTag != "" {
+		return s.nodeClient.GetBlockNumberByTag(ctx, cfg.BlockTag)
+	}
+
+	latestBlock, err := s.nodeClient.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if latestBlock < cfg.ConfirmationDepth {
+		return 0, nil
+	}
+	return latestBlock - cfg.ConfirmationDepth, nil
+}
+
+// promoteFinalizedTransactions marks every unconfirmed transaction up to the finalized block as finalized
+func (s *Service) promoteFinalizedTransactions(ctx context.Context) error {
+	finalized, err := s.finalizedBlock(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get finalized block: %w", err)
+	}
+
+	promoted, err := s.repo.FinalizeTransactions(finalized)
+	if err != nil {
+		return fmt.Errorf("failed to finalize transactions up to block %d: %w", finalized, err)
+	}
+	if promoted > 0 {
+		log.Printf("Finalized %d transactions up to block %d", promoted, finalized)
+	}
+	return nil
+}
+
+// runFinalityPromoter periodically promotes unconfirmed transactions until the context is cancelled
+func (s *Service) runFinalityPromoter(ctx context.Context) {
+	interval := s.config.FinalityConfig.PromoteInterval
+	if interval <= 0 {
+		interval = 12 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.promoteFinalizedTransactions(ctx); err != nil {
+			log.Printf("Error promoting finalized transactions: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
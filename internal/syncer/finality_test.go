@@ -0,0 +1,121 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBlocks runs live sync over blocks from..to of the chain
+func syncBlocks(t *testing.T, service *Service, from, to uint64) {
+	for i := from; i <= to; i++ {
+		blockNum := i
+		require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+	}
+}
+
+// finalityByBlock returns the finality of the stored transaction in each block
+func finalityByBlock(repo *memoryRepository) map[uint64]FinalityStatus {
+	result := make(map[uint64]FinalityStatus)
+	for _, tx := range repo.transactions {
+		result[tx.BlockNumber] = tx.Finality
+	}
+	return result
+}
+
+func TestPromoteFinalizedTransactionsByDepth(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 10, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{
+		Pools:          []config.PoolConfig{config.DefaultPools[0]},
+		FinalityConfig: config.FinalityConfig{ConfirmationDepth: 3},
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	syncBlocks(t, service, 1, 10)
+
+	for _, finality := range finalityByBlock(repo) {
+		assert.Equal(t, FinalityUnconfirmed, finality)
+	}
+
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+	finality := finalityByBlock(repo)
+	for n := uint64(1); n <= 7; n++ {
+		assert.Equal(t, FinalityFinalized, finality[n], "block %d", n)
+	}
+	for n := uint64(8); n <= 10; n++ {
+		assert.Equal(t, FinalityUnconfirmed, finality[n], "block %d", n)
+	}
+
+	// Rows are promoted once the chain advances past the confirmation depth
+	chain.extend(11, 12, "a")
+	syncBlocks(t, service, 11, 12)
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+	finality = finalityByBlock(repo)
+	assert.Equal(t, FinalityFinalized, finality[9])
+	assert.Equal(t, FinalityUnconfirmed, finality[10])
+}
+
+func TestPromoteFinalizedTransactionsByBlockTag(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 10, "a")
+	chain.finalized = 4
+	repo := newMemoryRepository()
+	cfg := &config.Config{
+		Pools:          []config.PoolConfig{config.DefaultPools[0]},
+		FinalityConfig: config.FinalityConfig{ConfirmationDepth: 1, BlockTag: config.BlockTagFinalized},
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	syncBlocks(t, service, 1, 10)
+
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+	finality := finalityByBlock(repo)
+	assert.Equal(t, FinalityFinalized, finality[4])
+	assert.Equal(t, FinalityUnconfirmed, finality[5])
+}
+
+func TestPromoteFinalizedTransactionsSkipsUnpriced(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 5, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	syncBlocks(t, service, 1, 5)
+
+	// The fee of an unpriced transaction still changes once the reconciler prices it
+	unpriced := repo.transactions[chain.receipts[2][0].TxHash.Hex()]
+	unpriced.MarkPriceFailed(errors.New("price unavailable"))
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+	assert.Equal(t, FinalityUnconfirmed, unpriced.Finality)
+	assert.Equal(t, FinalityFinalized, finalityByBlock(repo)[1])
+
+	unpriced.UpdatePrices(big.NewFloat(2000))
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+	assert.Equal(t, FinalityFinalized, unpriced.Finality)
+}
+
+func TestReorgBelowFinalizedTransactionsFails(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 5, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{
+		Pools:          []config.PoolConfig{config.DefaultPools[0]},
+		FinalityConfig: config.FinalityConfig{ConfirmationDepth: 2},
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	syncBlocks(t, service, 1, 5)
+	require.NoError(t, service.promoteFinalizedTransactions(context.Background()))
+
+	// Blocks 3-5 are replaced although block 3 was reported as finalized
+	chain.extend(3, 6, "b")
+	blockNum := uint64(6)
+	err := service.processBlockTransactions(context.Background(), &blockNum)
+	assert.ErrorIs(t, err, ErrFinalizedReorg)
+	assert.Len(t, repo.transactions, 5, "nothing is rolled back")
+	assert.Equal(t, FinalityFinalized, finalityByBlock(repo)[3])
+}
@@ -0,0 +1,197 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// targetLogsPerWindow is the number of logs per eth_getLogs call the adaptive window aims for
+const targetLogsPerWindow = 1000
+
+// runLogScanSync backfills a pool by scanning its logs with eth_getLogs over adaptive block windows
+// and building transactions from their receipts
+func (s *Service) runLogScanSync(ctx context.Context, progress *SyncProgress, pool Pool) {
+	window := s.config.HistoricalConfig.LogWindow
+	if window == 0 {
+		window = 2000
+	}
+
+	currentBlock := progress.LastProcessedBlock + 1
+	for currentBlock <= progress.EndBlock {
+		select {
+		case <-ctx.Done():
+			progress.Status = SyncStatusPaused
+			progress.ErrorMessage = "context cancelled"
+			s.repo.UpdateSyncProgress(progress)
+			return
+		default:
+		}
+
+		logs, toBlock, err := s.scanPoolLogs(ctx, pool, currentBlock, progress.EndBlock, &window)
+		if err != nil {
+			switch {
+			case ctx.Err() != nil:
+				progress.Status = SyncStatusPaused
+				progress.ErrorMessage = "context cancelled"
+			case isLogRangeError(err):
+				progress.Status = SyncStatusFailed
+				progress.ErrorMessage = fmt.Sprintf("failed to scan logs: %v", err)
+			default:
+				// The node is unavailable: the chunk resumes from its last processed block on the next run
+				log.Printf("Pausing log scan of pool %s at block %d: %v", pool.Name, currentBlock, err)
+				progress.Status = SyncStatusPaused
+				progress.ErrorMessage = fmt.Sprintf("failed to scan logs: %v", err)
+			}
+			s.repo.UpdateSyncProgress(progress)
+			return
+		}
+
+		txBatch, err := s.transactionsFromLogs(ctx, pool, logs)
+		if err != nil {
+			progress.Status = SyncStatusFailed
+			progress.ErrorMessage = fmt.Sprintf("failed to build transactions: %v", err)
+			s.repo.UpdateSyncProgress(progress)
+			return
+		}
+
+		log.Printf("Fetching historic price for batch of %d blocks of pool %s from block %d to %d",
+			len(txBatch), pool.Name, currentBlock, toBlock)
+		txsWithPrice := s.processBatch(ctx, txBatch, s.config.PriceFetchBatchSize)
+
+		if len(txsWithPrice) > 0 {
+			if err := s.repo.SaveTransactions(txsWithPrice); err != nil {
+				progress.Status = SyncStatusFailed
+				progress.ErrorMessage = fmt.Sprintf("failed to save transactions: %v", err)
+				s.repo.UpdateSyncProgress(progress)
+				return
+			}
+		}
+
+		previousBlock := progress.LastProcessedBlock
+		progress.LastProcessedBlock = toBlock
+		progress.TransactionsProcessed += uint64(len(txsWithPrice))
+		s.recordHistoricalProgress(progress, previousBlock)
+		if err := s.repo.UpdateSyncProgress(progress); err != nil {
+			log.Printf("Failed to update sync progress: %v", err)
+		}
+
+		currentBlock = toBlock + 1
+	}
+
+	// Mark sync as completed
+	progress.Status = SyncStatusCompleted
+	s.repo.UpdateSyncProgress(progress)
+}
+
+// scanPoolLogs returns the pool's logs from fromBlock up to the block it returns, at most endBlock.
+// The window is halved while the node rejects the range (e.g. too many results) and doubled while
+// few logs are returned. Other errors are returned as is.
+func (s *Service) scanPoolLogs(ctx context.Context, pool Pool, fromBlock, endBlock uint64, window *uint64) ([]types.Log, uint64, error) {
+	maxWindow := max(s.config.HistoricalConfig.MaxWindow, *window)
+	for {
+		toBlock := min(fromBlock+*window-1, endBlock)
+		logs, err := s.nodeClient.GetLogs(ctx, pool.Address, "", fromBlock, toBlock)
+		if err != nil {
+			if ctx.Err() != nil || !isLogRangeError(err) || *window == 1 {
+				return nil, 0, err
+			}
+			*window /= 2
+			log.Printf("Shrinking log scan window of pool %s to %d blocks: %v", pool.Name, *window, err)
+			continue
+		}
+
+		if len(logs) < targetLogsPerWindow/2 {
+			*window = min(*window*2, maxWindow)
+		}
+		return logs, toBlock, nil
+	}
+}
+
+// logRangeErrors are fragments of the errors nodes and providers return when an eth_getLogs range is too
+// wide or matches too many logs
+var logRangeErrors = []string{
+	"more than 10000 results",
+	"block range",
+	"range limit",
+	"response size",
+}
+
+// isLogRangeError reports whether an eth_getLogs error asks for a narrower block range
+func isLogRangeError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, fragment := range logRangeErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// transactionsFromLogs fetches the receipts, bodies and block headers of the transactions that emitted
+// the logs and builds unpriced transactions grouped by block
+func (s *Service) transactionsFromLogs(ctx context.Context, pool Pool, logs []types.Log) ([][]*Transaction, error) {
+	var txHashes []string
+	var blockNumbers []uint64
+	seenTx := make(map[string]bool)
+	seenBlock := make(map[uint64]bool)
+	for _, l := range logs {
+		if l.Removed {
+			continue
+		}
+		txHash := l.TxHash.Hex()
+		if !seenTx[txHash] {
+			seenTx[txHash] = true
+			txHashes = append(txHashes, txHash)
+		}
+		if !seenBlock[l.BlockNumber] {
+			seenBlock[l.BlockNumber] = true
+			blockNumbers = append(blockNumbers, l.BlockNumber)
+		}
+	}
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+
+	receipts, err := s.nodeClient.GetTransactionReceipts(ctx, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+	headers, err := s.nodeClient.GetHeaders(ctx, blockNumbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get headers: %w", err)
+	}
+	headersByNumber := make(map[uint64]*types.Header, len(headers))
+	for _, header := range headers {
+		headersByNumber[header.Number.Uint64()] = header
+	}
+
+	txsByBlock := make(map[uint64][]*Transaction)
+	for _, receipt := range receipts {
+		blockNum := receipt.BlockNumber.Uint64()
+		header, ok := headersByNumber[blockNum]
+		if !ok {
+			return nil, fmt.Errorf("no header for block %d of transaction %s", blockNum, receipt.TxHash.Hex())
+		}
+		tx := s.newTransactionFromReceipt(receipt, pool, blockNum, time.Unix(int64(header.Time), 0))
+		tx.SetBaseFee(header.BaseFee)
+		txsByBlock[blockNum] = append(txsByBlock[blockNum], tx)
+	}
+
+	sort.Slice(blockNumbers, func(i, j int) bool { return blockNumbers[i] < blockNumbers[j] })
+	txBatch := make([][]*Transaction, 0, len(txsByBlock))
+	for _, blockNum := range blockNumbers {
+		if txs, ok := txsByBlock[blockNum]; ok {
+			txBatch = append(txBatch, txs)
+		}
+	}
+	if err := s.attachBodies(ctx, txBatch); err != nil {
+		return nil, err
+	}
+	return txBatch, nil
+}
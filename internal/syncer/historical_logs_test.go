@@ -0,0 +1,141 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunLogScanSync(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 20, "a")
+	chain.maxRange = 3
+	repo := newMemoryRepository()
+	cfg := &config.Config{
+		Pools:               []config.PoolConfig{config.DefaultPools[0]},
+		HistoricalConfig:    config.HistoricalConfig{Backend: config.HistoricalBackendLogs, LogWindow: 8, MaxWindow: 16},
+		PriceFetchBatchSize: 10,
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	progress := &SyncProgress{
+		PoolAddress:        testPool.Address,
+		StartBlock:         4,
+		EndBlock:           18,
+		LastProcessedBlock: 4,
+		Status:             SyncStatusRunning,
+	}
+	service.runHistoricalSync(context.Background(), progress)
+
+	assert.Equal(t, SyncStatusCompleted, progress.Status)
+	assert.Equal(t, uint64(18), progress.LastProcessedBlock)
+	assert.Equal(t, uint64(14), progress.TransactionsProcessed)
+
+	require.Len(t, repo.transactions, 14)
+	for n := uint64(5); n <= 18; n++ {
+		tx, ok := repo.transactions[chain.receipts[n][0].TxHash.Hex()]
+		require.True(t, ok, "block %d", n)
+		assert.Equal(t, n, tx.BlockNumber)
+		assert.Equal(t, int64(1700000000+n*12), tx.Timestamp.Unix())
+		assert.Equal(t, testPool.Address, tx.PoolAddress)
+		assert.Equal(t, StatusProcessed, tx.Status)
+		assert.Equal(t, "fake", tx.PriceSource)
+		assert.Equal(t, "0.042000", tx.FeeUSDT.Text('f', 6))
+		assert.Len(t, tx.Swaps, 1)
+		// The fake blocks predate London, so the whole fee is a tip
+		assert.Equal(t, "0", tx.BaseFeePerGas.String())
+		assert.Equal(t, "0.042000", tx.TipFeeUSDT.Text('f', 6))
+	}
+}
+
+func TestScanPoolLogsAdaptsWindow(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 100, "a")
+	chain.maxRange = 10
+	cfg := &config.Config{
+		Pools:            []config.PoolConfig{config.DefaultPools[0]},
+		HistoricalConfig: config.HistoricalConfig{LogWindow: 40, MaxWindow: 64},
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, newMemoryRepository())
+
+	// Rejected ranges shrink the window until the node accepts it
+	window := uint64(40)
+	logs, toBlock, err := service.scanPoolLogs(context.Background(), testPool, 1, 100, &window)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), toBlock)
+	assert.Len(t, logs, 10)
+	assert.Equal(t, uint64(20), window)
+
+	// Accepted ranges with few logs grow the window, up to the maximum
+	chain.maxRange = 0
+	window = 40
+	_, toBlock, err = service.scanPoolLogs(context.Background(), testPool, 1, 100, &window)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(40), toBlock)
+	assert.Equal(t, uint64(64), window)
+
+	// The range never extends past the end block
+	_, toBlock, err = service.scanPoolLogs(context.Background(), testPool, 90, 100, &window)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), toBlock)
+}
+
+func TestScanPoolLogsKeepsWindowOnOtherErrors(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 100, "a")
+	chain.logsErr = errors.New("connection refused")
+	cfg := &config.Config{
+		Pools:            []config.PoolConfig{config.DefaultPools[0]},
+		HistoricalConfig: config.HistoricalConfig{LogWindow: 40, MaxWindow: 64},
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, newMemoryRepository())
+
+	window := uint64(40)
+	_, _, err := service.scanPoolLogs(context.Background(), testPool, 1, 100, &window)
+	assert.ErrorIs(t, err, chain.logsErr)
+	assert.Equal(t, uint64(40), window)
+
+	// A cancelled scan returns at once, even when the node rejects the range
+	chain.logsErr = nil
+	chain.maxRange = 10
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = service.scanPoolLogs(ctx, testPool, 1, 100, &window)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(40), window)
+}
+
+func TestRunLogScanSyncPausesOnNodeErrors(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 20, "a")
+	chain.logsErr = errors.New("connection refused")
+	cfg := &config.Config{
+		Pools:               []config.PoolConfig{config.DefaultPools[0]},
+		HistoricalConfig:    config.HistoricalConfig{Backend: config.HistoricalBackendLogs, LogWindow: 8, MaxWindow: 16},
+		PriceFetchBatchSize: 10,
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, newMemoryRepository())
+
+	progress := &SyncProgress{
+		PoolAddress:        testPool.Address,
+		StartBlock:         4,
+		EndBlock:           18,
+		LastProcessedBlock: 4,
+		Status:             SyncStatusRunning,
+	}
+	service.runHistoricalSync(context.Background(), progress)
+	assert.Equal(t, SyncStatusPaused, progress.Status)
+	assert.Equal(t, uint64(4), progress.LastProcessedBlock)
+	assert.Contains(t, progress.ErrorMessage, "connection refused")
+
+	// A range the node rejects even one block at a time fails the chunk
+	chain.logsErr = errors.New("query returned more than 10000 results")
+	progress.Status = SyncStatusRunning
+	service.runHistoricalSync(context.Background(), progress)
+	assert.Equal(t, SyncStatusFailed, progress.Status)
+	assert.Equal(t, uint64(4), progress.LastProcessedBlock)
+}
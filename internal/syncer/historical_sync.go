@@ -5,43 +5,68 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
+	"uniswap-fee-tracker/internal/config"
 	"uniswap-fee-tracker/internal/etherscan"
+
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// StartHistoricalSync starts a historical sync from startBlock to latestBlock
-func (s *Service) StartHistoricalSync(ctx context.Context, startBlock, latestBlock uint64) error {
+// StartHistoricalSync backfills every tracked pool up to latestBlock. Pools that have been synced before
+// continue from lastTrackedBlock, while newly tracked pools start from their configured start block.
+func (s *Service) StartHistoricalSync(ctx context.Context, lastTrackedBlock, latestBlock uint64) error {
 	if os.Getenv("DISABLE_HISTORICAL_SYNC") == "true" {
 		return nil
 	}
-	// Create sync progress record
-	progress := &SyncProgress{
-		StartBlock:            startBlock,
-		EndBlock:              latestBlock,
-		LastProcessedBlock:    startBlock,
-		TransactionsProcessed: 0,
-		Status:                SyncStatusRunning,
-		ErrorMessage:          "",
-		CompletedAt:           nil,
-	}
 
-	if err := s.repo.CreateSyncProgress(progress); err != nil {
-		return fmt.Errorf("failed to create sync progress: %w", err)
+	for _, pool := range s.pools {
+		synced, err := s.repo.HasSyncProgress(pool.Address)
+		if err != nil {
+			return fmt.Errorf("failed to check sync progress for pool %s: %w", pool.Name, err)
+		}
+
+		startBlock := lastTrackedBlock
+		if !synced && pool.StartBlock > 0 {
+			startBlock = pool.StartBlock - 1
+		}
+		if startBlock >= latestBlock {
+			continue
+		}
+
+		// Create a sync progress record for each chunk so chunks are synced and resumed independently
+		chunks := splitBlockRange(startBlock, latestBlock, s.config.HistoricalConfig.Chunks)
+		log.Printf("Starting historical sync of pool %s from block %d to %d in %d chunks",
+			pool.Name, startBlock, latestBlock, len(chunks))
+		for _, chunk := range chunks {
+			progress := &SyncProgress{
+				PoolAddress:           pool.Address,
+				StartBlock:            chunk.From,
+				EndBlock:              chunk.To,
+				LastProcessedBlock:    chunk.From,
+				TransactionsProcessed: 0,
+				Status:                SyncStatusRunning,
+				ErrorMessage:          "",
+				CompletedAt:           nil,
+			}
+			if err := s.repo.CreateSyncProgress(progress); err != nil {
+				return fmt.Errorf("failed to create sync progress: %w", err)
+			}
+		}
 	}
+
 	err := s.repo.UpdateLastTrackedBlock(latestBlock)
 	if err != nil {
 		log.Printf("failed to update last tracked block: %v", err)
 		return err
 	}
-	// Start sync in a goroutine
+	// Sync the incomplete chunks, including those of previous runs, on the worker pool
 	syncProgress, err := s.repo.GetIncompleteSyncProgress()
 	if err != nil {
 		log.Printf("failed to get incomplete sync progress: %v", err)
 		return err
 	}
-	for _, sync := range syncProgress {
-		go s.runHistoricalSync(ctx, &sync)
-	}
+	s.enqueueHistoricalSync(ctx, syncProgress)
 
 	return nil
 }
@@ -56,6 +81,21 @@ func (s *Service) runHistoricalSync(ctx context.Context, progress *SyncProgress)
 		}
 	}()
 
+	pool, ok := s.Pool(progress.PoolAddress)
+	if !ok {
+		log.Printf("Skipping historical sync %d: pool %s is no longer tracked", progress.ID, progress.PoolAddress)
+		return
+	}
+
+	// Measure the chunk's throughput from where it resumes
+	s.throughput.record(progress.ID, progress.LastProcessedBlock, time.Now())
+	defer s.throughput.forget(progress.ID)
+
+	if s.config.HistoricalConfig.Backend == config.HistoricalBackendLogs {
+		s.runLogScanSync(ctx, progress, pool)
+		return
+	}
+
 	currentBlock := progress.LastProcessedBlock + 1
 	for currentBlock <= progress.EndBlock {
 		select {
@@ -68,7 +108,7 @@ func (s *Service) runHistoricalSync(ctx context.Context, progress *SyncProgress)
 		}
 
 		// Get transactions for current batch
-		transfers, err := s.etherScanClient.GetTokenTransfers(ctx, s.config.UniswapV3Pool, currentBlock, progress.EndBlock)
+		transfers, err := s.etherScanClient.GetTokenTransfers(ctx, pool.Address, currentBlock, progress.EndBlock)
 		if err != nil {
 			log.Printf("Failed to get token transfers for block %d: %v", currentBlock, err)
 			time.Sleep(10 * time.Second)
@@ -80,11 +120,34 @@ func (s *Service) runHistoricalSync(ctx context.Context, progress *SyncProgress)
 		}
 		lastBlockInBatch := transfers[len(transfers)-1].GetBlockNumber()
 		isFinalIteration := lastBlockInBatch == progress.EndBlock || lastBlockInBatch == currentBlock
-		txBatch := s.filterAndGroupTransactions(transfers, isFinalIteration, lastBlockInBatch)
+		txBatch := s.filterAndGroupTransactions(transfers, pool, isFinalIteration, lastBlockInBatch)
+
+		// Decode the pool's swap events for the batch
+		if err := s.attachSwaps(ctx, txBatch, pool, currentBlock, lastBlockInBatch); err != nil {
+			progress.Status = SyncStatusFailed
+			progress.ErrorMessage = fmt.Sprintf("failed to attach swaps: %v", err)
+			s.repo.UpdateSyncProgress(progress)
+			return
+		}
+
+		// Etherscan transfers carry neither the base fee nor the fee caps needed for the fee breakdown, and
+		// their from and to are the token sender and receiver rather than those of the transaction
+		if err := s.attachBaseFees(ctx, txBatch); err != nil {
+			progress.Status = SyncStatusFailed
+			progress.ErrorMessage = fmt.Sprintf("failed to attach base fees: %v", err)
+			s.repo.UpdateSyncProgress(progress)
+			return
+		}
+		if err := s.attachBodies(ctx, txBatch); err != nil {
+			progress.Status = SyncStatusFailed
+			progress.ErrorMessage = fmt.Sprintf("failed to attach transaction bodies: %v", err)
+			s.repo.UpdateSyncProgress(progress)
+			return
+		}
 
 		// Log batch processing
-		log.Printf("Fetching historic price for batch of %d transactions from block %d to %d",
-			len(txBatch), currentBlock, lastBlockInBatch)
+		log.Printf("Fetching historic price for batch of %d transactions of pool %s from block %d to %d",
+			len(txBatch), pool.Name, currentBlock, lastBlockInBatch)
 
 		// Fetch prices for batch transactions
 		txsWithPrice := s.processBatch(ctx, txBatch, s.config.PriceFetchBatchSize)
@@ -101,11 +164,13 @@ func (s *Service) runHistoricalSync(ctx context.Context, progress *SyncProgress)
 			progress.TransactionsProcessed+uint64(len(txsWithPrice)))
 
 		// Update progress
+		previousBlock := progress.LastProcessedBlock
 		progress.LastProcessedBlock = lastBlockInBatch
 		if !isFinalIteration {
 			progress.LastProcessedBlock = lastBlockInBatch - 1
 		}
 		progress.TransactionsProcessed += uint64(len(txsWithPrice))
+		s.recordHistoricalProgress(progress, previousBlock)
 
 		if err := s.repo.UpdateSyncProgress(progress); err != nil {
 			log.Printf("Failed to update sync progress: %v", err)
@@ -123,7 +188,7 @@ func (s *Service) runHistoricalSync(ctx context.Context, progress *SyncProgress)
 	s.repo.UpdateSyncProgress(progress)
 }
 
-func (s *Service) filterAndGroupTransactions(transfers []etherscan.TokenTransfer, isFinalIteration bool, lastBlockInBatch uint64) [][]*Transaction {
+func (s *Service) filterAndGroupTransactions(transfers []etherscan.TokenTransfer, pool Pool, isFinalIteration bool, lastBlockInBatch uint64) [][]*Transaction {
 	// Process transfers in batches using a map to track transactions
 	txMap := make(map[string]*Transaction)
 
@@ -141,7 +206,7 @@ func (s *Service) filterAndGroupTransactions(transfers []etherscan.TokenTransfer
 			continue
 		}
 
-		txMap[transfer.Hash] = s.toTransferModel(transfer)
+		txMap[transfer.Hash] = s.toTransferModel(transfer, pool)
 	}
 
 	// Build final batch from the tracked transactions
@@ -161,7 +226,33 @@ func (s *Service) filterAndGroupTransactions(transfers []etherscan.TokenTransfer
 	return txBatch
 }
 
-func (s *Service) toTransferModel(transfer etherscan.TokenTransfer) *Transaction {
+// attachSwaps fetches the pool's Swap events in the block range and attaches them to their transactions
+func (s *Service) attachSwaps(ctx context.Context, txBatch [][]*Transaction, pool Pool, fromBlock, toBlock uint64) error {
+	logs, err := s.nodeClient.GetLogs(ctx, pool.Address, SwapEventTopic, fromBlock, toBlock)
+	if err != nil {
+		return fmt.Errorf("failed to get swap logs: %w", err)
+	}
+
+	logPtrs := make([]*types.Log, 0, len(logs))
+	for i := range logs {
+		logPtrs = append(logPtrs, &logs[i])
+	}
+
+	swapsByTx := make(map[string][]Swap)
+	for _, swap := range decodePoolSwaps(pool, logPtrs) {
+		txHash := strings.ToLower(swap.TxHash)
+		swapsByTx[txHash] = append(swapsByTx[txHash], swap)
+	}
+
+	for _, blockTxs := range txBatch {
+		for _, tx := range blockTxs {
+			tx.Swaps = swapsByTx[strings.ToLower(tx.TxHash)]
+		}
+	}
+	return nil
+}
+
+func (s *Service) toTransferModel(transfer etherscan.TokenTransfer, pool Pool) *Transaction {
 	// Convert transfer to transaction
 	gasUsed := transfer.GetGasUsed()
 	gasPrice := transfer.GetGasPrice()
@@ -170,9 +261,17 @@ func (s *Service) toTransferModel(transfer etherscan.TokenTransfer) *Transaction
 		TxHash:      transfer.Hash,
 		BlockNumber: transfer.GetBlockNumber(),
 		Timestamp:   transfer.GetTimeStamp(),
+		PoolAddress: pool.Address,
 		GasUsed:     NewBigInt(gasUsed),
 		GasPrice:    NewBigInt(gasPrice),
 		Status:      StatusPendingPrice,
+		Finality:    FinalityUnconfirmed,
+		Nonce:       transfer.GetNonce(),
+
+		// Reverted transactions emit no Transfer events, so every transfer belongs to a successful one
+		TransactionIndex: transfer.GetTransactionIndex(),
+		Reverted:         false,
 	}
+	tx.SetFeeETH()
 	return tx
 }
@@ -34,7 +34,7 @@ func TestFilterAndGroupTransactions(t *testing.T) {
 	}
 
 	// Execute
-	result := service.filterAndGroupTransactions(transfers, true, 101)
+	result := service.filterAndGroupTransactions(transfers, testPool, true, 101)
 
 	// Assert
 	assert.Equal(t, 2, len(result), "Should have transactions grouped into 2 blocks")
@@ -65,7 +65,7 @@ func TestToTransferModel(t *testing.T) {
 	transfer := createMockTransfer("tx1", 100, gasUsed, gasPrice)
 
 	// Execute
-	result := service.toTransferModel(transfer)
+	result := service.toTransferModel(transfer, testPool)
 
 	// Assert
 	assert.NotNil(t, result)
@@ -73,5 +73,6 @@ func TestToTransferModel(t *testing.T) {
 	assert.Equal(t, uint64(100), result.BlockNumber)
 	assert.Equal(t, gasUsed.String(), result.GasUsed.String())
 	assert.Equal(t, gasPrice.String(), result.GasPrice.String())
+	assert.Equal(t, testPool.Address, result.PoolAddress)
 	assert.Equal(t, StatusPendingPrice, result.Status)
 }
@@ -0,0 +1,187 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"uniswap-fee-tracker/internal/metrics"
+)
+
+const (
+	// minChunkBlocks keeps short ranges, e.g. blocks missed between restarts, in a single chunk
+	minChunkBlocks = 10000
+	// progressReportInterval is how often aggregated historical sync progress is logged
+	progressReportInterval = time.Minute
+)
+
+// blockRange is a range of blocks after From up to and including To, matching SyncProgress semantics
+type blockRange struct {
+	From uint64
+	To   uint64
+}
+
+// splitBlockRange splits the blocks after from up to and including to into at most n contiguous ranges
+func splitBlockRange(from, to uint64, n int) []blockRange {
+	if to <= from {
+		return nil
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	total := to - from
+	size := (total + uint64(n) - 1) / uint64(n)
+	if size < minChunkBlocks {
+		size = minChunkBlocks
+	}
+
+	var ranges []blockRange
+	for start := from; start < to; start += size {
+		ranges = append(ranges, blockRange{From: start, To: min(start+size, to)})
+	}
+	return ranges
+}
+
+// PoolSyncProgress aggregates the historical sync chunks of a pool
+type PoolSyncProgress struct {
+	PoolAddress           string
+	Chunks                int
+	CompletedChunks       int
+	FailedChunks          int
+	StartBlock            uint64
+	EndBlock              uint64
+	TotalBlocks           uint64
+	ProcessedBlocks       uint64
+	TransactionsProcessed uint64
+}
+
+// Percent returns the share of blocks processed across all chunks
+func (p PoolSyncProgress) Percent() float64 {
+	if p.TotalBlocks == 0 {
+		return 100
+	}
+	return float64(p.ProcessedBlocks) / float64(p.TotalBlocks) * 100
+}
+
+// aggregateSyncProgress sums the chunks of each pool, ordered by pool address
+func aggregateSyncProgress(progresses []SyncProgress) []PoolSyncProgress {
+	byPool := make(map[string]*PoolSyncProgress)
+	for _, progress := range progresses {
+		pool, ok := byPool[progress.PoolAddress]
+		if !ok {
+			pool = &PoolSyncProgress{
+				PoolAddress: progress.PoolAddress,
+				StartBlock:  progress.StartBlock,
+				EndBlock:    progress.EndBlock,
+			}
+			byPool[progress.PoolAddress] = pool
+		}
+
+		pool.Chunks++
+		switch progress.Status {
+		case SyncStatusCompleted:
+			pool.CompletedChunks++
+		case SyncStatusFailed:
+			pool.FailedChunks++
+		}
+		pool.StartBlock = min(pool.StartBlock, progress.StartBlock)
+		pool.EndBlock = max(pool.EndBlock, progress.EndBlock)
+		pool.TotalBlocks += progress.EndBlock - progress.StartBlock
+		pool.ProcessedBlocks += progress.ProcessedBlocks()
+		pool.TransactionsProcessed += progress.TransactionsProcessed
+	}
+
+	result := make([]PoolSyncProgress, 0, len(byPool))
+	for _, pool := range byPool {
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PoolAddress < result[j].PoolAddress
+	})
+	return result
+}
+
+// HistoricalProgress returns the historical sync progress of each pool, aggregated across chunks
+func (s *Service) HistoricalProgress() ([]PoolSyncProgress, error) {
+	progresses, err := s.repo.ListSyncProgress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync progress: %w", err)
+	}
+	return aggregateSyncProgress(progresses), nil
+}
+
+// enqueueHistoricalSync schedules the given chunks on the historical worker pool, starting it on first use
+func (s *Service) enqueueHistoricalSync(ctx context.Context, progresses []SyncProgress) {
+	s.historicalOnce.Do(func() {
+		workers := max(s.config.HistoricalConfig.Workers, 1)
+		log.Printf("Starting %d historical sync workers", workers)
+		for i := 0; i < workers; i++ {
+			go s.historicalWorker(ctx)
+		}
+		go s.reportHistoricalProgress(ctx)
+	})
+
+	go func() {
+		for i := range progresses {
+			select {
+			case <-ctx.Done():
+				return
+			case s.historicalJobs <- &progresses[i]:
+			}
+		}
+	}()
+}
+
+// historicalWorker syncs chunks one at a time. All workers share the clients' rate limiters.
+func (s *Service) historicalWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case queued := <-s.historicalJobs:
+			progress, jobCtx, ok := s.startSyncJob(ctx, queued.ID)
+			if !ok {
+				continue
+			}
+			s.runHistoricalSync(jobCtx, progress)
+			s.finishSyncJob(jobCtx, progress)
+		}
+	}
+}
+
+// recordHistoricalProgress records the throughput of a chunk that advanced from the given block
+func (s *Service) recordHistoricalProgress(progress *SyncProgress, from uint64) {
+	s.throughput.record(progress.ID, progress.LastProcessedBlock, time.Now())
+	if progress.LastProcessedBlock > from {
+		metrics.BlocksProcessed.WithLabelValues(metrics.SyncHistorical).Add(float64(progress.LastProcessedBlock - from))
+	}
+}
+
+// reportHistoricalProgress periodically logs the aggregated progress of pools that are still syncing
+func (s *Service) reportHistoricalProgress(ctx context.Context) {
+	ticker := time.NewTicker(progressReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pools, err := s.HistoricalProgress()
+		if err != nil {
+			log.Printf("Error getting historical sync progress: %v", err)
+			continue
+		}
+		for _, pool := range pools {
+			if pool.CompletedChunks == pool.Chunks {
+				continue
+			}
+			log.Printf("📊 Historical sync of pool %s: %.1f%% | Chunks: %d/%d completed, %d failed | Txns: %d",
+				pool.PoolAddress, pool.Percent(), pool.CompletedChunks, pool.Chunks, pool.FailedChunks, pool.TransactionsProcessed)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBlockRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to uint64
+		n        int
+		expected []blockRange
+	}{
+		{"empty range", 100, 100, 4, nil},
+		{"short range stays whole", 100, 5100, 4, []blockRange{{100, 5100}}},
+		{"even split", 0, 40000, 4, []blockRange{{0, 10000}, {10000, 20000}, {20000, 30000}, {30000, 40000}}},
+		{"uneven split", 0, 50001, 2, []blockRange{{0, 25001}, {25001, 50001}}},
+		{"minimum chunk size", 0, 25000, 8, []blockRange{{0, 10000}, {10000, 20000}, {20000, 25000}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitBlockRange(tt.from, tt.to, tt.n))
+		})
+	}
+}
+
+func TestAggregateSyncProgress(t *testing.T) {
+	progresses := []SyncProgress{
+		{PoolAddress: "0xb", StartBlock: 0, EndBlock: 100, LastProcessedBlock: 100, Status: SyncStatusCompleted, TransactionsProcessed: 10},
+		{PoolAddress: "0xa", StartBlock: 0, EndBlock: 100, LastProcessedBlock: 99, Status: SyncStatusCompleted, TransactionsProcessed: 5},
+		{PoolAddress: "0xa", StartBlock: 100, EndBlock: 200, LastProcessedBlock: 150, Status: SyncStatusRunning, TransactionsProcessed: 3},
+		{PoolAddress: "0xa", StartBlock: 200, EndBlock: 300, LastProcessedBlock: 200, Status: SyncStatusFailed},
+	}
+
+	pools := aggregateSyncProgress(progresses)
+	require.Len(t, pools, 2)
+
+	assert.Equal(t, PoolSyncProgress{
+		PoolAddress:           "0xa",
+		Chunks:                3,
+		CompletedChunks:       1,
+		FailedChunks:          1,
+		StartBlock:            0,
+		EndBlock:              300,
+		TotalBlocks:           300,
+		ProcessedBlocks:       150,
+		TransactionsProcessed: 8,
+	}, pools[0])
+	assert.InDelta(t, 50.0, pools[0].Percent(), 0.001)
+	assert.Equal(t, "0xb", pools[1].PoolAddress)
+	assert.InDelta(t, 100.0, pools[1].Percent(), 0.001)
+}
+
+func TestStartHistoricalSyncRunsChunksInParallel(t *testing.T) {
+	t.Setenv("DISABLE_HISTORICAL_SYNC", "")
+
+	chain := newFakeChain()
+	blocks := []uint64{5000, 15000, 25000, 35000, 39999}
+	for _, n := range blocks {
+		chain.addBlock(n, "a")
+	}
+	repo := newMemoryRepository()
+	pool := config.DefaultPools[0]
+	pool.StartBlock = 1
+	cfg := &config.Config{
+		Pools: []config.PoolConfig{pool},
+		HistoricalConfig: config.HistoricalConfig{
+			Backend:   config.HistoricalBackendLogs,
+			LogWindow: 5000,
+			MaxWindow: 10000,
+			Chunks:    4,
+			Workers:   2,
+		},
+		PriceFetchBatchSize: 10,
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, service.StartHistoricalSync(ctx, 0, 40000))
+
+	require.Eventually(t, func() bool {
+		pools, err := service.HistoricalProgress()
+		return err == nil && len(pools) == 1 && pools[0].CompletedChunks == pools[0].Chunks
+	}, 5*time.Second, 10*time.Millisecond)
+
+	pools, err := service.HistoricalProgress()
+	require.NoError(t, err)
+	assert.Equal(t, 4, pools[0].Chunks)
+	assert.Equal(t, uint64(40000), pools[0].TotalBlocks)
+	assert.Equal(t, uint64(len(blocks)), pools[0].TransactionsProcessed)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, n := range blocks {
+		assert.Contains(t, repo.transactions, chain.receipts[n][0].TxHash.Hex(), "block %d", n)
+	}
+	assert.Equal(t, uint64(40000), repo.lastTracked)
+}
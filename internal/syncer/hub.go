@@ -0,0 +1,148 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// DefaultSubscriptionBuffer is the number of transactions buffered per subscriber
+const DefaultSubscriptionBuffer = 256
+
+// ErrSlowConsumer is returned by Subscription.Err when the subscription was dropped because its buffer was full
+var ErrSlowConsumer = errors.New("subscriber too slow, transactions were dropped")
+
+// StreamFilter selects the newly processed transactions delivered to a subscription
+type StreamFilter struct {
+	MinFeeUSDT  *big.Float // Unpriced transactions never match a minimum fee
+	PoolAddress string     // Pool the transaction is attributed to or swapped in
+	Address     string     // Sender or called contract
+}
+
+// Normalize validates the filter and lowercases its addresses
+func (f *StreamFilter) Normalize() error {
+	if f.PoolAddress != "" && !addressPattern.MatchString(f.PoolAddress) {
+		return fmt.Errorf("%w: invalid pool address %q", ErrInvalidFilter, f.PoolAddress)
+	}
+	if f.Address != "" && !addressPattern.MatchString(f.Address) {
+		return fmt.Errorf("%w: invalid address %q", ErrInvalidFilter, f.Address)
+	}
+	f.PoolAddress = strings.ToLower(f.PoolAddress)
+	f.Address = strings.ToLower(f.Address)
+	return nil
+}
+
+// Matches reports whether the transaction passes the filter
+func (f *StreamFilter) Matches(tx *Transaction) bool {
+	if f.MinFeeUSDT != nil && (tx.FeeUSDT == nil || tx.FeeUSDT.Float == nil || tx.FeeUSDT.Cmp(f.MinFeeUSDT) < 0) {
+		return false
+	}
+	if f.Address != "" && tx.From != f.Address && tx.To != f.Address {
+		return false
+	}
+	if f.PoolAddress != "" && tx.PoolAddress != f.PoolAddress {
+		// Multi-hop transactions are attributed to one pool but carry the swaps of every pool they touched
+		for _, swap := range tx.Swaps {
+			if swap.PoolAddress == f.PoolAddress {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Subscription receives the transactions matching its filter until it is closed
+type Subscription struct {
+	hub    *Hub
+	filter StreamFilter
+	ch     chan *Transaction
+	err    error
+}
+
+// Transactions returns the channel transactions are delivered on. It is closed when the subscription ends.
+func (s *Subscription) Transactions() <-chan *Transaction {
+	return s.ch
+}
+
+// Err returns ErrSlowConsumer once the subscription has been dropped for falling behind
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.remove(s, nil)
+}
+
+// Hub fans newly processed transactions out to in-process subscribers. Publishing never blocks: subscribers
+// whose buffer is full are dropped so that a slow consumer cannot hold up block processing.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewHub creates a hub without subscribers
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe registers a subscriber for the transactions matching filter, buffering up to buffer of them
+func (h *Hub) Subscribe(filter StreamFilter, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+	sub := &Subscription{hub: h, filter: filter, ch: make(chan *Transaction, buffer)}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Subscribers returns the number of active subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Publish delivers the transactions to every subscriber whose filter they match
+func (h *Hub) Publish(txs []*Transaction) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		for _, tx := range txs {
+			if !sub.filter.Matches(tx) {
+				continue
+			}
+			select {
+			case sub.ch <- tx:
+			default:
+				h.removeLocked(sub, ErrSlowConsumer)
+			}
+			if sub.err != nil {
+				break
+			}
+		}
+	}
+}
+
+// remove unregisters the subscription and closes its channel, recording why it ended
+func (h *Hub) remove(sub *Subscription, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sub, err)
+}
+
+func (h *Hub) removeLocked(sub *Subscription, err error) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	sub.err = err
+	close(sub.ch)
+}
@@ -0,0 +1,104 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFilter_Matches(t *testing.T) {
+	const pool = "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	const otherPool = "0x11b815efb8f581194ae79006d24e0d814b7697f6"
+	const sender = "0x5f6ab5e2ab6a2f4d3c6a1b2b9b5c1e7c7f3e9a01"
+	tx := &Transaction{
+		PoolAddress: pool,
+		From:        sender,
+		To:          "0xe592427a0aece92de3edee1f18e0157c05861564",
+		FeeUSDT:     NewBigFloat(big.NewFloat(12.5)),
+		Swaps:       []Swap{{PoolAddress: pool}, {PoolAddress: otherPool}},
+	}
+	unpriced := &Transaction{PoolAddress: pool}
+
+	tests := []struct {
+		name   string
+		filter StreamFilter
+		tx     *Transaction
+		want   bool
+	}{
+		{"no filter", StreamFilter{}, tx, true},
+		{"fee above minimum", StreamFilter{MinFeeUSDT: big.NewFloat(10)}, tx, true},
+		{"fee below minimum", StreamFilter{MinFeeUSDT: big.NewFloat(20)}, tx, false},
+		{"unpriced with minimum", StreamFilter{MinFeeUSDT: big.NewFloat(0)}, unpriced, false},
+		{"attributed pool", StreamFilter{PoolAddress: pool}, tx, true},
+		{"swapped pool", StreamFilter{PoolAddress: otherPool}, tx, true},
+		{"other pool", StreamFilter{PoolAddress: "0xcbcdf9626bc03e24f779434178a73a0b4bad62ed"}, tx, false},
+		{"sender", StreamFilter{Address: sender}, tx, true},
+		{"target", StreamFilter{Address: tx.To}, tx, true},
+		{"other address", StreamFilter{Address: "0x0000000000000000000000000000000000000001"}, tx, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(tt.tx))
+		})
+	}
+
+	filter := StreamFilter{Address: "0x5F6AB5E2AB6A2F4D3C6A1B2B9B5C1E7C7F3E9A01"}
+	require.NoError(t, filter.Normalize())
+	assert.Equal(t, sender, filter.Address)
+	filter = StreamFilter{PoolAddress: "pool"}
+	assert.True(t, errors.Is(filter.Normalize(), ErrInvalidFilter))
+}
+
+func TestHubDropsSlowConsumers(t *testing.T) {
+	hub := NewHub()
+	fast := hub.Subscribe(StreamFilter{}, 10)
+	slow := hub.Subscribe(StreamFilter{}, 1)
+	filtered := hub.Subscribe(StreamFilter{MinFeeUSDT: big.NewFloat(1)}, 1)
+
+	// Publishing returns even though the slow subscriber never reads
+	hub.Publish([]*Transaction{{TxHash: "0x01"}, {TxHash: "0x02"}, {TxHash: "0x03"}})
+
+	assert.Len(t, fast.Transactions(), 3)
+	assert.Nil(t, fast.Err())
+	assert.Equal(t, 2, hub.Subscribers())
+
+	// The slow subscriber receives what fit in its buffer and is then closed
+	tx, ok := <-slow.Transactions()
+	require.True(t, ok)
+	assert.Equal(t, "0x01", tx.TxHash)
+	_, ok = <-slow.Transactions()
+	assert.False(t, ok)
+	assert.True(t, errors.Is(slow.Err(), ErrSlowConsumer))
+
+	// Unpriced transactions did not match the minimum fee
+	assert.Empty(t, filtered.Transactions())
+	filtered.Close()
+	filtered.Close()
+	_, ok = <-filtered.Transactions()
+	assert.False(t, ok)
+	assert.Nil(t, filtered.Err())
+	assert.Equal(t, 1, hub.Subscribers())
+}
+
+func TestProcessBlockTransactionsPublishes(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	sub := service.Hub().Subscribe(StreamFilter{}, 0)
+	defer sub.Close()
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	require.Len(t, sub.Transactions(), 1)
+	tx := <-sub.Transactions()
+	assert.Equal(t, chain.receipts[1][0].TxHash.Hex(), tx.TxHash)
+	assert.Equal(t, StatusProcessed, tx.Status)
+}
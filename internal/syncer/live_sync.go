@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"sort"
+	"sync"
 	"time"
+	"uniswap-fee-tracker/internal/metrics"
 
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -30,13 +33,23 @@ func (s *Service) StartLiveSync(ctx context.Context, startBlock uint64) {
 
 	// Channel for blocks
 	blockChan := make(chan *uint64, blockBufferSize)
+	metrics.ObserveLiveSyncBlock(startBlock)
 
 	// Start workers
-	go s.blockPoller(ctx, blockChan, startBlock)
-	go s.blockProcessor(ctx, blockChan)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.blockPoller(ctx, blockChan, startBlock)
+	}()
+	go func() {
+		defer wg.Done()
+		s.blockProcessor(ctx, blockChan)
+	}()
 
-	// Wait for context cancellation
+	// Wait for context cancellation and for the block being processed
 	<-ctx.Done()
+	wg.Wait()
 	log.Printf("Live sync stopped")
 	return
 }
@@ -47,18 +60,29 @@ func (s *Service) blockPoller(ctx context.Context, blockChan chan *uint64, lastB
 	defer ticker.Stop()
 
 	for {
-		ctx, cancle := context.WithTimeout(context.Background(), time.Second*10)
-		latestBlock, err := s.nodeClient.GetLatestBlockNumber(ctx)
-		cancle()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reqCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		latestBlock, err := s.nodeClient.GetLatestBlockNumber(reqCtx)
+		cancel()
 		if err != nil {
 			log.Printf("Error getting latest block: %v", err)
 			continue
 		}
+		metrics.ObserveLiveSyncTip(latestBlock)
 
 		// Process any new blocks
 		if lastBlock < latestBlock {
 			for i := lastBlock + 1; i <= latestBlock; i++ {
-				blockChan <- &i
+				select {
+				case <-ctx.Done():
+					return
+				case blockChan <- &i:
+				}
 				lastBlock = i
 			}
 		}
@@ -73,8 +97,9 @@ func (s *Service) blockProcessor(ctx context.Context, blockChan chan *uint64) {
 			log.Printf("blockProcessor: Live sync stopped")
 			return
 		case block := <-blockChan:
-			// Process block transactions
-			if err := s.processBlockTransactions(ctx, block); err != nil {
+			// Process block transactions. A block being processed when live sync is paused is finished, so
+			// the last tracked block stays consistent with the stored transactions.
+			if err := s.processBlockTransactions(context.WithoutCancel(ctx), block); err != nil {
 				log.Printf("Error processing block %d: %v", block, err)
 				//	TODO: handle block error
 			}
@@ -91,10 +116,22 @@ func (s *Service) processBlockTransactions(ctx context.Context, blockNum *uint64
 		return fmt.Errorf("failed to get block %d: %w", *blockNum, err)
 	}
 
+	// Roll back and reprocess the canonical chain if the block does not extend the stored one
+	reorged, err := s.handleReorg(ctx, block)
+	if err != nil {
+		return fmt.Errorf("failed to handle reorg at block %d: %w", *blockNum, err)
+	}
+	if reorged {
+		return s.processBlockTransactions(ctx, blockNum)
+	}
+
 	receipts, err := s.nodeClient.GetBlockReceipts(context.Background(), *blockNum)
 	if err != nil {
 		return fmt.Errorf("failed to get receipts for block %d: %w", *blockNum, err)
 	}
+	if len(receipts) > 0 && receipts[0].BlockHash != block.Hash() {
+		return fmt.Errorf("block %d changed while fetching receipts", *blockNum)
+	}
 
 	// Create receipt map for quick lookup
 	receiptMap := make(map[string]*types.Receipt)
@@ -108,17 +145,34 @@ func (s *Service) processBlockTransactions(ctx context.Context, blockNum *uint64
 	// Save transactions to database
 	if len(transactions) > 0 {
 		// Get ETH/USDT price for this block
-		kline, err := s.binanceClient.GetPrice(context.Background(), "ETHUSDT", blockTime)
+		quote, err := s.ethPrice(ctx, *blockNum, blockTime)
 		if err != nil {
+			// Store the transactions unpriced; the price reconciler retries them
 			log.Printf("Error getting ETH price for block %d: %v", *blockNum, err)
-			return fmt.Errorf("failed to get ETH price: %w", err)
-		}
-		for _, transaction := range transactions {
-			transaction.UpdatePrices(kline.Close)
+			for _, transaction := range transactions {
+				transaction.MarkPriceFailed(err)
+			}
+		} else {
+			for _, transaction := range transactions {
+				transaction.ApplyQuote(quote)
+			}
+			s.convertFees(ctx, transactions, *blockNum, blockTime)
 		}
+		s.recordPoolPrice(ctx, transactions, *blockNum, blockTime, true)
 		if err := s.repo.SaveTransactions(transactions); err != nil {
 			return fmt.Errorf("failed to save transactions: %w", err)
 		}
+		s.hub.Publish(transactions)
+	}
+
+	// Record the block hash so the next block can be checked against it
+	if err := s.repo.SaveProcessedBlock(&ProcessedBlock{
+		Number:     *blockNum,
+		Hash:       block.Hash().Hex(),
+		ParentHash: block.ParentHash().Hex(),
+		Timestamp:  blockTime,
+	}); err != nil {
+		return fmt.Errorf("failed to save processed block: %w", err)
 	}
 
 	// Update tracker
@@ -126,12 +180,15 @@ func (s *Service) processBlockTransactions(ctx context.Context, blockNum *uint64
 		log.Printf("Error updating last tracked block %d: %v", *blockNum, err)
 		return fmt.Errorf("failed to update last tracked block: %w", err)
 	}
+	s.throughput.record(liveSyncJob, *blockNum, time.Now())
+	metrics.ObserveLiveSyncBlock(*blockNum)
+	metrics.BlocksProcessed.WithLabelValues(metrics.SyncLive).Inc()
 	log.Printf("✅Processing live block completed %d", *blockNum)
 	return nil
 }
 
 func (s *Service) filterTransaction(block *types.Block, receiptMap map[string]*types.Receipt, blockNum *uint64, blockTime time.Time) []*Transaction {
-	// Filter and process Uniswap WETH-USDC transactions
+	// Filter and process transactions of the tracked Uniswap pools
 	var transactions []*Transaction
 
 	for _, tx := range block.Transactions() {
@@ -142,36 +199,75 @@ func (s *Service) filterTransaction(block *types.Block, receiptMap map[string]*t
 			continue
 		}
 
-		// Check for Uniswap V3 WETH-USDC swap events
-		is_WETH_USDC_PoolTx := false
-		for _, log := range receipt.Logs {
-			// Check if log is from WETH-USDC pool and is a swap event
-			if IsWethUsdcPool(log.Address.Hex()) {
-				is_WETH_USDC_PoolTx = true
-				break
-			}
-		}
-
-		// Skip if not a swap
-		if !is_WETH_USDC_PoolTx {
+		// Skip if the transaction did not touch a tracked pool
+		pool, ok := s.txPool(tx, receipt)
+		if !ok {
 			continue
 		}
 
-		// Calculate effective gas price (handles both legacy and EIP-1559 transactions)
-		gasUsed := big.NewInt(int64(receipt.GasUsed))
-		effectiveGasPrice := receipt.EffectiveGasPrice
-
-		// Create transaction model
-		txModel := &Transaction{
-			TxHash:      txHash,
-			BlockNumber: *blockNum,
-			Timestamp:   blockTime,
-			GasUsed:     NewBigInt(gasUsed),
-			GasPrice:    NewBigInt(effectiveGasPrice),
-			Status:      StatusPendingPrice,
-		}
-
+		txModel := s.newTransactionFromReceipt(receipt, pool, *blockNum, blockTime)
+		txModel.SetBaseFee(block.BaseFee())
+		txModel.SetFeeCaps(tx)
+		txModel.SetOrigin(tx)
 		transactions = append(transactions, txModel)
 	}
 	return transactions
 }
+
+// txPool returns the tracked pool a transaction is attributed to. Reverted transactions emit no logs, so
+// those calling a tracked pool directly are matched by their target instead.
+func (s *Service) txPool(tx *types.Transaction, receipt *types.Receipt) (Pool, bool) {
+	pool, ok := s.matchPool(receipt)
+	if !ok && receipt.Status == types.ReceiptStatusFailed && tx.To() != nil {
+		pool, ok = s.Pool(tx.To().Hex())
+	}
+	return pool, ok
+}
+
+// matchPool returns the first tracked pool that emitted a log in the receipt
+func (s *Service) matchPool(receipt *types.Receipt) (Pool, bool) {
+	for _, log := range receipt.Logs {
+		if pool, ok := s.Pool(log.Address.Hex()); ok {
+			return pool, true
+		}
+	}
+	return Pool{}, false
+}
+
+// newTransactionFromReceipt creates an unpriced transaction model from a transaction receipt,
+// attributed to the given pool and including the swaps of every tracked pool
+func (s *Service) newTransactionFromReceipt(receipt *types.Receipt, pool Pool, blockNum uint64, blockTime time.Time) *Transaction {
+	// Calculate effective gas price (handles both legacy and EIP-1559 transactions)
+	gasUsed := new(big.Int).SetUint64(receipt.GasUsed)
+	effectiveGasPrice := receipt.EffectiveGasPrice
+
+	tx := &Transaction{
+		TxHash:      receipt.TxHash.Hex(),
+		BlockNumber: blockNum,
+		Timestamp:   blockTime,
+		GasUsed:     NewBigInt(gasUsed),
+		GasPrice:    NewBigInt(effectiveGasPrice),
+		PoolAddress: pool.Address,
+		Status:      StatusPendingPrice,
+		Finality:    FinalityUnconfirmed,
+		Swaps:       s.decodeSwaps(receipt.Logs),
+		TxType:      receipt.Type,
+
+		TransactionIndex: receipt.TransactionIndex,
+		Reverted:         receipt.Status == types.ReceiptStatusFailed,
+	}
+	tx.SetFeeETH()
+	return tx
+}
+
+// decodeSwaps decodes the Swap events emitted by any tracked pool, in log order
+func (s *Service) decodeSwaps(logs []*types.Log) []Swap {
+	var swaps []Swap
+	for _, pool := range s.pools {
+		swaps = append(swaps, decodePoolSwaps(pool, logs)...)
+	}
+	sort.Slice(swaps, func(i, j int) bool {
+		return swaps[i].LogIndex < swaps[j].LogIndex
+	})
+	return swaps
+}
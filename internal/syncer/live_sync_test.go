@@ -0,0 +1,642 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/ethereum"
+	"uniswap-fee-tracker/internal/price"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeChain is an in-memory node whose canonical chain can be replaced to simulate reorgs
+type fakeChain struct {
+	ethereum.Client
+	blocks    map[uint64]*types.Block
+	receipts  map[uint64][]*types.Receipt
+	finalized uint64 // Block reported for the safe and finalized tags
+	maxRange  uint64 // When set, GetLogs rejects wider block ranges
+	logsErr   error  // When set, GetLogs fails with it
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		blocks:   make(map[uint64]*types.Block),
+		receipts: make(map[uint64][]*types.Receipt),
+	}
+}
+
+// extend builds canonical blocks from..to on top of the current chain, each with one pool swap.
+// The fork label makes blocks of different branches hash differently.
+func (c *fakeChain) extend(from, to uint64, fork string) {
+	for n := from; n <= to; n++ {
+		c.addBlock(n, fork)
+	}
+}
+
+// addBlock builds block n with one pool swap, linked to block n-1 when it exists
+func (c *fakeChain) addBlock(n uint64, fork string) {
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(n),
+		Time:       uint64(1700000000 + n*12),
+		Extra:      []byte(fork),
+		Difficulty: big.NewInt(0),
+	}
+	if parent, ok := c.blocks[n-1]; ok {
+		header.ParentHash = parent.Hash()
+	}
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: n, Data: []byte(fork), GasPrice: big.NewInt(1e9)})
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+	swapLog := newSwapLog(testPool.Address, tx.Hash(), big.NewInt(-1000_000000), big.NewInt(5e17), 1)
+	swapLog.BlockNumber = n
+	c.blocks[n] = block
+	c.receipts[n] = []*types.Receipt{{
+		TxHash:            tx.Hash(),
+		BlockHash:         block.Hash(),
+		BlockNumber:       header.Number,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(1e9),
+		Logs:              []*types.Log{swapLog},
+	}}
+}
+
+func (c *fakeChain) GetLatestBlockNumber(context.Context) (uint64, error) {
+	var latest uint64
+	for number := range c.blocks {
+		latest = max(latest, number)
+	}
+	return latest, nil
+}
+
+func (c *fakeChain) GetBlockNumberByTag(context.Context, string) (uint64, error) {
+	return c.finalized, nil
+}
+
+func (c *fakeChain) GetBlockByNumber(_ context.Context, number uint64) (*types.Block, error) {
+	block, ok := c.blocks[number]
+	if !ok {
+		return nil, fmt.Errorf("block %d: %w", number, ethereum.ErrNotFound)
+	}
+	return block, nil
+}
+
+func (c *fakeChain) GetHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error) {
+	block, err := c.GetBlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+func (c *fakeChain) GetBlockReceipts(_ context.Context, number uint64) ([]*types.Receipt, error) {
+	return c.receipts[number], nil
+}
+
+func (c *fakeChain) GetLogs(_ context.Context, address string, _ string, fromBlock, toBlock uint64) ([]types.Log, error) {
+	if c.logsErr != nil {
+		return nil, c.logsErr
+	}
+	if c.maxRange > 0 && toBlock-fromBlock+1 > c.maxRange {
+		return nil, fmt.Errorf("query returned more than 10000 results")
+	}
+	var logs []types.Log
+	for n, receipts := range c.receipts {
+		if n < fromBlock || n > toBlock {
+			continue
+		}
+		for _, receipt := range receipts {
+			for _, l := range receipt.Logs {
+				if strings.EqualFold(l.Address.Hex(), address) {
+					logs = append(logs, *l)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeChain) GetTransactionReceipt(_ context.Context, txHash string) (*types.Receipt, error) {
+	return c.receipt(txHash)
+}
+
+func (c *fakeChain) GetTransactionReceipts(_ context.Context, txHashes []string) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		receipt, err := c.receipt(txHash)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+func (c *fakeChain) GetTransactions(_ context.Context, txHashes []string) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		tx, err := c.transaction(txHash)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (c *fakeChain) transaction(txHash string) (*types.Transaction, error) {
+	for _, block := range c.blocks {
+		for _, tx := range block.Transactions() {
+			if tx.Hash().Hex() == txHash {
+				return tx, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("transaction %s: %w", txHash, ethereum.ErrNotFound)
+}
+
+func (c *fakeChain) receipt(txHash string) (*types.Receipt, error) {
+	for _, receipts := range c.receipts {
+		for _, receipt := range receipts {
+			if receipt.TxHash.Hex() == txHash {
+				return receipt, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("receipt %s: %w", txHash, ethereum.ErrNotFound)
+}
+
+func (c *fakeChain) GetHeaders(ctx context.Context, numbers []uint64) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, len(numbers))
+	for _, number := range numbers {
+		header, err := c.GetHeaderByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// fakePriceClient returns a constant ETH price
+type fakePriceClient struct{}
+
+func (fakePriceClient) Name() string {
+	return "fake"
+}
+
+func (fakePriceClient) GetPrice(_ context.Context, req price.Request) (*price.Quote, error) {
+	return &price.Quote{Pair: req.Pair, Price: big.NewFloat(2000), Timestamp: req.Timestamp, Source: "fake"}, nil
+}
+
+// memoryRepository keeps sync state in memory and is safe for concurrent use
+type memoryRepository struct {
+	Repository
+	mu           sync.Mutex
+	transactions map[string]*Transaction
+	blocks       map[uint64]*ProcessedBlock
+	progress     map[uint]SyncProgress
+	lastTracked  uint64
+	priceUpdates int // Transactions written by UpdateTransactionPrices
+	webhooks     []WebhookSubscription
+	deliveries   []*WebhookDelivery
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		transactions: make(map[string]*Transaction),
+		blocks:       make(map[uint64]*ProcessedBlock),
+		progress:     make(map[uint]SyncProgress),
+	}
+}
+
+func (r *memoryRepository) SaveTransactions(txs []*Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range txs {
+		r.transactions[tx.TxHash] = tx
+	}
+	deliveries, err := newWebhookDeliveries(r.webhooks, txs)
+	for _, delivery := range deliveries {
+		delivery.ID = uint(len(r.deliveries) + 1)
+		r.deliveries = append(r.deliveries, delivery)
+	}
+	return err
+}
+
+func (r *memoryRepository) GetTransaction(txHash string) (*Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx, ok := r.transactions[txHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return tx, nil
+}
+
+func (r *memoryRepository) FinalizeTransactions(blockNumber uint64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var promoted int64
+	for _, tx := range r.transactions {
+		if tx.Finality != FinalityFinalized && tx.Status == StatusProcessed && tx.BlockNumber <= blockNumber {
+			tx.Finality = FinalityFinalized
+			promoted++
+		}
+	}
+	return promoted, nil
+}
+
+func (r *memoryRepository) GetUnpricedTransactions(filter UnpricedFilter) ([]*Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*Transaction
+	for _, tx := range r.transactions {
+		if tx.Status == StatusProcessed {
+			continue
+		}
+		if filter.FromBlock != nil && tx.BlockNumber < *filter.FromBlock ||
+			filter.ToBlock != nil && tx.BlockNumber > *filter.ToBlock {
+			continue
+		}
+		if filter.DueBy != nil && tx.NextPriceAttemptAt != nil && tx.NextPriceAttemptAt.After(*filter.DueBy) {
+			continue
+		}
+		if filter.AfterTxHash != "" && (tx.BlockNumber < filter.AfterBlock ||
+			tx.BlockNumber == filter.AfterBlock && tx.TxHash <= filter.AfterTxHash) {
+			continue
+		}
+		result = append(result, tx)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+		return result[i].TxHash < result[j].TxHash
+	})
+	if len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) GetTransactionsMissingFees(filter MissingFeesFilter) ([]*Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*Transaction
+	for _, tx := range r.transactions {
+		if tx.Status != StatusProcessed || !hasFeeETH([]*Transaction{tx}) {
+			continue
+		}
+		if filter.AfterTxHash != "" && (tx.BlockNumber < filter.AfterBlock ||
+			tx.BlockNumber == filter.AfterBlock && tx.TxHash <= filter.AfterTxHash) {
+			continue
+		}
+		for _, currency := range filter.Currencies {
+			if _, ok := tx.Fee(currency); !ok {
+				result = append(result, tx)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+		return result[i].TxHash < result[j].TxHash
+	})
+	if len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// ListTransactions supports block and time ranges sorted by ascending block number. Like rows loaded from
+// the database, the returned transactions are copies.
+func (r *memoryRepository) ListTransactions(filter TransactionFilter) (*TransactionPage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var afterBlock uint64
+	var afterTxHash string
+	if filter.Cursor != "" {
+		value, txHash, err := filter.decodeCursor()
+		if err != nil {
+			return nil, err
+		}
+		afterBlock, afterTxHash = value.(uint64), txHash
+	}
+
+	var result []*Transaction
+	for _, tx := range r.transactions {
+		if filter.FromBlock != nil && tx.BlockNumber < *filter.FromBlock ||
+			filter.ToBlock != nil && tx.BlockNumber > *filter.ToBlock ||
+			filter.FromTime != nil && tx.Timestamp.Before(*filter.FromTime) ||
+			filter.ToTime != nil && tx.Timestamp.After(*filter.ToTime) {
+			continue
+		}
+		if afterTxHash != "" && (tx.BlockNumber < afterBlock || tx.BlockNumber == afterBlock && tx.TxHash <= afterTxHash) {
+			continue
+		}
+		stored := *tx
+		result = append(result, &stored)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+		return result[i].TxHash < result[j].TxHash
+	})
+
+	page := &TransactionPage{Transactions: result}
+	if len(result) > filter.Limit {
+		page.Transactions = result[:filter.Limit]
+		page.NextCursor = filter.encodeCursor(page.Transactions[filter.Limit-1])
+	}
+	return page, nil
+}
+
+func (r *memoryRepository) UpdateTransactionPrices(txs []*Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range txs {
+		r.transactions[tx.TxHash] = tx
+	}
+	r.priceUpdates += len(txs)
+	return nil
+}
+
+func (r *memoryRepository) CreateSyncProgress(sp *SyncProgress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sp.ID = uint(len(r.progress) + 1)
+	r.progress[sp.ID] = *sp
+	return nil
+}
+
+func (r *memoryRepository) UpdateSyncProgress(sp *SyncProgress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress[sp.ID] = *sp
+	return nil
+}
+
+func (r *memoryRepository) HasSyncProgress(poolAddress string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sp := range r.progress {
+		if sp.PoolAddress == poolAddress {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRepository) GetSyncProgress(id uint) (*SyncProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sp, ok := r.progress[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &sp, nil
+}
+
+func (r *memoryRepository) GetIncompleteSyncProgress() ([]SyncProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []SyncProgress
+	for _, sp := range r.progress {
+		if sp.Status != SyncStatusCompleted && sp.Status != SyncStatusCancelled {
+			result = append(result, sp)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) ListSyncProgress() ([]SyncProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]SyncProgress, 0, len(r.progress))
+	for _, sp := range r.progress {
+		result = append(result, sp)
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) SaveProcessedBlock(block *ProcessedBlock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blocks[block.Number] = block
+	return nil
+}
+
+func (r *memoryRepository) GetProcessedBlock(number uint64) (*ProcessedBlock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	block, ok := r.blocks[number]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return block, nil
+}
+
+func (r *memoryRepository) RollbackToBlock(blockNumber uint64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range r.transactions {
+		if tx.BlockNumber > blockNumber && tx.Finality == FinalityFinalized {
+			return 0, fmt.Errorf("%w: transaction %s", ErrFinalizedReorg, tx.TxHash)
+		}
+	}
+	var removed int64
+	for hash, tx := range r.transactions {
+		if tx.BlockNumber > blockNumber {
+			delete(r.transactions, hash)
+			removed++
+		}
+	}
+	for number := range r.blocks {
+		if number > blockNumber {
+			delete(r.blocks, number)
+		}
+	}
+	r.lastTracked = blockNumber
+	return removed, nil
+}
+
+func (r *memoryRepository) UpdateLastTrackedBlock(blockNumber uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastTracked = blockNumber
+	return nil
+}
+
+func TestProcessBlockTransactionsHandlesReorg(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 5, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	for n := uint64(1); n <= 5; n++ {
+		blockNum := n
+		require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+	}
+	assert.Len(t, repo.transactions, 5)
+
+	orphaned := make([]string, 0, 3)
+	for n := uint64(3); n <= 5; n++ {
+		orphaned = append(orphaned, chain.receipts[n][0].TxHash.Hex())
+	}
+
+	// Blocks 3-5 are replaced by a longer fork
+	chain.extend(3, 6, "b")
+	blockNum := uint64(6)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	for _, txHash := range orphaned {
+		assert.NotContains(t, repo.transactions, txHash)
+	}
+	assert.Len(t, repo.transactions, 6)
+	for n := uint64(1); n <= 6; n++ {
+		require.Contains(t, repo.blocks, n)
+		assert.Equal(t, chain.blocks[n].Hash().Hex(), repo.blocks[n].Hash)
+		assert.Contains(t, repo.transactions, chain.receipts[n][0].TxHash.Hex())
+	}
+	assert.Equal(t, uint64(6), repo.lastTracked)
+}
+
+func TestProcessBlockTransactionsWithoutReorg(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	for n := uint64(1); n <= 2; n++ {
+		blockNum := n
+		require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+	}
+
+	assert.Len(t, repo.transactions, 2)
+	assert.Equal(t, chain.blocks[1].Hash().Hex(), repo.blocks[2].ParentHash)
+	assert.Equal(t, uint64(2), repo.lastTracked)
+}
+
+func TestProcessBlockTransactionsRecordsPoolPrice(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}, RecordPoolPrice: true}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	// The block's swaps price it without asking the node
+	chain.logsErr = errors.New("eth_getLogs unavailable")
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	tx := repo.transactions[chain.receipts[1][0].TxHash.Hex()]
+	require.NotNil(t, tx)
+	assert.Equal(t, "2000.000000", tx.ETHPrice.Text('f', 6))
+	require.NotNil(t, tx.PoolETHPrice)
+	// The price comes from the sqrtPriceX96 of the block's swap
+	expected := price.ETHPriceFromSqrtPriceX96(big.NewInt(1234567890), config.DefaultPools[0])
+	assert.Equal(t, expected.Text('e', 10), tx.PoolETHPrice.Text('e', 10))
+}
+
+func TestProcessBlockTransactionsRecordsContext(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	router := common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564")
+	pool := common.HexToAddress(testPool.Address)
+
+	swapTx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 7, To: &router})
+	revertedTx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 8, To: &pool})
+	otherRevertedTx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 9, To: &router})
+
+	chain := newFakeChain()
+	header := &types.Header{Number: big.NewInt(1), Time: 1700000012, Difficulty: big.NewInt(0)}
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{
+		Transactions: []*types.Transaction{swapTx, revertedTx, otherRevertedTx},
+	})
+	chain.blocks[1] = block
+	receipt := func(tx *types.Transaction, index uint, status uint64, logs []*types.Log) *types.Receipt {
+		return &types.Receipt{
+			Type:              types.DynamicFeeTxType,
+			Status:            status,
+			TxHash:            tx.Hash(),
+			BlockHash:         block.Hash(),
+			BlockNumber:       header.Number,
+			TransactionIndex:  index,
+			GasUsed:           50000,
+			EffectiveGasPrice: big.NewInt(1e9),
+			Logs:              logs,
+		}
+	}
+	swapLog := newSwapLog(testPool.Address, swapTx.Hash(), big.NewInt(-1000_000000), big.NewInt(5e17), 1)
+	chain.receipts[1] = []*types.Receipt{
+		receipt(swapTx, 0, types.ReceiptStatusSuccessful, []*types.Log{swapLog}),
+		receipt(revertedTx, 1, types.ReceiptStatusFailed, nil),
+		receipt(otherRevertedTx, 2, types.ReceiptStatusFailed, nil),
+	}
+
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+	require.Len(t, repo.transactions, 2)
+
+	tx := repo.transactions[swapTx.Hash().Hex()]
+	require.NotNil(t, tx)
+	assert.Equal(t, sender, tx.From)
+	assert.Equal(t, strings.ToLower(router.Hex()), tx.To)
+	assert.Equal(t, uint64(7), tx.Nonce)
+	assert.Equal(t, uint(0), tx.TransactionIndex)
+	assert.False(t, tx.Reverted)
+
+	// Reverted transactions emit no logs and are only matched when they call a tracked pool directly
+	tx = repo.transactions[revertedTx.Hash().Hex()]
+	require.NotNil(t, tx)
+	assert.Equal(t, testPool.Address, tx.PoolAddress)
+	assert.Equal(t, testPool.Address, tx.To)
+	assert.Equal(t, uint(1), tx.TransactionIndex)
+	assert.True(t, tx.Reverted)
+	assert.Empty(t, tx.Swaps)
+	assert.Equal(t, "0.000050000000000000", tx.FeeETH.Text('f', 18))
+}
+
+func TestProcessBlockTransactionsStoresUnpricedFeeETH(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	prices := &flakyPriceClient{failing: map[int64]bool{1700000012: true}}
+	service := NewService(cfg, nil, prices, chain, repo)
+
+	blockNum := uint64(1)
+	require.NoError(t, service.processBlockTransactions(context.Background(), &blockNum))
+
+	// The ETH fee needs no price, so it is known while the transaction waits for the reconciler
+	tx := repo.transactions[chain.receipts[1][0].TxHash.Hex()]
+	require.NotNil(t, tx)
+	assert.Equal(t, StatusFailed, tx.Status)
+	assert.Nil(t, tx.FeeUSDT)
+	assert.Equal(t, "0.000021000000000000", tx.FeeETH.Text('f', 18))
+}
@@ -1,9 +1,13 @@
 package syncer
 
 import (
+	"log"
 	"math/big"
+	"strings"
 	"time"
+	"uniswap-fee-tracker/internal/price"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +20,15 @@ const (
 	StatusFailed       TransactionStatus = "FAILED"
 )
 
+// FinalityStatus represents whether a transaction's fee can still change. A transaction is finalized once its
+// block can no longer be reorganized away and it is priced.
+type FinalityStatus string
+
+const (
+	FinalityUnconfirmed FinalityStatus = "UNCONFIRMED"
+	FinalityFinalized   FinalityStatus = "FINALIZED"
+)
+
 // SyncStatus represents the status of a sync operation
 type SyncStatus string
 
@@ -24,6 +37,7 @@ const (
 	SyncStatusCompleted SyncStatus = "COMPLETED"
 	SyncStatusFailed    SyncStatus = "FAILED"
 	SyncStatusPaused    SyncStatus = "PAUSED"
+	SyncStatusCancelled SyncStatus = "CANCELLED"
 )
 
 // Transaction represents a processed Ethereum transaction with its fee in USDT
@@ -31,18 +45,55 @@ type Transaction struct {
 	TxHash      string            `gorm:"primaryKey;type:varchar(66)" json:"tx_hash"`
 	BlockNumber uint64            `gorm:"index" json:"block_number"`
 	Timestamp   time.Time         `gorm:"index" json:"timestamp"`
-	GasUsed     *BigInt           `gorm:"type:numeric(78,0)" json:"gas_used"`  // Custom type
-	GasPrice    *BigInt           `gorm:"type:numeric(78,0)" json:"gas_price"` // Custom type
-	FeeETH      *BigFloat         `gorm:"type:numeric(38,18)" json:"fee_eth"`  // Custom type
-	FeeUSDT     *BigFloat         `gorm:"type:numeric(38,6)" json:"fee_usdt"`  // Custom type
-	ETHPrice    *BigFloat         `gorm:"type:numeric(38,6)" json:"eth_price"` // Custom type
+	PoolAddress string            `gorm:"type:varchar(42);index" json:"pool_address"` // First tracked pool the transaction touched
+	GasUsed     *BigInt           `gorm:"type:numeric(78,0)" json:"gas_used"`         // Custom type
+	GasPrice    *BigInt           `gorm:"type:numeric(78,0)" json:"gas_price"`        // Custom type
+	FeeETH      *BigFloat         `gorm:"type:numeric(38,18)" json:"fee_eth"`         // Custom type
+	FeeUSDT     *BigFloat         `gorm:"type:numeric(38,6)" json:"fee_usdt"`         // Custom type
+	ETHPrice    *BigFloat         `gorm:"type:numeric(38,6)" json:"eth_price"`        // Custom type
+	PriceSource string            `gorm:"type:varchar(32)" json:"price_source"`       // Provider the ETH price came from
 	Status      TransactionStatus `gorm:"type:varchar(20)" json:"status"`
+	Finality    FinalityStatus    `gorm:"type:varchar(20);default:UNCONFIRMED;index" json:"finality"`
+	Swaps       []Swap            `gorm:"foreignKey:TxHash;references:TxHash;constraint:OnDelete:CASCADE" json:"swaps,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	UpdatedAt   time.Time         `json:"updated_at"`
+
+	// Price retry bookkeeping for FAILED and PENDING_PRICE transactions
+	PriceAttempts      int        `gorm:"not null;default:0" json:"price_attempts"`
+	LastPriceError     string     `gorm:"type:text" json:"last_price_error,omitempty"`
+	NextPriceAttemptAt *time.Time `gorm:"index" json:"next_price_attempt_at,omitempty"`
+
+	// ETH price derived on-chain from the tracked WETH/stablecoin pool, kept for comparison with ETHPrice
+	PoolETHPrice *BigFloat `gorm:"type:numeric(38,6)" json:"pool_eth_price,omitempty"`
+
+	// Candle interval the ETH price was taken from, coarser than 1s for dates without 1s klines
+	PriceInterval string `gorm:"type:varchar(8)" json:"price_interval,omitempty"`
+
+	// Fee converted to each configured FEE_CURRENCIES currency
+	Fees []TransactionFee `gorm:"foreignKey:TxHash;references:TxHash;constraint:OnDelete:CASCADE" json:"fees,omitempty"`
+
+	// EIP-1559 fee breakdown. BaseFeePerGas is 0 for blocks before the London fork, whose whole fee went
+	// to the miner, and nil when the block header has not been looked up.
+	TxType               uint8     `gorm:"not null;default:0" json:"tx_type"`
+	BaseFeePerGas        *BigInt   `gorm:"type:numeric(78,0)" json:"base_fee_per_gas,omitempty"`
+	MaxFeePerGas         *BigInt   `gorm:"type:numeric(78,0)" json:"max_fee_per_gas,omitempty"`          // EIP-1559 transactions only
+	MaxPriorityFeePerGas *BigInt   `gorm:"type:numeric(78,0)" json:"max_priority_fee_per_gas,omitempty"` // EIP-1559 transactions only
+	BurnedFeeETH         *BigFloat `gorm:"type:numeric(38,18)" json:"burned_fee_eth,omitempty"`
+	BurnedFeeUSDT        *BigFloat `gorm:"type:numeric(38,6)" json:"burned_fee_usdt,omitempty"`
+	TipFeeETH            *BigFloat `gorm:"type:numeric(38,18)" json:"tip_fee_eth,omitempty"`
+	TipFeeUSDT           *BigFloat `gorm:"type:numeric(38,6)" json:"tip_fee_usdt,omitempty"`
+
+	// Transaction context. To is the contract called, usually a router or aggregator rather than the pool,
+	// and is empty for contract creations. Reverted transactions paid their fee without executing a swap.
+	From             string `gorm:"type:varchar(42);index" json:"from"`
+	To               string `gorm:"type:varchar(42);index" json:"to"`
+	Nonce            uint64 `gorm:"not null;default:0" json:"nonce"`
+	TransactionIndex uint   `gorm:"not null;default:0" json:"transaction_index"`
+	Reverted         bool   `gorm:"not null;default:false;index" json:"reverted"`
 }
 
-// UpdatePrices calculates transaction fees based on ETH price
-func (tx *Transaction) UpdatePrices(ethPrice *big.Float) {
+// SetFeeETH calculates the transaction fee in ETH, which unlike the USDT fee needs no price
+func (tx *Transaction) SetFeeETH() {
 	// Calculate fee in Wei (gas_used * gas_price)
 	feeWei := new(big.Int).Mul(tx.GasUsed.Int, tx.GasPrice.Int)
 
@@ -51,20 +102,130 @@ func (tx *Transaction) UpdatePrices(ethPrice *big.Float) {
 		new(big.Float).SetInt(feeWei),
 		new(big.Float).SetInt(big.NewInt(1e18)),
 	))
+}
+
+// UpdatePrices calculates transaction fees based on ETH price
+func (tx *Transaction) UpdatePrices(ethPrice *big.Float) {
+	tx.SetFeeETH()
 	// Store ETH price
 	tx.ETHPrice = NewBigFloat(new(big.Float).Set(ethPrice))
 
 	// Calculate fee in USDT
 	tx.FeeUSDT = NewBigFloat(new(big.Float).Mul(tx.FeeETH.Float, ethPrice))
+	tx.updateFeeBreakdown(ethPrice)
 
 	// Update status
 	tx.Status = StatusProcessed
+	tx.LastPriceError = ""
+	tx.NextPriceAttemptAt = nil
 	tx.UpdatedAt = time.Now()
 }
 
+// ApplyQuote prices the transaction with an ETH/USDT quote and records its source
+func (tx *Transaction) ApplyQuote(quote *price.Quote) {
+	tx.UpdatePrices(quote.Price)
+	tx.PriceSource = quote.Source
+	tx.PriceInterval = quote.Interval
+	if quote.Source == price.SourceUniswap {
+		tx.PoolETHPrice = NewBigFloat(new(big.Float).Set(quote.Price))
+	}
+}
+
+// ApplyCurrencyQuote converts the transaction fee with an ETH quote in another currency, replacing any
+// previous conversion to that currency. Transactions without an ETH fee are left unchanged.
+func (tx *Transaction) ApplyCurrencyQuote(quote *price.Quote) {
+	if tx.FeeETH == nil || tx.FeeETH.Float == nil {
+		return
+	}
+	fee := TransactionFee{
+		TxHash:      tx.TxHash,
+		Currency:    quote.Pair.Quote,
+		BlockNumber: tx.BlockNumber,
+		Fee:         NewBigFloat(new(big.Float).Mul(tx.FeeETH.Float, quote.Price)),
+		ETHPrice:    NewBigFloat(new(big.Float).Set(quote.Price)),
+		PriceSource: quote.Source,
+		UpdatedAt:   time.Now(),
+	}
+	for i := range tx.Fees {
+		if tx.Fees[i].Currency == fee.Currency {
+			fee.CreatedAt = tx.Fees[i].CreatedAt
+			tx.Fees[i] = fee
+			return
+		}
+	}
+	tx.Fees = append(tx.Fees, fee)
+}
+
+// Fee returns the transaction fee converted to currency, if any
+func (tx *Transaction) Fee(currency string) (*TransactionFee, bool) {
+	for i := range tx.Fees {
+		if tx.Fees[i].Currency == currency {
+			return &tx.Fees[i], true
+		}
+	}
+	return nil, false
+}
+
+// SetOrigin records the sender, target contract and nonce of the transaction body. The sender is
+// recovered from the signature; it is left empty if the signature cannot be verified.
+func (tx *Transaction) SetOrigin(ethTx *types.Transaction) {
+	tx.Nonce = ethTx.Nonce()
+	if to := ethTx.To(); to != nil {
+		tx.To = strings.ToLower(to.Hex())
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(ethTx.ChainId()), ethTx)
+	if err != nil {
+		log.Printf("Failed to recover sender of transaction %s: %v", tx.TxHash, err)
+		return
+	}
+	tx.From = strings.ToLower(from.Hex())
+}
+
+// MarkPriceFailed records a failed attempt to price the transaction and schedules the next one
+func (tx *Transaction) MarkPriceFailed(err error) {
+	tx.Status = StatusFailed
+	tx.PriceAttempts++
+	tx.LastPriceError = err.Error()
+	next := time.Now().Add(priceRetryBackoff(tx.PriceAttempts))
+	tx.NextPriceAttemptAt = &next
+	tx.UpdatedAt = time.Now()
+}
+
+// Swap represents a decoded Uniswap V3 Swap event emitted by a tracked pool.
+// Amounts are raw token units from the pool's perspective: positive amounts were paid into the pool.
+type Swap struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	TxHash         string    `gorm:"type:varchar(66);not null;uniqueIndex:idx_swaps_tx_log" json:"tx_hash"`
+	LogIndex       uint      `gorm:"not null;uniqueIndex:idx_swaps_tx_log" json:"log_index"`
+	BlockNumber    uint64    `gorm:"index" json:"block_number"`
+	PoolAddress    string    `gorm:"type:varchar(42);index" json:"pool_address"`
+	Sender         string    `gorm:"type:varchar(42)" json:"sender"`
+	Recipient      string    `gorm:"type:varchar(42)" json:"recipient"`
+	Amount0        *BigInt   `gorm:"type:numeric(78,0)" json:"amount0"`
+	Amount1        *BigInt   `gorm:"type:numeric(78,0)" json:"amount1"`
+	SqrtPriceX96   *BigInt   `gorm:"type:numeric(78,0)" json:"sqrt_price_x96"`
+	Liquidity      *BigInt   `gorm:"type:numeric(78,0)" json:"liquidity"`
+	Tick           int32     `json:"tick"`
+	ExecutionPrice *BigFloat `gorm:"type:numeric(38,18)" json:"execution_price"` // Decimal-adjusted, see Pool.PriceUnit
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TransactionFee is a transaction fee converted to a currency other than USDT
+type TransactionFee struct {
+	TxHash      string    `gorm:"primaryKey;type:varchar(66)" json:"tx_hash"`
+	Currency    string    `gorm:"primaryKey;type:varchar(8)" json:"currency"`
+	BlockNumber uint64    `gorm:"index" json:"block_number"`
+	Fee         *BigFloat `gorm:"type:numeric(38,18)" json:"fee"`
+	ETHPrice    *BigFloat `gorm:"type:numeric(38,18)" json:"eth_price"` // Price of 1 ETH in Currency
+	PriceSource string    `gorm:"type:varchar(32)" json:"price_source"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // SyncProgress tracks the progress of block synchronization
 type SyncProgress struct {
 	gorm.Model
+	PoolAddress           string     `gorm:"type:varchar(42);index" json:"pool_address"`
 	StartBlock            uint64     `gorm:"not null;index" json:"start_block"`
 	EndBlock              uint64     `gorm:"not null;index" json:"end_block"`
 	LastProcessedBlock    uint64     `gorm:"not null" json:"last_processed_block"`
@@ -80,11 +241,30 @@ type BlockTracker struct {
 	BlockNumber uint64 `gorm:"not null" json:"block_number"`
 }
 
+// ProcessedBlock records the hash of a block processed by live sync, used to detect chain reorganizations
+type ProcessedBlock struct {
+	Number     uint64    `gorm:"primaryKey;autoIncrement:false" json:"number"`
+	Hash       string    `gorm:"type:varchar(66);not null" json:"hash"`
+	ParentHash string    `gorm:"type:varchar(66);not null" json:"parent_hash"`
+	Timestamp  time.Time `json:"timestamp"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 // TableName specifies the table name for Transaction
 func (Transaction) TableName() string {
 	return "transactions"
 }
 
+// TableName specifies the table name for Swap
+func (Swap) TableName() string {
+	return "swaps"
+}
+
+// TableName specifies the table name for TransactionFee
+func (TransactionFee) TableName() string {
+	return "transaction_fees"
+}
+
 // TableName specifies the table name for SyncProgress
 func (SyncProgress) TableName() string {
 	return "sync_progress"
@@ -94,3 +274,18 @@ func (SyncProgress) TableName() string {
 func (BlockTracker) TableName() string {
 	return "block_tracker"
 }
+
+// TableName specifies the table name for ProcessedBlock
+func (ProcessedBlock) TableName() string {
+	return "processed_blocks"
+}
+
+// TableName specifies the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// TableName specifies the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -3,13 +3,26 @@ package syncer
 import (
 	"context"
 	"log"
+	"strings"
 	"sync"
+	"time"
+	"uniswap-fee-tracker/internal/price"
 )
 
 // processBatch handles a batch of transactions, updating their prices concurrently and saving to DB
 func (s *Service) processBatch(ctx context.Context, txs [][]*Transaction, batchSize int) []*Transaction {
 	total := len(txs)
 
+	// Load the batch's prices in bulk so the per-block lookups below are served from the cache
+	timestamps := make([]time.Time, 0, total)
+	for _, group := range txs {
+		if len(group) > 0 {
+			timestamps = append(timestamps, group[0].Timestamp)
+		}
+	}
+	prefetchPrices(ctx, s.priceProvider, price.ETHUSDT, timestamps)
+	s.prefetchFeeCurrencies(ctx, timestamps)
+
 	// Process transactions in batches
 	for i := 0; i < total; i += batchSize {
 		end := i + batchSize
@@ -26,18 +39,24 @@ func (s *Service) processBatch(ctx context.Context, txs [][]*Transaction, batchS
 			go func(txs []*Transaction) {
 				defer wg.Done()
 
+				// Record the on-chain pool price whether or not the provider price is available. A historical
+				// batch holds every transaction of its pool, so the block's swaps price it when that is the price pool.
+				wholeBlock := strings.EqualFold(txs[0].PoolAddress, s.pricePool.Address)
+				defer s.recordPoolPrice(ctx, txs, txs[0].BlockNumber, txs[0].Timestamp, wholeBlock)
+
 				// Fetch ETH/USDT price for the transaction
-				kline, err := s.binanceClient.GetPrice(ctx, "ETHUSDT", txs[0].Timestamp)
+				quote, err := s.ethPrice(ctx, txs[0].BlockNumber, txs[0].Timestamp)
 				if err != nil {
 					log.Printf("failed to get ETH price: %v", err)
 					for _, tx := range txs {
-						tx.Status = StatusFailed
+						tx.MarkPriceFailed(err)
 					}
 					return
 				}
 				for _, tx := range txs {
-					tx.UpdatePrices(kline.Close)
+					tx.ApplyQuote(quote)
 				}
+				s.convertFees(ctx, txs, txs[0].BlockNumber, txs[0].Timestamp)
 			}(tx)
 		}
 
@@ -51,3 +70,15 @@ func (s *Service) processBatch(ctx context.Context, txs [][]*Transaction, batchS
 	}
 	return results
 }
+
+// prefetchPrices lets the price provider load the pair's prices at the given times in bulk. Failures are only
+// logged: prices that were not prefetched are fetched one by one.
+func prefetchPrices(ctx context.Context, provider price.Provider, pair price.Pair, timestamps []time.Time) {
+	prefetcher, ok := provider.(price.Prefetcher)
+	if !ok || len(timestamps) == 0 {
+		return
+	}
+	if err := prefetcher.Prefetch(ctx, pair, timestamps); err != nil {
+		log.Printf("Failed to prefetch %s prices: %v", pair, err)
+	}
+}
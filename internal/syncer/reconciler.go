@@ -0,0 +1,168 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"uniswap-fee-tracker/internal/price"
+)
+
+const (
+	// reconcileBatchSize is the number of unpriced transactions loaded per query
+	reconcileBatchSize = 500
+	// priceRetryBaseDelay is the delay before the first retry of a failed price fetch
+	priceRetryBaseDelay = time.Minute
+	// priceRetryMaxDelay caps the exponential backoff between price retries
+	priceRetryMaxDelay = 6 * time.Hour
+)
+
+// UnpricedFilter selects FAILED and PENDING_PRICE transactions to reprice
+type UnpricedFilter struct {
+	FromBlock   *uint64
+	ToBlock     *uint64
+	DueBy       *time.Time // Only transactions whose next attempt is due by then; nil ignores the backoff
+	AfterBlock  uint64     // Keyset cursor: only transactions after (AfterBlock, AfterTxHash)
+	AfterTxHash string
+	Limit       int
+}
+
+// ReconcileResult summarizes a price reconciliation run
+type ReconcileResult struct {
+	Checked  int
+	Repriced int
+	Failed   int
+}
+
+// priceRetryBackoff returns the delay before the next price attempt after the given number of failures
+func priceRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := priceRetryBaseDelay
+	for i := 1; i < attempts && delay < priceRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, priceRetryMaxDelay)
+}
+
+// ReconcilePrices refetches prices for FAILED and PENDING_PRICE transactions in the block range.
+// Nil bounds leave the range open. Unless force is set, transactions still backing off are skipped.
+func (s *Service) ReconcilePrices(ctx context.Context, fromBlock, toBlock *uint64, force bool) (*ReconcileResult, error) {
+	filter := UnpricedFilter{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Limit:     reconcileBatchSize,
+	}
+	if !force {
+		now := time.Now()
+		filter.DueBy = &now
+	}
+
+	result := &ReconcileResult{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		txs, err := s.repo.GetUnpricedTransactions(filter)
+		if err != nil {
+			return result, fmt.Errorf("failed to get unpriced transactions: %w", err)
+		}
+		if len(txs) == 0 {
+			break
+		}
+
+		s.repriceTransactions(ctx, txs, result)
+		if err := s.repo.UpdateTransactionPrices(txs); err != nil {
+			return result, fmt.Errorf("failed to update transaction prices: %w", err)
+		}
+
+		last := txs[len(txs)-1]
+		filter.AfterBlock, filter.AfterTxHash = last.BlockNumber, last.TxHash
+		if len(txs) < filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// repriceTransactions fetches one ETH price per distinct timestamp and applies it, recording failures
+func (s *Service) repriceTransactions(ctx context.Context, txs []*Transaction, result *ReconcileResult) {
+	byTimestamp := make(map[int64][]*Transaction)
+	var timestamps []int64
+	for _, tx := range txs {
+		ts := tx.Timestamp.Unix()
+		if _, ok := byTimestamp[ts]; !ok {
+			timestamps = append(timestamps, ts)
+		}
+		byTimestamp[ts] = append(byTimestamp[ts], tx)
+	}
+
+	times := make([]time.Time, 0, len(timestamps))
+	for _, ts := range timestamps {
+		times = append(times, time.Unix(ts, 0))
+	}
+	prefetchPrices(ctx, s.priceProvider, price.ETHUSDT, times)
+	s.prefetchFeeCurrencies(ctx, times)
+
+	for _, ts := range timestamps {
+		group := byTimestamp[ts]
+		result.Checked += len(group)
+
+		s.recordPoolPrice(ctx, group, group[0].BlockNumber, time.Unix(ts, 0), false)
+		quote, err := s.ethPrice(ctx, group[0].BlockNumber, time.Unix(ts, 0))
+		if err != nil {
+			for _, tx := range group {
+				tx.MarkPriceFailed(err)
+			}
+			result.Failed += len(group)
+			continue
+		}
+		for _, tx := range group {
+			tx.PriceAttempts++
+			tx.ApplyQuote(quote)
+		}
+		s.convertFees(ctx, group, group[0].BlockNumber, time.Unix(ts, 0))
+		result.Repriced += len(group)
+	}
+}
+
+// runPriceReconciler periodically retries unpriced transactions whose backoff has elapsed and converts the
+// fees of priced transactions to the configured currencies they miss
+func (s *Service) runPriceReconciler(ctx context.Context) {
+	interval := s.config.ReconcileConfig.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var feeCursor MissingFeesFilter
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := s.ReconcilePrices(ctx, nil, nil, false)
+		if err != nil {
+			log.Printf("Error reconciling transaction prices: %v", err)
+			continue
+		}
+		if result.Checked > 0 {
+			log.Printf("🔁 Reconciled prices | Checked: %d | Repriced: %d | Failed: %d",
+				result.Checked, result.Repriced, result.Failed)
+		}
+
+		converted, err := s.backfillFeeCurrencies(ctx, &feeCursor)
+		if err != nil {
+			log.Printf("Error converting missing fee currencies: %v", err)
+			continue
+		}
+		if converted > 0 {
+			log.Printf("🔁 Converted the fees of %d transactions to missing currencies", converted)
+		}
+	}
+}
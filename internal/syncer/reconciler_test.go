@@ -0,0 +1,100 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/price"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPriceClient fails for the given timestamps and returns a constant price otherwise
+type flakyPriceClient struct {
+	failing map[int64]bool
+}
+
+func (c *flakyPriceClient) Name() string {
+	return "flaky"
+}
+
+func (c *flakyPriceClient) GetPrice(_ context.Context, req price.Request) (*price.Quote, error) {
+	if c.failing[req.Timestamp.Unix()] {
+		return nil, errors.New("binance unavailable")
+	}
+	return &price.Quote{Pair: req.Pair, Price: big.NewFloat(2000), Timestamp: req.Timestamp, Source: "flaky"}, nil
+}
+
+func newUnpricedTransaction(txHash string, blockNumber uint64, status TransactionStatus) *Transaction {
+	return &Transaction{
+		TxHash:      txHash,
+		BlockNumber: blockNumber,
+		Timestamp:   time.Unix(int64(1700000000+blockNumber*12), 0),
+		GasUsed:     NewBigInt(big.NewInt(21000)),
+		GasPrice:    NewBigInt(big.NewInt(1e9)),
+		Status:      status,
+	}
+}
+
+func TestPriceRetryBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), priceRetryBackoff(0))
+	assert.Equal(t, time.Minute, priceRetryBackoff(1))
+	assert.Equal(t, 2*time.Minute, priceRetryBackoff(2))
+	assert.Equal(t, 8*time.Minute, priceRetryBackoff(4))
+	assert.Equal(t, priceRetryMaxDelay, priceRetryBackoff(20))
+}
+
+func TestReconcilePrices(t *testing.T) {
+	repo := newMemoryRepository()
+	for _, tx := range []*Transaction{
+		newUnpricedTransaction("0x01", 10, StatusFailed),
+		newUnpricedTransaction("0x02", 10, StatusPendingPrice),
+		newUnpricedTransaction("0x03", 20, StatusFailed),
+		newUnpricedTransaction("0x04", 30, StatusFailed),
+	} {
+		repo.transactions[tx.TxHash] = tx
+	}
+	prices := &flakyPriceClient{failing: map[int64]bool{1700000000 + 20*12: true}}
+	service := NewService(&config.Config{}, nil, prices, nil, repo)
+
+	from, to := uint64(0), uint64(25)
+	result, err := service.ReconcilePrices(context.Background(), &from, &to, false)
+	require.NoError(t, err)
+	assert.Equal(t, &ReconcileResult{Checked: 3, Repriced: 2, Failed: 1}, result)
+
+	for _, txHash := range []string{"0x01", "0x02"} {
+		tx := repo.transactions[txHash]
+		assert.Equal(t, StatusProcessed, tx.Status)
+		assert.Equal(t, "0.042000", tx.FeeUSDT.Text('f', 6))
+		assert.Equal(t, 1, tx.PriceAttempts)
+		assert.Equal(t, "flaky", tx.PriceSource)
+	}
+
+	failed := repo.transactions["0x03"]
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, 1, failed.PriceAttempts)
+	assert.Equal(t, "binance unavailable", failed.LastPriceError)
+	require.NotNil(t, failed.NextPriceAttemptAt)
+	assert.True(t, failed.NextPriceAttemptAt.After(time.Now()))
+
+	// Outside the range
+	assert.Equal(t, StatusFailed, repo.transactions["0x04"].Status)
+
+	// The failed transaction is backing off until forced
+	prices.failing = nil
+	result, err = service.ReconcilePrices(context.Background(), &from, &to, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Checked)
+
+	result, err = service.ReconcilePrices(context.Background(), &from, &to, true)
+	require.NoError(t, err)
+	assert.Equal(t, &ReconcileResult{Checked: 1, Repriced: 1}, result)
+	assert.Equal(t, StatusProcessed, failed.Status)
+	assert.Equal(t, 2, failed.PriceAttempts)
+	assert.Empty(t, failed.LastPriceError)
+	assert.Nil(t, failed.NextPriceAttemptAt)
+}
@@ -0,0 +1,87 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+)
+
+// maxReorgDepth is the maximum number of blocks walked back when looking for a fork point
+const maxReorgDepth = 64
+
+// ErrFinalizedReorg is returned when a reorganization would remove transactions already reported as finalized
+var ErrFinalizedReorg = errors.New("reorganization below finalized transactions")
+
+// handleReorg checks whether block extends the last processed block. If it does not, the stored chain
+// is rolled back to the fork point and the canonical blocks up to block's parent are reprocessed.
+// It reports whether a reorganization was handled.
+func (s *Service) handleReorg(ctx context.Context, block *types.Block) (bool, error) {
+	number := block.NumberU64()
+	if number == 0 {
+		return false, nil
+	}
+
+	parent, err := s.repo.GetProcessedBlock(number - 1)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Nothing to compare against, e.g. the first block after startup
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get processed block %d: %w", number-1, err)
+	}
+	if parent.Hash == block.ParentHash().Hex() {
+		return false, nil
+	}
+
+	forkBlock, err := s.findForkPoint(ctx, number-1)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := s.repo.RollbackToBlock(forkBlock)
+	if err != nil {
+		if errors.Is(err, ErrFinalizedReorg) {
+			log.Printf("🚨 Chain reorganization at block %d reaches below finalized transactions, live sync cannot "+
+				"continue: raise CONFIRMATION_DEPTH or set FINALITY_BLOCK_TAG: %v", number, err)
+		}
+		return false, fmt.Errorf("failed to roll back to block %d: %w", forkBlock, err)
+	}
+	log.Printf("⚠️ Chain reorganization detected at block %d: rolled back %d blocks to %d, removed %d transactions",
+		number, number-1-forkBlock, forkBlock, removed)
+
+	// Reprocess the canonical blocks replacing the orphaned ones
+	for n := forkBlock + 1; n < number; n++ {
+		blockNum := n
+		if err := s.processBlockTransactions(ctx, &blockNum); err != nil {
+			return false, fmt.Errorf("failed to reprocess block %d: %w", blockNum, err)
+		}
+	}
+	return true, nil
+}
+
+// findForkPoint walks back from blockNumber to the highest processed block that is still canonical
+func (s *Service) findForkPoint(ctx context.Context, blockNumber uint64) (uint64, error) {
+	for n := blockNumber; n > 0 && blockNumber-n < maxReorgDepth; n-- {
+		stored, err := s.repo.GetProcessedBlock(n)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Blocks before live sync started are not tracked; treat them as final
+				return n, nil
+			}
+			return 0, fmt.Errorf("failed to get processed block %d: %w", n, err)
+		}
+
+		header, err := s.nodeClient.GetHeaderByNumber(ctx, n)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get header %d: %w", n, err)
+		}
+		if header.Hash().Hex() == stored.Hash {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("no common ancestor found within %d blocks of %d", maxReorgDepth, blockNumber)
+}
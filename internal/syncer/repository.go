@@ -2,8 +2,13 @@ package syncer
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"uniswap-fee-tracker/internal/metrics"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -11,16 +16,40 @@ type Repository interface {
 	SaveTransaction(tx *Transaction) error
 	SaveTransactions(txs []*Transaction) error
 	GetTransaction(txHash string) (*Transaction, error)
+	GetTransactionsByHashes(txHashes []string) ([]*Transaction, error)
+	ListTransactions(filter TransactionFilter) (*TransactionPage, error)
 	UpdateTransactionStatus(txHash string, status TransactionStatus) error
+	FinalizeTransactions(blockNumber uint64) (int64, error)
+	GetUnpricedTransactions(filter UnpricedFilter) ([]*Transaction, error)
+	UpdateTransactionPrices(txs []*Transaction) error
+	GetTransactionsMissingFees(filter MissingFeesFilter) ([]*Transaction, error)
+	GetAddressFeeSummary(filter AddressFeeFilter) (*AddressFeeSummary, error)
+	GetFeeStats(filter FeeStatsFilter) ([]FeeStatsBucket, error)
+
+	// Webhook operations
+	CreateWebhookSubscription(sub *WebhookSubscription) error
+	ListWebhookSubscriptions() ([]WebhookSubscription, error)
+	DeleteWebhookSubscription(id uint) error
+	GetDueWebhookDeliveries(dueBy time.Time, limit int) ([]*WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *WebhookDelivery) error
+	ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	RetryWebhookDelivery(subscriptionID, id uint) error
 
 	// Sync progress operations
 	CreateSyncProgress(sp *SyncProgress) error
 	UpdateSyncProgress(sp *SyncProgress) error
+	GetSyncProgress(id uint) (*SyncProgress, error)
 	GetIncompleteSyncProgress() ([]SyncProgress, error)
+	ListSyncProgress() ([]SyncProgress, error)
+	HasSyncProgress(poolAddress string) (bool, error)
+	AssignLegacyPool(poolAddress string) error
 
 	// Block tracking operations
 	UpdateLastTrackedBlock(blockNumber uint64) error
 	GetLastTrackedBlock() (uint64, error)
+	SaveProcessedBlock(block *ProcessedBlock) error
+	GetProcessedBlock(number uint64) (*ProcessedBlock, error)
+	RollbackToBlock(blockNumber uint64) (int64, error)
 
 	// Database operations
 	AutoMigrate() error
@@ -35,19 +64,202 @@ func NewRepository(db *gorm.DB) Repository {
 }
 
 func (r *repository) SaveTransaction(tx *Transaction) error {
-	return r.db.Save(tx).Error
+	if err := r.db.Save(tx).Error; err != nil {
+		return err
+	}
+	metrics.TransactionsSaved.WithLabelValues(string(tx.Status)).Inc()
+	return nil
 }
 
+// SaveTransactions inserts new transactions. Transactions that are already stored, e.g. multi-hop
+// transactions found by the sync of each tracked pool they touched, are left untouched along with their
+// swaps and fees. The webhook deliveries of the transactions are added to the outbox in the same
+// database transaction.
 func (r *repository) SaveTransactions(txs []*Transaction) error {
-	return r.db.CreateInBatches(txs, 100).Error
+	if len(txs) == 0 {
+		return nil
+	}
+	// Transactions are inserted by price status to count the inserted ones of each status
+	var statuses []TransactionStatus
+	byStatus := make(map[TransactionStatus][]*Transaction)
+	for _, tx := range txs {
+		if _, ok := byStatus[tx.Status]; !ok {
+			statuses = append(statuses, tx.Status)
+		}
+		byStatus[tx.Status] = append(byStatus[tx.Status], tx)
+	}
+	inserted := make(map[TransactionStatus]int64, len(statuses))
+
+	err := r.db.Transaction(func(db *gorm.DB) error {
+		// Associations are inserted separately: GORM would upsert them by their own primary key, which
+		// conflicts with the swaps and fees of a transaction saved again
+		for _, status := range statuses {
+			result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(byStatus[status], 100)
+			if result.Error != nil {
+				return result.Error
+			}
+			inserted[status] = result.RowsAffected
+		}
+		if err := insertAssociations(db, txs); err != nil {
+			return err
+		}
+		return queueWebhookDeliveries(db, txs)
+	})
+	if err != nil {
+		return err
+	}
+	for status, n := range inserted {
+		metrics.TransactionsSaved.WithLabelValues(string(status)).Add(float64(n))
+	}
+	return nil
+}
+
+// queueWebhookDeliveries adds the deliveries of the transactions to the subscriptions they match to the
+// outbox. Transactions already queued to a subscription keep their original delivery.
+func queueWebhookDeliveries(db *gorm.DB, txs []*Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	var subs []WebhookSubscription
+	if err := db.Find(&subs).Error; err != nil {
+		return err
+	}
+	deliveries, err := newWebhookDeliveries(subs, txs)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(deliveries, 100).Error
+}
+
+// insertAssociations inserts the swaps and fees of the transactions, skipping those already stored
+func insertAssociations(db *gorm.DB, txs []*Transaction) error {
+	var swaps []*Swap
+	var fees []*TransactionFee
+	for _, tx := range txs {
+		for i := range tx.Swaps {
+			tx.Swaps[i].TxHash = tx.TxHash
+			swaps = append(swaps, &tx.Swaps[i])
+		}
+		for i := range tx.Fees {
+			tx.Fees[i].TxHash = tx.TxHash
+			fees = append(fees, &tx.Fees[i])
+		}
+	}
+
+	if len(swaps) > 0 {
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+			DoNothing: true,
+		}).CreateInBatches(swaps, 100).Error
+		if err != nil {
+			return err
+		}
+	}
+	if len(fees) > 0 {
+		return db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(fees, 100).Error
+	}
+	return nil
 }
 
 func (r *repository) GetTransaction(txHash string) (*Transaction, error) {
 	var tx Transaction
-	err := r.db.Where("tx_hash = ?", txHash).First(&tx).Error
+	err := r.db.Preload("Swaps", orderSwaps).Preload("Fees").Where("tx_hash = ?", txHash).First(&tx).Error
 	return &tx, err
 }
 
+// GetTransactionsByHashes returns the stored transactions among the given hashes in a single query
+func (r *repository) GetTransactionsByHashes(txHashes []string) ([]*Transaction, error) {
+	var txs []*Transaction
+	if len(txHashes) == 0 {
+		return txs, nil
+	}
+	err := r.db.Preload("Swaps", orderSwaps).Preload("Fees").Where("tx_hash IN ?", txHashes).Find(&txs).Error
+	return txs, err
+}
+
+// ListTransactions returns a page of transactions matching the filter using keyset pagination
+func (r *repository) ListTransactions(filter TransactionFilter) (*TransactionPage, error) {
+	query := r.db.Model(&Transaction{})
+
+	if filter.FromBlock != nil {
+		query = query.Where("block_number >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		query = query.Where("block_number <= ?", *filter.ToBlock)
+	}
+	if filter.FromTime != nil {
+		query = query.Where("timestamp >= ?", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		query = query.Where("timestamp <= ?", *filter.ToTime)
+	}
+	if filter.MinFeeUSDT != nil {
+		query = query.Where("fee_usdt >= ?::numeric", filter.MinFeeUSDT.Text('f', 6))
+	}
+	if filter.MaxFeeUSDT != nil {
+		query = query.Where("fee_usdt <= ?::numeric", filter.MaxFeeUSDT.Text('f', 6))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Finality != "" {
+		query = query.Where("finality = ?", filter.Finality)
+	}
+	if filter.PoolAddress != "" {
+		// Multi-hop transactions are attributed to one pool but carry the swaps of every pool they touched
+		query = query.Where("pool_address = ? OR tx_hash IN (SELECT tx_hash FROM swaps WHERE pool_address = ?)",
+			filter.PoolAddress, filter.PoolAddress)
+	}
+	if filter.FromAddress != "" {
+		query = query.Where(`"from" = ?`, filter.FromAddress)
+	}
+	if filter.ToAddress != "" {
+		query = query.Where(`"to" = ?`, filter.ToAddress)
+	}
+	if filter.Reverted != nil {
+		query = query.Where("reverted = ?", *filter.Reverted)
+	}
+
+	column := filter.sortColumn()
+	direction := "ASC"
+	comparator := ">"
+	if filter.Order == SortDesc {
+		direction = "DESC"
+		comparator = "<"
+	}
+
+	if filter.Cursor != "" {
+		value, txHash, err := filter.decodeCursor()
+		if err != nil {
+			return nil, err
+		}
+		placeholder := "?"
+		if filter.SortBy == SortByFeeUSDT {
+			placeholder = "?::numeric"
+		}
+		query = query.Where(fmt.Sprintf("(%s, tx_hash) %s (%s, ?)", column, comparator, placeholder), value, txHash)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	var txs []*Transaction
+	err := query.
+		Preload("Swaps", orderSwaps).
+		Preload("Fees").
+		Order(fmt.Sprintf("%s %s, tx_hash %s", column, direction, direction)).
+		Limit(filter.Limit + 1).
+		Find(&txs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TransactionPage{Transactions: txs}
+	if len(txs) > filter.Limit {
+		page.Transactions = txs[:filter.Limit]
+		page.NextCursor = filter.encodeCursor(page.Transactions[filter.Limit-1])
+	}
+	return page, nil
+}
+
 func (r *repository) UpdateTransactionStatus(txHash string, status TransactionStatus) error {
 	return r.db.Model(&Transaction{}).
 		Where("tx_hash = ?", txHash).
@@ -55,6 +267,258 @@ func (r *repository) UpdateTransactionStatus(txHash string, status TransactionSt
 		Error
 }
 
+// FinalizeTransactions marks unconfirmed priced transactions at or below blockNumber as finalized,
+// returning the number of promoted transactions. Unpriced ones are promoted once the reconciler prices them.
+func (r *repository) FinalizeTransactions(blockNumber uint64) (int64, error) {
+	result := r.db.Model(&Transaction{}).
+		Where("finality <> ? AND status = ? AND block_number <= ?", FinalityFinalized, StatusProcessed, blockNumber).
+		Update("finality", FinalityFinalized)
+	return result.RowsAffected, result.Error
+}
+
+// GetUnpricedTransactions returns FAILED and PENDING_PRICE transactions matching the filter,
+// ordered by block number and hash
+func (r *repository) GetUnpricedTransactions(filter UnpricedFilter) ([]*Transaction, error) {
+	query := r.db.Where("status IN ?", []TransactionStatus{StatusFailed, StatusPendingPrice})
+	if filter.FromBlock != nil {
+		query = query.Where("block_number >= ?", *filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		query = query.Where("block_number <= ?", *filter.ToBlock)
+	}
+	if filter.DueBy != nil {
+		query = query.Where("next_price_attempt_at IS NULL OR next_price_attempt_at <= ?", *filter.DueBy)
+	}
+	if filter.AfterTxHash != "" {
+		query = query.Where("(block_number, tx_hash) > (?, ?)", filter.AfterBlock, filter.AfterTxHash)
+	}
+
+	var txs []*Transaction
+	err := query.Order("block_number, tx_hash").Limit(filter.Limit).Find(&txs).Error
+	return txs, err
+}
+
+// UpdateTransactionPrices stores the prices, status and retry bookkeeping of the given transactions,
+// along with their converted fees. Transactions priced for the first time are added to the webhook outbox,
+// as subscriptions filtering on the USDT fee could not match them while they were unpriced.
+func (r *repository) UpdateTransactionPrices(txs []*Transaction) error {
+	return r.db.Transaction(func(db *gorm.DB) error {
+		var priced []string
+		for _, tx := range txs {
+			if tx.Status == StatusProcessed {
+				priced = append(priced, tx.TxHash)
+			}
+		}
+		// The priced transactions whose stored row is still unpriced
+		newlyPriced := make(map[string]bool)
+		if len(priced) > 0 {
+			var hashes []string
+			err := db.Model(&Transaction{}).Where("tx_hash IN ? AND status <> ?", priced, StatusProcessed).
+				Pluck("tx_hash", &hashes).Error
+			if err != nil {
+				return err
+			}
+			for _, hash := range hashes {
+				newlyPriced[hash] = true
+			}
+		}
+
+		var notify []*Transaction
+		for _, tx := range txs {
+			if newlyPriced[tx.TxHash] {
+				notify = append(notify, tx)
+			}
+			err := db.Model(tx).
+				Select("fee_eth", "fee_usdt", "eth_price", "price_source", "price_interval", "pool_eth_price", "status", "price_attempts",
+					"last_price_error", "next_price_attempt_at", "updated_at", "burned_fee_eth", "burned_fee_usdt", "tip_fee_eth", "tip_fee_usdt").
+				Updates(tx).Error
+			if err != nil {
+				return err
+			}
+			if len(tx.Fees) == 0 {
+				continue
+			}
+			err = db.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "currency"}},
+				DoUpdates: clause.AssignmentColumns([]string{"fee", "eth_price", "price_source", "updated_at"}),
+			}).Create(&tx.Fees).Error
+			if err != nil {
+				return err
+			}
+		}
+		return queueWebhookDeliveries(db, notify)
+	})
+}
+
+// GetTransactionsMissingFees returns priced transactions, with their fees, that have no conversion for at
+// least one of the filter's currencies
+func (r *repository) GetTransactionsMissingFees(filter MissingFeesFilter) ([]*Transaction, error) {
+	converted := r.db.Model(&TransactionFee{}).Select("COUNT(*)").
+		Where("transaction_fees.tx_hash = transactions.tx_hash AND currency IN ?", filter.Currencies)
+	query := r.db.Preload("Fees").
+		Where("status = ? AND fee_eth IS NOT NULL", StatusProcessed).
+		Where("(?) < ?", converted, len(filter.Currencies))
+	if filter.AfterTxHash != "" {
+		query = query.Where("(block_number, tx_hash) > (?, ?)", filter.AfterBlock, filter.AfterTxHash)
+	}
+
+	var txs []*Transaction
+	err := query.Order("block_number, tx_hash").Limit(filter.Limit).Find(&txs).Error
+	return txs, err
+}
+
+// feeETHColumn is the ETH fee of a transaction. Unpriced rows stored before the ETH fee was set when
+// transactions are built have no fee_eth, so it is computed from their gas.
+const feeETHColumn = "COALESCE(fee_eth, gas_used * gas_price / 1e18)"
+
+// GetAddressFeeSummary aggregates the fees of the transactions sent by the filter's address
+func (r *repository) GetAddressFeeSummary(filter AddressFeeFilter) (*AddressFeeSummary, error) {
+	query := r.db.Model(&Transaction{}).Where(`"from" = ?`, filter.Address)
+	if filter.FromTime != nil {
+		query = query.Where("timestamp >= ?", *filter.FromTime)
+	}
+	if filter.ToTime != nil {
+		query = query.Where("timestamp <= ?", *filter.ToTime)
+	}
+
+	var summary AddressFeeSummary
+	err := query.Select(fmt.Sprintf(`COUNT(*) AS transaction_count,
+		COUNT(fee_usdt) AS priced_count,
+		COUNT(*) FILTER (WHERE reverted) AS reverted_count,
+		COALESCE(SUM(%[1]s), 0) AS total_fee_eth,
+		COALESCE(SUM(fee_usdt), 0) AS total_fee_usdt,
+		AVG(%[1]s) AS average_fee_eth,
+		AVG(fee_usdt) AS average_fee_usdt,
+		MIN(timestamp) AS first_seen,
+		MAX(timestamp) AS last_seen`, feeETHColumn)).
+		Scan(&summary).Error
+	return &summary, err
+}
+
+// GetFeeStats aggregates the fees of the transactions in the filter's time range into buckets of its interval,
+// aligned to the Unix epoch. Only buckets containing transactions are returned, in chronological order.
+func (r *repository) GetFeeStats(filter FeeStatsFilter) ([]FeeStatsBucket, error) {
+	bucket := fmt.Sprintf("date_bin('%d seconds'::interval, timestamp, TIMESTAMPTZ '1970-01-01 00:00:00+00')",
+		int64(filter.Interval/time.Second))
+	percentile := func(fraction, column string) string {
+		return fmt.Sprintf("(percentile_cont(%s) WITHIN GROUP (ORDER BY %s))::numeric", fraction, column)
+	}
+
+	query := r.db.Model(&Transaction{}).
+		Where("timestamp >= ? AND timestamp < ?", filter.FromTime, filter.ToTime)
+	if filter.PoolAddress != "" {
+		// Multi-hop transactions are attributed to one pool but carry the swaps of every pool they touched
+		query = query.Where("pool_address = ? OR tx_hash IN (SELECT tx_hash FROM swaps WHERE pool_address = ?)",
+			filter.PoolAddress, filter.PoolAddress)
+	}
+
+	var buckets []FeeStatsBucket
+	err := query.Select(strings.Join([]string{
+		bucket + " AS bucket_start",
+		"COUNT(*) AS count",
+		"COUNT(fee_usdt) AS priced_count",
+		"SUM(" + feeETHColumn + ") AS sum_fee_eth",
+		"AVG(" + feeETHColumn + ") AS mean_fee_eth",
+		percentile("0.5", feeETHColumn) + " AS median_fee_eth",
+		percentile("0.9", feeETHColumn) + " AS p90_fee_eth",
+		percentile("0.95", feeETHColumn) + " AS p95_fee_eth",
+		percentile("0.99", feeETHColumn) + " AS p99_fee_eth",
+		"SUM(fee_usdt) AS sum_fee_usdt",
+		"AVG(fee_usdt) AS mean_fee_usdt",
+		percentile("0.5", "fee_usdt") + " AS median_fee_usdt",
+		percentile("0.9", "fee_usdt") + " AS p90_fee_usdt",
+		percentile("0.95", "fee_usdt") + " AS p95_fee_usdt",
+		percentile("0.99", "fee_usdt") + " AS p99_fee_usdt",
+		"AVG(gas_price) AS average_gas_price",
+	}, ", ")).
+		Group("bucket_start").
+		Order("bucket_start").
+		Scan(&buckets).Error
+	return buckets, err
+}
+
+// CreateWebhookSubscription stores a new webhook subscription
+func (r *repository) CreateWebhookSubscription(sub *WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+// ListWebhookSubscriptions returns every webhook subscription ordered by ID
+func (r *repository) ListWebhookSubscriptions() ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	err := r.db.Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// DeleteWebhookSubscription removes a webhook subscription and its deliveries
+func (r *repository) DeleteWebhookSubscription(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&WebhookSubscription{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetDueWebhookDeliveries returns pending deliveries whose next attempt is due by dueBy, oldest first,
+// with their subscription
+func (r *repository) GetDueWebhookDeliveries(dueBy time.Time, limit int) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	err := r.db.Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", WebhookStatusPending, dueBy).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// UpdateWebhookDelivery stores the outcome of a delivery attempt
+func (r *repository) UpdateWebhookDelivery(delivery *WebhookDelivery) error {
+	return r.db.Model(delivery).
+		Select("status", "attempts", "last_error", "next_attempt_at", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
+
+// ListWebhookDeliveries returns the most recent deliveries matching the filter
+func (r *repository) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	query := r.db.Model(&WebhookDelivery{})
+	if filter.SubscriptionID != 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	var deliveries []WebhookDelivery
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// RetryWebhookDelivery requeues a dead-lettered delivery of the subscription, returning gorm.ErrRecordNotFound
+// when there is none
+func (r *repository) RetryWebhookDelivery(subscriptionID, id uint) error {
+	result := r.db.Model(&WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ? AND status = ?", id, subscriptionID, WebhookStatusDead).
+		Updates(map[string]interface{}{
+			"status":          WebhookStatusPending,
+			"attempts":        0,
+			"last_error":      "",
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *repository) CreateSyncProgress(sp *SyncProgress) error {
 	return r.db.Create(sp).Error
 }
@@ -63,9 +527,18 @@ func (r *repository) UpdateSyncProgress(sp *SyncProgress) error {
 	return r.db.Save(sp).Error
 }
 
+// GetSyncProgress returns a sync progress record by its ID
+func (r *repository) GetSyncProgress(id uint) (*SyncProgress, error) {
+	var progress SyncProgress
+	if err := r.db.First(&progress, id).Error; err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
 func (r *repository) GetIncompleteSyncProgress() ([]SyncProgress, error) {
 	var syncProgresses []SyncProgress
-	err := r.db.Where("status != ?", SyncStatusCompleted).Order("created_at DESC").Find(&syncProgresses).Error
+	err := r.db.Where("status NOT IN ?", []SyncStatus{SyncStatusCompleted, SyncStatusCancelled}).Order("created_at DESC").Find(&syncProgresses).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -75,6 +548,34 @@ func (r *repository) GetIncompleteSyncProgress() ([]SyncProgress, error) {
 	return syncProgresses, nil
 }
 
+// ListSyncProgress returns every sync progress record ordered by pool and start block
+func (r *repository) ListSyncProgress() ([]SyncProgress, error) {
+	var syncProgresses []SyncProgress
+	err := r.db.Order("pool_address, start_block").Find(&syncProgresses).Error
+	return syncProgresses, err
+}
+
+// HasSyncProgress reports whether a historical sync has ever been started for the pool
+func (r *repository) HasSyncProgress(poolAddress string) (bool, error) {
+	var count int64
+	err := r.db.Model(&SyncProgress{}).Where("pool_address = ?", poolAddress).Count(&count).Error
+	return count > 0, err
+}
+
+// AssignLegacyPool attributes transactions and sync progress stored without a pool to the given pool
+func (r *repository) AssignLegacyPool(poolAddress string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Transaction{}).
+			Where("pool_address IS NULL OR pool_address = ''").
+			Update("pool_address", poolAddress).Error; err != nil {
+			return err
+		}
+		return tx.Model(&SyncProgress{}).
+			Where("pool_address IS NULL OR pool_address = ''").
+			Update("pool_address", poolAddress).Error
+	})
+}
+
 // UpdateLastTrackedBlock updates the last processed block number
 func (r *repository) UpdateLastTrackedBlock(blockNumber uint64) error {
 	tracker := BlockTracker{Model: gorm.Model{ID: 1}, BlockNumber: blockNumber}
@@ -87,7 +588,65 @@ func (r *repository) GetLastTrackedBlock() (uint64, error) {
 	return tracker.BlockNumber, r.db.First(&tracker).Error
 }
 
+// SaveProcessedBlock stores or replaces the hash of a processed block
+func (r *repository) SaveProcessedBlock(block *ProcessedBlock) error {
+	return r.db.Save(block).Error
+}
+
+// GetProcessedBlock returns the processed block with the given number
+func (r *repository) GetProcessedBlock(number uint64) (*ProcessedBlock, error) {
+	var block ProcessedBlock
+	err := r.db.Where("number = ?", number).First(&block).Error
+	return &block, err
+}
+
+// RollbackToBlock removes transactions and processed blocks above blockNumber and resets the
+// block tracker to it, returning the number of removed transactions. It fails with ErrFinalizedReorg,
+// removing nothing, when a transaction above blockNumber is finalized.
+func (r *repository) RollbackToBlock(blockNumber uint64) (int64, error) {
+	var removed int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var finalized int64
+		err := tx.Model(&Transaction{}).Where("block_number > ? AND finality = ?", blockNumber, FinalityFinalized).
+			Count(&finalized).Error
+		if err != nil {
+			return err
+		}
+		if finalized > 0 {
+			return fmt.Errorf("%w: %d finalized transactions after block %d", ErrFinalizedReorg, finalized, blockNumber)
+		}
+
+		if err := tx.Where("block_number > ?", blockNumber).Delete(&Swap{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("block_number > ?", blockNumber).Delete(&TransactionFee{}).Error; err != nil {
+			return err
+		}
+		// Notifications already sent cannot be recalled, but pending ones are dropped with their transactions
+		if err := tx.Where("block_number > ? AND status = ?", blockNumber, WebhookStatusPending).
+			Delete(&WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("block_number > ?", blockNumber).Delete(&Transaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected
+		if err := tx.Where("number > ?", blockNumber).Delete(&ProcessedBlock{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(&BlockTracker{Model: gorm.Model{ID: 1}, BlockNumber: blockNumber}).Error
+	})
+	return removed, err
+}
+
 // AutoMigrate creates or updates database tables
 func (r *repository) AutoMigrate() error {
-	return r.db.AutoMigrate(&Transaction{}, &SyncProgress{}, &BlockTracker{})
+	return r.db.AutoMigrate(&Transaction{}, &Swap{}, &TransactionFee{}, &SyncProgress{}, &BlockTracker{}, &ProcessedBlock{},
+		&WebhookSubscription{}, &WebhookDelivery{})
+}
+
+// orderSwaps preloads swaps in the order they were emitted
+func orderSwaps(db *gorm.DB) *gorm.DB {
+	return db.Order("log_index")
 }
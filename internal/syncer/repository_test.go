@@ -0,0 +1,303 @@
+package syncer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepository returns a repository on the Postgres database of TEST_DB_URI, skipping the test when it is
+// not set. Each test runs in a database transaction rolled back once it ends.
+func newTestRepository(t *testing.T) *repository {
+	t.Helper()
+	dsn := os.Getenv("TEST_DB_URI")
+	if dsn == "" {
+		t.Skip("TEST_DB_URI is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, NewRepository(db).AutoMigrate())
+
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	t.Cleanup(func() { tx.Rollback() })
+	return &repository{db: tx}
+}
+
+// dryRunConn is a connection pool for dry runs, which build statements without sending them
+type dryRunConn struct{}
+
+var errDryRun = errors.New("dry run")
+
+func (dryRunConn) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, errDryRun }
+func (dryRunConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errDryRun
+}
+func (dryRunConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+func (dryRunConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+func (c dryRunConn) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{c}, nil
+}
+
+type dryRunTx struct{ dryRunConn }
+
+func (*dryRunTx) Commit() error   { return nil }
+func (*dryRunTx) Rollback() error { return nil }
+
+// statementRecorder is a GORM logger keeping the SQL of every statement
+type statementRecorder struct {
+	statements []string
+}
+
+func (r *statementRecorder) LogMode(logger.LogLevel) logger.Interface      { return r }
+func (r *statementRecorder) Info(context.Context, string, ...interface{})  {}
+func (r *statementRecorder) Warn(context.Context, string, ...interface{})  {}
+func (r *statementRecorder) Error(context.Context, string, ...interface{}) {}
+func (r *statementRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// newDryRunRepository returns a Postgres repository that records the SQL it would run
+func newDryRunRepository(t *testing.T) (*repository, *statementRecorder) {
+	t.Helper()
+	recorder := &statementRecorder{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunConn{}}), &gorm.Config{DryRun: true, Logger: recorder})
+	require.NoError(t, err)
+	return &repository{db: db}, recorder
+}
+
+// multiHopTransaction returns a transaction swapping through two pools
+func multiHopTransaction() *Transaction {
+	const txHash = "0x6f3c0a1a1d2f0f6a7c46c5f5bb5d2c7f1e9a0b8c4d3e2f1a0b9c8d7e6f5a4b3c"
+	return &Transaction{
+		TxHash:      txHash,
+		BlockNumber: 100,
+		Timestamp:   time.Unix(1700000000, 0).UTC(),
+		PoolAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+		GasUsed:     NewBigInt(big.NewInt(150000)),
+		GasPrice:    NewBigInt(big.NewInt(20e9)),
+		Status:      StatusPendingPrice,
+		Swaps: []Swap{
+			{TxHash: txHash, LogIndex: 3, BlockNumber: 100, PoolAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"},
+			{TxHash: txHash, LogIndex: 7, BlockNumber: 100, PoolAddress: "0x11b815efb8f581194ae79006d24e0d814b7697f6"},
+		},
+		Fees: []TransactionFee{{TxHash: txHash, Currency: "EUR", BlockNumber: 100}},
+	}
+}
+
+func TestSaveTransactionsSkipsStoredSwaps(t *testing.T) {
+	repo, recorder := newDryRunRepository(t)
+	require.NoError(t, repo.SaveTransactions([]*Transaction{multiHopTransaction()}))
+
+	var swapInserts int
+	for _, statement := range recorder.statements {
+		assert.NotContains(t, statement, `ON CONFLICT ("id")`, "swaps must not be upserted by their serial ID")
+		if strings.HasPrefix(statement, `INSERT INTO "swaps"`) {
+			swapInserts++
+			assert.Contains(t, statement, `ON CONFLICT ("tx_hash","log_index") DO NOTHING`)
+		}
+		if strings.HasPrefix(statement, `INSERT INTO "transaction_fees"`) {
+			assert.Contains(t, statement, `ON CONFLICT DO NOTHING`)
+		}
+	}
+	assert.Equal(t, 1, swapInserts)
+}
+
+func TestSaveTransactionsTwice(t *testing.T) {
+	repo := newTestRepository(t)
+
+	saved := metrics.TransactionsSaved.WithLabelValues(string(StatusPendingPrice))
+	before := testutil.ToFloat64(saved)
+
+	// A multi-hop transaction is found by the sync of each pool it touched
+	require.NoError(t, repo.SaveTransactions([]*Transaction{multiHopTransaction()}))
+	require.NoError(t, repo.SaveTransactions([]*Transaction{multiHopTransaction()}))
+	assert.Equal(t, before+1, testutil.ToFloat64(saved), "only inserted transactions are counted")
+
+	stored, err := repo.GetTransaction(multiHopTransaction().TxHash)
+	require.NoError(t, err)
+	require.Len(t, stored.Swaps, 2)
+	assert.Equal(t, uint(3), stored.Swaps[0].LogIndex)
+	assert.Equal(t, uint(7), stored.Swaps[1].LogIndex)
+	assert.Len(t, stored.Fees, 1)
+}
+
+func TestGetFeeStatsQuery(t *testing.T) {
+	repo, recorder := newDryRunRepository(t)
+	from := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	_, err := repo.GetFeeStats(FeeStatsFilter{
+		Interval:    time.Hour,
+		FromTime:    from,
+		ToTime:      from.Add(3 * time.Hour),
+		PoolAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+	})
+	assert.ErrorIs(t, err, gorm.ErrDryRunModeUnsupported)
+	require.Len(t, recorder.statements, 1)
+	statement := recorder.statements[0]
+	for _, expected := range []string{
+		`date_bin('3600 seconds'::interval, timestamp, TIMESTAMPTZ '1970-01-01 00:00:00+00') AS bucket_start`,
+		`(percentile_cont(0.9) WITHIN GROUP (ORDER BY fee_usdt))::numeric AS p90_fee_usdt`,
+		`SUM(COALESCE(fee_eth, gas_used * gas_price / 1e18)) AS sum_fee_eth`,
+		`(percentile_cont(0.5) WITHIN GROUP (ORDER BY COALESCE(fee_eth, gas_used * gas_price / 1e18)))::numeric AS median_fee_eth`,
+		`WHERE (timestamp >= '2024-02-13 10:00:00' AND timestamp < '2024-02-13 13:00:00')`,
+		`tx_hash IN (SELECT tx_hash FROM swaps WHERE pool_address = '0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640')`,
+		`GROUP BY "bucket_start" ORDER BY bucket_start`,
+	} {
+		assert.Contains(t, statement, expected)
+	}
+}
+
+func TestGetFeeStats(t *testing.T) {
+	repo := newTestRepository(t)
+	base := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	var txs []*Transaction
+	seed := func(hash string, offset time.Duration, gasPrice int64, ethPrice float64) {
+		tx := &Transaction{
+			TxHash:    hash,
+			Timestamp: base.Add(offset),
+			GasUsed:   NewBigInt(big.NewInt(100000)),
+			GasPrice:  NewBigInt(big.NewInt(gasPrice)),
+		}
+		switch {
+		case ethPrice > 0:
+			tx.UpdatePrices(big.NewFloat(ethPrice))
+		case ethPrice == 0:
+			tx.SetFeeETH()
+			tx.MarkPriceFailed(errors.New("price unavailable"))
+		default:
+			// Stored before the ETH fee was set when transactions are built
+			tx.MarkPriceFailed(errors.New("price unavailable"))
+		}
+		txs = append(txs, tx)
+	}
+	// 10:00-11:00: fees of 2, 4, 6 and 8 USDT
+	seed("0x01", 5*time.Minute, 10e9, 2000)
+	seed("0x02", 10*time.Minute, 20e9, 2000)
+	seed("0x03", 20*time.Minute, 30e9, 2000)
+	seed("0x04", 59*time.Minute, 40e9, 2000)
+	// 12:00-13:00: two unpriced transactions
+	seed("0x05", 2*time.Hour+30*time.Minute, 10e9, 0)
+	seed("0x07", 2*time.Hour+45*time.Minute, 20e9, -1)
+	// Outside the range
+	seed("0x06", 3*time.Hour, 10e9, 2000)
+	require.NoError(t, repo.SaveTransactions(txs))
+
+	buckets, err := repo.GetFeeStats(FeeStatsFilter{Interval: time.Hour, FromTime: base, ToTime: base.Add(3 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+
+	hour := buckets[0]
+	assert.True(t, base.Equal(hour.Start))
+	assert.Equal(t, int64(4), hour.Count)
+	assert.Equal(t, int64(4), hour.PricedCount)
+	assert.Equal(t, "20.000000", hour.SumFeeUSDT.Text('f', 6))
+	assert.Equal(t, "5.000000", hour.MeanFeeUSDT.Text('f', 6))
+	assert.Equal(t, "5.000000", hour.MedianFeeUSDT.Text('f', 6))
+	assert.Equal(t, "7.400000", hour.P90FeeUSDT.Text('f', 6))
+	assert.Equal(t, "0.002500000000000000", hour.MeanFeeETH.Text('f', 18))
+	assert.Equal(t, "25000000000", hour.AverageGasPrice.Text('f', 0))
+
+	// Unpriced transactions only count towards the ETH figures
+	assert.True(t, base.Add(2*time.Hour).Equal(buckets[1].Start))
+	assert.Equal(t, int64(2), buckets[1].Count)
+	assert.Zero(t, buckets[1].PricedCount)
+	assert.Equal(t, "0.003000", buckets[1].SumFeeETH.Text('f', 6))
+	assert.Equal(t, "0.001500", buckets[1].MeanFeeETH.Text('f', 6))
+	assert.Nil(t, buckets[1].MedianFeeUSDT)
+}
+
+func TestUpdateTransactionPricesQueuesWebhooks(t *testing.T) {
+	repo := newTestRepository(t)
+	sub := &WebhookSubscription{URL: "http://example.com/hook", Secret: "secret", MinFeeUSDT: NewBigFloat(big.NewFloat(1))}
+	require.NoError(t, repo.CreateWebhookSubscription(sub))
+	countDeliveries := func() int64 {
+		var count int64
+		require.NoError(t, repo.db.Model(&WebhookDelivery{}).Count(&count).Error)
+		return count
+	}
+
+	// A transaction stored unpriced during a price outage does not match the fee filter
+	tx := multiHopTransaction()
+	tx.Timestamp = time.Now()
+	tx.MarkPriceFailed(errors.New("price unavailable"))
+	require.NoError(t, repo.SaveTransactions([]*Transaction{tx}))
+	assert.Zero(t, countDeliveries())
+
+	// Its delivery is queued once it is priced, and only once
+	tx.UpdatePrices(big.NewFloat(2000))
+	require.NoError(t, repo.UpdateTransactionPrices([]*Transaction{tx}))
+	assert.Equal(t, int64(1), countDeliveries())
+	require.NoError(t, repo.UpdateTransactionPrices([]*Transaction{tx}))
+	assert.Equal(t, int64(1), countDeliveries())
+}
+
+func TestGetAddressFeeSummary(t *testing.T) {
+	repo := newTestRepository(t)
+	sender := "0x1111111111111111111111111111111111111111"
+	priced := newUnpricedTransaction("0x01", 10, StatusPendingPrice)
+	priced.From = sender
+	priced.UpdatePrices(big.NewFloat(2000))
+	unpriced := newUnpricedTransaction("0x02", 11, StatusPendingPrice)
+	unpriced.From = sender
+	unpriced.SetFeeETH()
+	unpriced.MarkPriceFailed(errors.New("price unavailable"))
+	// Stored unpriced before the ETH fee was set when transactions are built
+	legacy := newUnpricedTransaction("0x03", 12, StatusFailed)
+	legacy.From = sender
+	require.NoError(t, repo.SaveTransactions([]*Transaction{priced, unpriced, legacy}))
+
+	summary, err := repo.GetAddressFeeSummary(AddressFeeFilter{Address: sender})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), summary.TransactionCount)
+	assert.Equal(t, int64(1), summary.PricedCount)
+	// Unpriced transactions count towards the ETH figures
+	assert.Equal(t, "0.000063000000000000", summary.TotalFeeETH.Text('f', 18))
+	assert.Equal(t, "0.000021000000000000", summary.AverageFeeETH.Text('f', 18))
+	assert.Equal(t, "0.042000", summary.TotalFeeUSDT.Text('f', 6))
+}
+
+func TestGetAddressFeeSummaryQuery(t *testing.T) {
+	repo, recorder := newDryRunRepository(t)
+	_, err := repo.GetAddressFeeSummary(AddressFeeFilter{Address: "0x1111111111111111111111111111111111111111"})
+	assert.ErrorIs(t, err, gorm.ErrDryRunModeUnsupported)
+	require.Len(t, recorder.statements, 1)
+	// Unpriced rows stored without an ETH fee count towards the ETH figures
+	assert.Contains(t, recorder.statements[0], `COALESCE(SUM(COALESCE(fee_eth, gas_used * gas_price / 1e18)), 0) AS total_fee_eth`)
+	assert.Contains(t, recorder.statements[0], `AVG(COALESCE(fee_eth, gas_used * gas_price / 1e18)) AS average_fee_eth`)
+}
+
+func TestGetTransactionsMissingFeesQuery(t *testing.T) {
+	repo, recorder := newDryRunRepository(t)
+	_, err := repo.GetTransactionsMissingFees(MissingFeesFilter{
+		Currencies:  []string{"EUR", "BTC"},
+		AfterBlock:  100,
+		AfterTxHash: "0xabc",
+		Limit:       500,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, recorder.statements)
+	statement := recorder.statements[0]
+	for _, expected := range []string{
+		`WHERE (status = 'PROCESSED' AND fee_eth IS NOT NULL)`,
+		`(SELECT COUNT(*) FROM "transaction_fees" WHERE transaction_fees.tx_hash = transactions.tx_hash AND currency IN ('EUR','BTC')) < 2`,
+		`(block_number, tx_hash) > (100, '0xabc') ORDER BY block_number, tx_hash LIMIT 500`,
+	} {
+		assert.Contains(t, statement, expected)
+	}
+}
@@ -0,0 +1,192 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+	"uniswap-fee-tracker/internal/price"
+)
+
+const (
+	// defaultRepriceBatchSize is the number of transactions loaded and written per batch
+	defaultRepriceBatchSize = 500
+	// maxReportedChanges caps the number of price changes listed in a reprice report
+	maxReportedChanges = 100
+)
+
+// RepriceOptions selects the transactions to reprice and the price source to use
+type RepriceOptions struct {
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *time.Time
+	ToTime    *time.Time
+	Providers []string // Price providers in priority order; empty uses the configured ones
+	DryRun    bool     // Report the changes without writing them
+	BatchSize int
+}
+
+// PriceChange is the old and new pricing of a repriced transaction
+type PriceChange struct {
+	TxHash      string
+	BlockNumber uint64
+	OldETHPrice *big.Float
+	NewETHPrice *big.Float
+	OldFeeUSDT  *big.Float
+	NewFeeUSDT  *big.Float
+	OldSource   string
+	NewSource   string
+}
+
+// RepriceReport summarizes a reprice run
+type RepriceReport struct {
+	DryRun    bool
+	Checked   int
+	Changed   int
+	Unchanged int
+	Failed    int           // Transactions whose price could not be fetched; they keep their stored price
+	Changes   []PriceChange // The first maxReportedChanges changes
+}
+
+// Reprice recomputes the ETH price and USDT fee of every stored transaction in the block and time range,
+// whatever its status, writing the changed ones in batches unless DryRun is set
+func (s *Service) Reprice(ctx context.Context, opts RepriceOptions) (*RepriceReport, error) {
+	if opts.FromBlock == nil && opts.ToBlock == nil && opts.FromTime == nil && opts.ToTime == nil {
+		return nil, fmt.Errorf("%w: a block or time range is required", ErrInvalidFilter)
+	}
+	provider, err := s.selectPriceProvider(opts.Providers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	if opts.BatchSize <= 0 || opts.BatchSize > MaxPageSize {
+		opts.BatchSize = defaultRepriceBatchSize
+	}
+
+	filter := TransactionFilter{
+		FromBlock: opts.FromBlock,
+		ToBlock:   opts.ToBlock,
+		FromTime:  opts.FromTime,
+		ToTime:    opts.ToTime,
+		SortBy:    SortByBlockNumber,
+		Order:     SortAsc,
+		Limit:     opts.BatchSize,
+	}
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+
+	report := &RepriceReport{DryRun: opts.DryRun}
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		page, err := s.repo.ListTransactions(filter)
+		if err != nil {
+			return report, fmt.Errorf("failed to list transactions: %w", err)
+		}
+
+		changed := s.repriceBatch(ctx, provider, page.Transactions, report)
+		if len(changed) > 0 && !opts.DryRun {
+			if err := s.repo.UpdateTransactionPrices(changed); err != nil {
+				return report, fmt.Errorf("failed to update transaction prices: %w", err)
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	log.Printf("Reprice with %s: checked %d, changed %d, unchanged %d, failed %d (dry run: %t)",
+		provider.Name(), report.Checked, report.Changed, report.Unchanged, report.Failed, opts.DryRun)
+	return report, nil
+}
+
+// repriceBatch prices the transactions with one quote per block and returns the ones whose pricing changed
+func (s *Service) repriceBatch(ctx context.Context, provider price.Provider, txs []*Transaction, report *RepriceReport) []*Transaction {
+	byBlock := make(map[uint64][]*Transaction)
+	var blocks []uint64
+	var timestamps []time.Time
+	for _, tx := range txs {
+		if _, ok := byBlock[tx.BlockNumber]; !ok {
+			blocks = append(blocks, tx.BlockNumber)
+			timestamps = append(timestamps, tx.Timestamp)
+		}
+		byBlock[tx.BlockNumber] = append(byBlock[tx.BlockNumber], tx)
+	}
+
+	prefetchPrices(ctx, provider, price.ETHUSDT, timestamps)
+
+	var changed []*Transaction
+	for _, blockNumber := range blocks {
+		group := byBlock[blockNumber]
+		report.Checked += len(group)
+
+		quote, err := provider.GetPrice(ctx, price.Request{
+			Pair:        price.ETHUSDT,
+			Timestamp:   group[0].Timestamp,
+			BlockNumber: blockNumber,
+		})
+		if err != nil {
+			log.Printf("Failed to reprice block %d: %v", blockNumber, err)
+			report.Failed += len(group)
+			continue
+		}
+
+		for _, tx := range group {
+			change := PriceChange{
+				TxHash:      tx.TxHash,
+				BlockNumber: tx.BlockNumber,
+				OldETHPrice: bigFloatValue(tx.ETHPrice),
+				OldFeeUSDT:  bigFloatValue(tx.FeeUSDT),
+				OldSource:   tx.PriceSource,
+			}
+			oldStatus := tx.Status
+
+			tx.ApplyQuote(quote)
+			change.NewETHPrice = tx.ETHPrice.Float
+			change.NewFeeUSDT = tx.FeeUSDT.Float
+			change.NewSource = tx.PriceSource
+
+			if oldStatus == tx.Status && change.OldSource == change.NewSource &&
+				sameAmount(change.OldETHPrice, change.NewETHPrice) && sameAmount(change.OldFeeUSDT, change.NewFeeUSDT) {
+				report.Unchanged++
+				continue
+			}
+			report.Changed++
+			if len(report.Changes) < maxReportedChanges {
+				report.Changes = append(report.Changes, change)
+			}
+			changed = append(changed, tx)
+		}
+	}
+	return changed
+}
+
+// selectPriceProvider returns the named price providers, or the configured ones when no names are given.
+// The on-chain pool provider can be selected whenever the pool price is recorded.
+func (s *Service) selectPriceProvider(names []string) (price.Provider, error) {
+	if len(names) == 0 {
+		return s.priceProvider, nil
+	}
+	return price.Select(names, s.priceProvider, s.poolPrice)
+}
+
+// bigFloatValue returns the value of v, or nil when unset
+func bigFloatValue(v *BigFloat) *big.Float {
+	if v == nil {
+		return nil
+	}
+	return v.Float
+}
+
+// sameAmount reports whether two amounts are equal at the 6 decimals prices and fees are stored with
+func sameAmount(a, b *big.Float) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Text('f', 6) == b.Text('f', 6)
+}
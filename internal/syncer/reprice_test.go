@@ -0,0 +1,111 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+	"uniswap-fee-tracker/internal/price"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// constantProvider returns a fixed ETH price under the given source name
+type constantProvider struct {
+	name  string
+	price float64
+}
+
+func (p constantProvider) Name() string {
+	return p.name
+}
+
+func (p constantProvider) GetPrice(_ context.Context, req price.Request) (*price.Quote, error) {
+	return &price.Quote{Pair: req.Pair, Price: big.NewFloat(p.price), Timestamp: req.Timestamp, Source: p.name}, nil
+}
+
+// newRepriceService stores three transactions priced at 2000 by the fake provider in blocks 10, 10 and 20
+func newRepriceService(t *testing.T) (*Service, *memoryRepository) {
+	repo := newMemoryRepository()
+	for _, tx := range []*Transaction{
+		newUnpricedTransaction("0x01", 10, StatusPendingPrice),
+		newUnpricedTransaction("0x02", 10, StatusPendingPrice),
+		newUnpricedTransaction("0x03", 20, StatusPendingPrice),
+	} {
+		quote, err := fakePriceClient{}.GetPrice(context.Background(), price.Request{Pair: price.ETHUSDT, Timestamp: tx.Timestamp})
+		require.NoError(t, err)
+		tx.ApplyQuote(quote)
+		repo.transactions[tx.TxHash] = tx
+	}
+
+	provider := price.NewChain(fakePriceClient{}, constantProvider{name: price.SourceKraken, price: 2500})
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	return NewService(cfg, nil, provider, newFakeChain(), repo), repo
+}
+
+func TestRepriceDryRun(t *testing.T) {
+	service, repo := newRepriceService(t)
+	from, to := uint64(0), uint64(100)
+
+	report, err := service.Reprice(context.Background(), RepriceOptions{
+		FromBlock: &from,
+		ToBlock:   &to,
+		Providers: []string{price.SourceKraken},
+		DryRun:    true,
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, 3, report.Changed)
+	require.Len(t, report.Changes, 3)
+
+	change := report.Changes[0]
+	assert.Equal(t, "0x01", change.TxHash)
+	assert.Equal(t, "2000.000000", change.OldETHPrice.Text('f', 6))
+	assert.Equal(t, "2500.000000", change.NewETHPrice.Text('f', 6))
+	assert.Equal(t, "0.042000", change.OldFeeUSDT.Text('f', 6))
+	assert.Equal(t, "0.052500", change.NewFeeUSDT.Text('f', 6))
+	assert.Equal(t, "fake", change.OldSource)
+	assert.Equal(t, price.SourceKraken, change.NewSource)
+
+	// Nothing is written
+	assert.Equal(t, 0, repo.priceUpdates)
+	assert.Equal(t, "2000.000000", repo.transactions["0x01"].ETHPrice.Text('f', 6))
+}
+
+func TestRepriceWritesChanges(t *testing.T) {
+	service, repo := newRepriceService(t)
+	fromTime := time.Unix(1700000000+10*12, 0)
+
+	opts := RepriceOptions{FromTime: &fromTime, Providers: []string{price.SourceKraken}}
+	report, err := service.Reprice(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Changed)
+	assert.Equal(t, 3, repo.priceUpdates)
+	for _, tx := range repo.transactions {
+		assert.Equal(t, "2500.000000", tx.ETHPrice.Text('f', 6))
+		assert.Equal(t, "0.052500", tx.FeeUSDT.Text('f', 6))
+		assert.Equal(t, price.SourceKraken, tx.PriceSource)
+	}
+
+	// Repricing again with the same source changes nothing
+	report, err = service.Reprice(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Changed)
+	assert.Equal(t, 3, report.Unchanged)
+	assert.Equal(t, 3, repo.priceUpdates)
+}
+
+func TestRepriceValidatesOptions(t *testing.T) {
+	service, _ := newRepriceService(t)
+	block := uint64(10)
+
+	_, err := service.Reprice(context.Background(), RepriceOptions{})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = service.Reprice(context.Background(), RepriceOptions{FromBlock: &block, Providers: []string{price.SourceCoinbase}})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
@@ -5,29 +5,91 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"uniswap-fee-tracker/internal/binance"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 	"uniswap-fee-tracker/internal/config"
 	"uniswap-fee-tracker/internal/ethereum"
 	"uniswap-fee-tracker/internal/etherscan"
+	"uniswap-fee-tracker/internal/price"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrTransactionNotFound is returned when a transaction is neither stored nor mined
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrNotPoolTransaction is returned when a mined transaction does not interact with any tracked pool
+	ErrNotPoolTransaction = errors.New("transaction does not interact with a tracked pool")
+)
+
 type Service struct {
 	config          *config.Config
 	etherScanClient etherscan.Client
-	binanceClient   binance.Client
+	priceProvider   price.Provider
+	poolPrice       price.Provider    // On-chain pool price recorded alongside priceProvider, nil when disabled
+	pricePool       config.PoolConfig // Pool poolPrice reads the price of
 	repo            Repository
-	nodeClient      *ethereum.Client
+	nodeClient      ethereum.Client
+	pools           []Pool
+	poolsByAddress  map[string]Pool
+	historicalJobs  chan *SyncProgress
+	historicalOnce  sync.Once
+	hub             *Hub // Newly processed live transactions
+	webhookClient   *http.Client
+	throughput      *throughputTracker // Recent progress of live sync and historical chunks
+
+	// Runtime control of sync from the admin API, guarded by controlMu
+	controlMu        sync.Mutex
+	syncCtx          context.Context // Context sync was started with, historical jobs run under it
+	liveCancel       context.CancelFunc
+	liveDone         chan struct{} // Closed once the current live sync run has stopped
+	historicalPaused bool
+	jobCancels       map[uint]context.CancelCauseFunc // Running historical sync jobs by sync progress ID
 }
 
-func NewService(config *config.Config, ethClient etherscan.Client, binClient binance.Client, nodeClient *ethereum.Client, repo Repository) *Service {
+func NewService(config *config.Config, ethClient etherscan.Client, priceProvider price.Provider, nodeClient ethereum.Client, repo Repository) *Service {
+	pools := make([]Pool, 0, len(config.Pools))
+	poolsByAddress := make(map[string]Pool, len(config.Pools))
+	for _, poolConfig := range config.Pools {
+		pool := NewPool(poolConfig)
+		pools = append(pools, pool)
+		poolsByAddress[pool.Address] = pool
+	}
+
+	var poolPrice price.Provider
+	pricePool, hasPricePool := price.FindPricePool(config.Pools)
+	if config.RecordPoolPrice {
+		if hasPricePool {
+			poolPrice, _ = price.NewUniswapProvider(nodeClient, pricePool)
+		} else {
+			log.Printf("No tracked WETH/stablecoin pool, the on-chain ETH price will not be recorded")
+		}
+	}
+
+	webhookTimeout := config.WebhookConfig.Timeout
+	if webhookTimeout <= 0 {
+		webhookTimeout = 10 * time.Second
+	}
+
 	return &Service{
 		config:          config,
 		etherScanClient: ethClient,
-		binanceClient:   binClient,
+		priceProvider:   priceProvider,
+		poolPrice:       poolPrice,
+		pricePool:       pricePool,
 		nodeClient:      nodeClient,
 		repo:            repo,
+		pools:           pools,
+		poolsByAddress:  poolsByAddress,
+		historicalJobs:  make(chan *SyncProgress),
+		hub:             NewHub(),
+		webhookClient:   &http.Client{Timeout: webhookTimeout},
+		throughput:      newThroughputTracker(),
+		syncCtx:         context.Background(),
+		jobCancels:      make(map[uint]context.CancelCauseFunc),
 	}
 }
 
@@ -36,16 +98,178 @@ func (s *Service) GetTransaction(txHash string) (*Transaction, error) {
 	return s.repo.GetTransaction(txHash)
 }
 
-func (s *Service) StartSync(ctx context.Context, indexedStartBlock uint64) error {
-	// Get last tracked block
-	lastTrackedBlock, err := s.repo.GetLastTrackedBlock()
+// Hub returns the hub live sync publishes newly processed transactions to
+func (s *Service) Hub() *Hub {
+	return s.hub
+}
+
+// Pools returns the tracked pools
+func (s *Service) Pools() []Pool {
+	return s.pools
+}
+
+// Pool returns the tracked pool with the given address
+func (s *Service) Pool(address string) (Pool, bool) {
+	pool, ok := s.poolsByAddress[strings.ToLower(address)]
+	return pool, ok
+}
+
+// ResolveTransaction returns a transaction by its hash. Transactions the sync has not stored yet are
+// fetched from the node, priced and persisted, as long as they interact with a tracked pool.
+func (s *Service) ResolveTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	txHash = strings.ToLower(txHash)
+
+	tx, err := s.repo.GetTransaction(txHash)
+	if err == nil {
+		return tx, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	return s.fetchTransaction(ctx, txHash)
+}
+
+// fetchTransaction builds, prices and stores a transaction straight from the node. Like live sync, it
+// publishes the transaction and queues its webhook deliveries, and stores it unpriced when its price is unavailable.
+func (s *Service) fetchTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	receipt, err := s.nodeClient.GetTransactionReceipt(ctx, txHash)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// If no blocks tracked yet, start from configured start block
-			lastTrackedBlock = indexedStartBlock - 1
-		} else {
-			return fmt.Errorf("failed to get last tracked block: %w", err)
+		if errors.Is(err, ethereum.ErrNotFound) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	bodies, err := s.nodeClient.GetTransactions(ctx, []string{txHash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	body := bodies[0]
+
+	pool, ok := s.txPool(body, receipt)
+	if !ok {
+		return nil, ErrNotPoolTransaction
+	}
+
+	blockNum := receipt.BlockNumber.Uint64()
+	header, err := s.nodeClient.GetHeaderByNumber(ctx, blockNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header for block %d: %w", blockNum, err)
+	}
+
+	blockTime := time.Unix(int64(header.Time), 0)
+	tx := s.newTransactionFromReceipt(receipt, pool, blockNum, blockTime)
+	tx.SetBaseFee(header.BaseFee)
+	tx.SetFeeCaps(body)
+	tx.SetOrigin(body)
+
+	quote, err := s.ethPrice(ctx, blockNum, blockTime)
+	if err != nil {
+		// Store the transaction unpriced; the price reconciler retries it
+		log.Printf("Error getting ETH price for transaction %s: %v", tx.TxHash, err)
+		tx.MarkPriceFailed(err)
+	} else {
+		tx.ApplyQuote(quote)
+		s.convertFees(ctx, []*Transaction{tx}, blockNum, blockTime)
+	}
+	s.recordPoolPrice(ctx, []*Transaction{tx}, blockNum, blockTime, false)
+
+	if err := s.repo.SaveTransactions([]*Transaction{tx}); err != nil {
+		return nil, fmt.Errorf("failed to save transaction: %w", err)
+	}
+	s.hub.Publish([]*Transaction{tx})
+	log.Printf("Resolved transaction %s from block %d on demand", tx.TxHash, blockNum)
+	return tx, nil
+}
+
+// ethPrice returns the ETH/USDT price at the given block from the configured price providers
+func (s *Service) ethPrice(ctx context.Context, blockNumber uint64, timestamp time.Time) (*price.Quote, error) {
+	return s.priceProvider.GetPrice(ctx, price.Request{
+		Pair:        price.ETHUSDT,
+		Timestamp:   timestamp,
+		BlockNumber: blockNumber,
+	})
+}
+
+// recordPoolPrice sets the on-chain pool ETH price of transactions mined in the same block. wholeBlock reports
+// whether txs hold every transaction of the block that swapped in the price pool, in which case the price is
+// taken from their last swap instead of the node. Failures are only logged since the pool price is informational.
+func (s *Service) recordPoolPrice(ctx context.Context, txs []*Transaction, blockNumber uint64, timestamp time.Time, wholeBlock bool) {
+	if s.poolPrice == nil || len(txs) == 0 || txs[0].PoolETHPrice != nil {
+		return
+	}
+
+	var poolETHPrice *big.Float
+	if swap := s.lastPricePoolSwap(txs); wholeBlock && swap != nil {
+		poolETHPrice = price.ETHPriceFromSqrtPriceX96(swap.SqrtPriceX96.Int, s.pricePool)
+	} else {
+		quote, err := s.poolPrice.GetPrice(ctx, price.Request{
+			Pair:        price.ETHUSDT,
+			Timestamp:   timestamp,
+			BlockNumber: blockNumber,
+		})
+		if err != nil {
+			log.Printf("Failed to get on-chain ETH price for block %d: %v", blockNumber, err)
+			return
 		}
+		poolETHPrice = quote.Price
+	}
+	for _, tx := range txs {
+		tx.PoolETHPrice = NewBigFloat(new(big.Float).Set(poolETHPrice))
+	}
+}
+
+// lastPricePoolSwap returns the price pool swap with the highest log index among the transactions' swaps
+func (s *Service) lastPricePoolSwap(txs []*Transaction) *Swap {
+	var last *Swap
+	for _, tx := range txs {
+		for i := range tx.Swaps {
+			swap := &tx.Swaps[i]
+			if !strings.EqualFold(swap.PoolAddress, s.pricePool.Address) || swap.SqrtPriceX96 == nil || swap.SqrtPriceX96.Sign() == 0 {
+				continue
+			}
+			if last == nil || swap.LogIndex > last.LogIndex {
+				last = swap
+			}
+		}
+	}
+	return last
+}
+
+// GetTransactions returns the stored transactions for the given hashes, keyed by lowercase hash.
+// Hashes that are not stored are absent from the result.
+func (s *Service) GetTransactions(txHashes []string) (map[string]*Transaction, error) {
+	normalized := make([]string, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		normalized = append(normalized, strings.ToLower(txHash))
+	}
+
+	txs, err := s.repo.GetTransactionsByHashes(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	result := make(map[string]*Transaction, len(txs))
+	for _, tx := range txs {
+		result[strings.ToLower(tx.TxHash)] = tx
+	}
+	return result, nil
+}
+
+// ListTransactions returns a page of stored transactions matching the filter
+func (s *Service) ListTransactions(filter TransactionFilter) (*TransactionPage, error) {
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransactions(filter)
+}
+
+func (s *Service) StartSync(ctx context.Context) error {
+	s.syncCtx = ctx
+
+	// Data synced before pools became configurable belongs to the original WETH-USDC pool
+	if err := s.repo.AssignLegacyPool(strings.ToLower(config.DefaultPools[0].Address)); err != nil {
+		return fmt.Errorf("failed to assign legacy pool: %w", err)
 	}
 
 	// Get latest block from node
@@ -54,27 +278,40 @@ func (s *Service) StartSync(ctx context.Context, indexedStartBlock uint64) error
 		return fmt.Errorf("failed to get latest block: %w", err)
 	}
 
+	// Get last tracked block
+	lastTrackedBlock, err := s.repo.GetLastTrackedBlock()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// If no blocks tracked yet, every pool starts from its configured start block
+			lastTrackedBlock = latestBlock
+		} else {
+			return fmt.Errorf("failed to get last tracked block: %w", err)
+		}
+	}
+
 	// Validate block numbers
 	if lastTrackedBlock > latestBlock {
 		return fmt.Errorf("last tracked block (%d) is greater than latest block (%d)",
 			lastTrackedBlock, latestBlock)
 	}
 
-	// Start historical sync if needed
-	if lastTrackedBlock < latestBlock {
-		log.Printf("Starting historical sync from block %d to %d", lastTrackedBlock, latestBlock)
-		if err := s.StartHistoricalSync(ctx, lastTrackedBlock, latestBlock); err != nil {
-			return fmt.Errorf("failed to start historical sync: %w", err)
-		}
+	// Start historical sync for blocks missed since the last run and for newly tracked pools
+	if err := s.StartHistoricalSync(ctx, lastTrackedBlock, latestBlock); err != nil {
+		return fmt.Errorf("failed to start historical sync: %w", err)
 	}
 
-	// Start live sync from the latest block with a new context
-	go func() {
-		// Create a new background context for live sync
-		liveCtx := context.Background()
-		// Start live sync from latest block
-		log.Printf("Starting live sync from block %d", latestBlock)
-		s.StartLiveSync(liveCtx, latestBlock)
-	}()
+	// Promote transactions to finalized as the chain advances
+	go s.runFinalityPromoter(ctx)
+
+	// Retry transactions whose price could not be fetched
+	go s.runPriceReconciler(ctx)
+
+	// Send webhook notifications of newly stored transactions
+	go s.runWebhookDelivery(ctx)
+
+	// Start live sync from the latest block with a new context, so it can be paused on its own
+	s.controlMu.Lock()
+	s.runLiveSync(&latestBlock)
+	s.controlMu.Unlock()
 	return nil
 }
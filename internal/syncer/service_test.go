@@ -0,0 +1,94 @@
+package syncer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTransactionFromNode(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	router := common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564")
+	pool := common.HexToAddress(testPool.Address)
+
+	// Block 1 holds a pool swap, block 2 a reverted direct pool call and a transaction of another contract
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	swapTx := chain.blocks[1].Transactions()[0]
+	revertedTx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, To: &pool})
+	otherTx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 2, To: &router})
+	header := &types.Header{Number: big.NewInt(2), Time: 1700000024, Difficulty: big.NewInt(0), ParentHash: chain.blocks[1].Hash()}
+	chain.blocks[2] = types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: []*types.Transaction{revertedTx, otherTx}})
+	for i, tx := range []*types.Transaction{revertedTx, otherTx} {
+		chain.receipts[2] = append(chain.receipts[2], &types.Receipt{
+			Type:              types.DynamicFeeTxType,
+			Status:            uint64(i), // The reverted call failed, the other transaction succeeded
+			TxHash:            tx.Hash(),
+			BlockHash:         chain.blocks[2].Hash(),
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+			GasUsed:           50000,
+			EffectiveGasPrice: big.NewInt(1e9),
+		})
+	}
+
+	repo := newMemoryRepository()
+	repo.webhooks = []WebhookSubscription{{ID: 1, URL: "http://example.com/hook", Secret: "secret"}}
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	sub := service.Hub().Subscribe(StreamFilter{}, 10)
+	defer sub.Close()
+
+	// Transactions found on the node are priced, stored, published and notified like synced ones
+	for _, hash := range []common.Hash{swapTx.Hash(), revertedTx.Hash()} {
+		tx, err := service.ResolveTransaction(context.Background(), hash.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, testPool.Address, tx.PoolAddress)
+		assert.Equal(t, StatusProcessed, tx.Status)
+		assert.Equal(t, "fake", tx.PriceSource)
+		assert.Same(t, tx, repo.transactions[hash.Hex()])
+		assert.Same(t, tx, <-sub.Transactions())
+	}
+	assert.Len(t, repo.transactions[swapTx.Hash().Hex()].Swaps, 1)
+	assert.True(t, repo.transactions[revertedTx.Hash().Hex()].Reverted)
+	assert.Len(t, repo.deliveries, 2)
+
+	_, err = service.ResolveTransaction(context.Background(), otherTx.Hash().Hex())
+	assert.ErrorIs(t, err, ErrNotPoolTransaction)
+	_, err = service.ResolveTransaction(context.Background(), common.HexToHash("0x1").Hex())
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+	assert.Len(t, repo.transactions, 2)
+}
+
+func TestResolveTransactionWithoutPrice(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 1, "a")
+	swapTx := chain.blocks[1].Transactions()[0]
+
+	repo := newMemoryRepository()
+	cfg := &config.Config{Pools: []config.PoolConfig{config.DefaultPools[0]}}
+	service := NewService(cfg, nil, &flakyPriceClient{failing: map[int64]bool{1700000012: true}}, chain, repo)
+	sub := service.Hub().Subscribe(StreamFilter{}, 10)
+	defer sub.Close()
+
+	// The transaction is stored and published unpriced for the reconciler to retry
+	tx, err := service.ResolveTransaction(context.Background(), swapTx.Hash().Hex())
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, tx.Status)
+	assert.Equal(t, 1, tx.PriceAttempts)
+	assert.Equal(t, "binance unavailable", tx.LastPriceError)
+	assert.NotNil(t, tx.NextPriceAttemptAt)
+	assert.Nil(t, tx.FeeUSDT)
+	assert.Equal(t, "0.000021", tx.FeeETH.Text('f', 6))
+	assert.Same(t, tx, repo.transactions[swapTx.Hash().Hex()])
+	assert.Same(t, tx, <-sub.Transactions())
+}
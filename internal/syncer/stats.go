@@ -0,0 +1,116 @@
+package syncer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MinStatsInterval is the smallest bucket width of fee statistics
+	MinStatsInterval = time.Minute
+	// MaxStatsBuckets caps the number of buckets a single fee statistics query may return
+	MaxStatsBuckets = 1000
+)
+
+// FeeStatsFilter selects the transactions aggregated into fee statistics and the bucket width
+type FeeStatsFilter struct {
+	Interval    time.Duration
+	FromTime    time.Time
+	ToTime      time.Time
+	PoolAddress string
+}
+
+// FeeStatsBucket aggregates the fees of the transactions mined in [Start, Start+Interval). ETH figures cover
+// every transaction, USDT figures only the priced ones. Statistics are nil for buckets without transactions.
+type FeeStatsBucket struct {
+	Start           time.Time `gorm:"column:bucket_start"`
+	Count           int64     `gorm:"column:count"`
+	PricedCount     int64     `gorm:"column:priced_count"`
+	SumFeeETH       *BigFloat `gorm:"column:sum_fee_eth"`
+	MeanFeeETH      *BigFloat `gorm:"column:mean_fee_eth"`
+	MedianFeeETH    *BigFloat `gorm:"column:median_fee_eth"`
+	P90FeeETH       *BigFloat `gorm:"column:p90_fee_eth"`
+	P95FeeETH       *BigFloat `gorm:"column:p95_fee_eth"`
+	P99FeeETH       *BigFloat `gorm:"column:p99_fee_eth"`
+	SumFeeUSDT      *BigFloat `gorm:"column:sum_fee_usdt"`
+	MeanFeeUSDT     *BigFloat `gorm:"column:mean_fee_usdt"`
+	MedianFeeUSDT   *BigFloat `gorm:"column:median_fee_usdt"`
+	P90FeeUSDT      *BigFloat `gorm:"column:p90_fee_usdt"`
+	P95FeeUSDT      *BigFloat `gorm:"column:p95_fee_usdt"`
+	P99FeeUSDT      *BigFloat `gorm:"column:p99_fee_usdt"`
+	AverageGasPrice *BigFloat `gorm:"column:average_gas_price"` // In Wei
+}
+
+// ParseStatsInterval parses a bucket width such as 15m, 1h or 1d. Days are accepted in addition to the
+// units of time.ParseDuration.
+func ParseStatsInterval(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid interval %q", ErrInvalidFilter, value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid interval %q", ErrInvalidFilter, value)
+	}
+	return interval, nil
+}
+
+// Normalize validates the filter, aligning the time range to the bucket boundaries
+func (f *FeeStatsFilter) Normalize() error {
+	if f.Interval < MinStatsInterval || f.Interval%time.Second != 0 {
+		return fmt.Errorf("%w: interval must be a whole number of seconds of at least %s", ErrInvalidFilter, MinStatsInterval)
+	}
+	if f.FromTime.IsZero() || f.ToTime.IsZero() {
+		return fmt.Errorf("%w: from and to are required", ErrInvalidFilter)
+	}
+	if !f.FromTime.Before(f.ToTime) {
+		return fmt.Errorf("%w: from is not before to", ErrInvalidFilter)
+	}
+	if f.PoolAddress != "" && !addressPattern.MatchString(f.PoolAddress) {
+		return fmt.Errorf("%w: invalid pool address %q", ErrInvalidFilter, f.PoolAddress)
+	}
+	f.PoolAddress = strings.ToLower(f.PoolAddress)
+
+	// Buckets are aligned to the Unix epoch so that the same interval always yields the same boundaries
+	seconds := int64(f.Interval / time.Second)
+	f.FromTime = time.Unix(f.FromTime.Unix()/seconds*seconds, 0).UTC()
+	f.ToTime = f.ToTime.UTC()
+	if buckets := f.bucketCount(); buckets > MaxStatsBuckets {
+		return fmt.Errorf("%w: %d buckets requested, at most %d are allowed", ErrInvalidFilter, buckets, MaxStatsBuckets)
+	}
+	return nil
+}
+
+// bucketCount returns the number of buckets between FromTime and ToTime
+func (f *FeeStatsFilter) bucketCount() int64 {
+	return int64((f.ToTime.Sub(f.FromTime) + f.Interval - 1) / f.Interval)
+}
+
+// FeeStats returns fee statistics of the filter's time range, one bucket per interval. Buckets without
+// transactions are included with a zero count so that charts have no gaps.
+func (s *Service) FeeStats(filter FeeStatsFilter) ([]FeeStatsBucket, error) {
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	stored, err := s.repo.GetFeeStats(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee stats: %w", err)
+	}
+
+	byStart := make(map[int64]FeeStatsBucket, len(stored))
+	for _, bucket := range stored {
+		byStart[bucket.Start.Unix()] = bucket
+	}
+	buckets := make([]FeeStatsBucket, 0, filter.bucketCount())
+	for start := filter.FromTime; start.Before(filter.ToTime); start = start.Add(filter.Interval) {
+		bucket := byStart[start.Unix()]
+		bucket.Start = start
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,89 @@
+package syncer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsRepository returns fixed fee statistics and records the filter it is queried with
+type statsRepository struct {
+	Repository
+	buckets []FeeStatsBucket
+	filter  *FeeStatsFilter
+}
+
+func (r *statsRepository) GetFeeStats(filter FeeStatsFilter) ([]FeeStatsBucket, error) {
+	r.filter = &filter
+	return r.buckets, nil
+}
+
+func TestFeeStats(t *testing.T) {
+	base := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	repo := &statsRepository{buckets: []FeeStatsBucket{
+		{Start: base, Count: 4, PricedCount: 4, MedianFeeUSDT: NewBigFloat(big.NewFloat(5))},
+		{Start: base.Add(2 * time.Hour), Count: 1, SumFeeETH: NewBigFloat(big.NewFloat(0.001))},
+	}}
+
+	service := NewService(&config.Config{}, nil, fakePriceClient{}, nil, repo)
+	buckets, err := service.FeeStats(FeeStatsFilter{
+		Interval:    time.Hour,
+		FromTime:    base.Add(15 * time.Minute), // Aligned down to 10:00
+		ToTime:      base.Add(3 * time.Hour),
+		PoolAddress: "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+	})
+	require.NoError(t, err)
+
+	// The repository is queried with the aligned range
+	assert.Equal(t, base, repo.filter.FromTime)
+	assert.Equal(t, base.Add(3*time.Hour), repo.filter.ToTime)
+	assert.Equal(t, "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", repo.filter.PoolAddress)
+
+	require.Len(t, buckets, 3)
+	assert.Equal(t, base, buckets[0].Start)
+	assert.Equal(t, int64(4), buckets[0].Count)
+	assert.Equal(t, "5.000000", buckets[0].MedianFeeUSDT.Text('f', 6))
+
+	// Empty buckets fill the gaps
+	assert.Equal(t, base.Add(time.Hour), buckets[1].Start)
+	assert.Zero(t, buckets[1].Count)
+	assert.Nil(t, buckets[1].MeanFeeUSDT)
+
+	assert.Equal(t, base.Add(2*time.Hour), buckets[2].Start)
+	assert.Equal(t, int64(1), buckets[2].Count)
+	assert.Equal(t, "0.001000", buckets[2].SumFeeETH.Text('f', 6))
+}
+
+func TestFeeStatsFilter_Normalize(t *testing.T) {
+	from := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter FeeStatsFilter
+	}{
+		{"interval too small", FeeStatsFilter{Interval: time.Second, FromTime: from, ToTime: from.Add(time.Hour)}},
+		{"missing range", FeeStatsFilter{Interval: time.Hour}},
+		{"inverted range", FeeStatsFilter{Interval: time.Hour, FromTime: from, ToTime: from.Add(-time.Hour)}},
+		{"too many buckets", FeeStatsFilter{Interval: time.Minute, FromTime: from, ToTime: from.Add(24 * time.Hour)}},
+		{"invalid pool", FeeStatsFilter{Interval: time.Hour, FromTime: from, ToTime: from.Add(time.Hour), PoolAddress: "0x1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Normalize()
+			assert.True(t, errors.Is(err, ErrInvalidFilter), "expected ErrInvalidFilter, got %v", err)
+		})
+	}
+
+	interval, err := ParseStatsInterval("1d")
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, interval)
+	interval, err = ParseStatsInterval("15m")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, interval)
+	_, err = ParseStatsInterval("daily")
+	assert.True(t, errors.Is(err, ErrInvalidFilter))
+}
@@ -0,0 +1,262 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidBackfill is returned when a backfill request is malformed
+	ErrInvalidBackfill = errors.New("invalid backfill")
+	// ErrSyncJobNotFound is returned when no historical sync job has the given ID
+	ErrSyncJobNotFound = errors.New("sync job not found")
+	// ErrSyncJobFinished is returned when cancelling a historical sync job that has completed or was cancelled
+	ErrSyncJobFinished = errors.New("sync job already finished")
+	// ErrSyncNotStarted is returned when controlling live sync before it was started
+	ErrSyncNotStarted = errors.New("sync not started")
+
+	// errSyncPaused and errSyncCancelled are the causes historical sync jobs are stopped with from the admin API
+	errSyncPaused    = errors.New("paused by admin")
+	errSyncCancelled = errors.New("cancelled by admin")
+)
+
+// SyncPaused reports whether live and historical sync are paused
+func (s *Service) SyncPaused() (live, historical bool) {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	return s.liveDone != nil && s.liveCancel == nil, s.historicalPaused
+}
+
+// runLiveSync starts live sync in the background. The run waits for the previous one to stop and, when no
+// start block is given, resumes after the last tracked block. Must be called with controlMu held.
+func (s *Service) runLiveSync(startBlock *uint64) {
+	previous := s.liveDone
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.liveCancel, s.liveDone = cancel, done
+
+	go func() {
+		defer close(done)
+		if previous != nil {
+			// The previous run finishes the block it is processing before it stops
+			<-previous
+		}
+		if startBlock == nil {
+			lastTracked, err := s.repo.GetLastTrackedBlock()
+			if err != nil {
+				log.Printf("Failed to resume live sync: failed to get last tracked block: %v", err)
+				s.controlMu.Lock()
+				if s.liveDone == done {
+					s.liveCancel = nil
+				}
+				s.controlMu.Unlock()
+				cancel()
+				return
+			}
+			startBlock = &lastTracked
+		}
+		log.Printf("Starting live sync from block %d", *startBlock)
+		s.StartLiveSync(ctx, *startBlock)
+	}()
+}
+
+// PauseLiveSync stops live sync after the block being processed. Pausing a paused live sync does nothing.
+func (s *Service) PauseLiveSync() error {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	if s.liveDone == nil {
+		return ErrSyncNotStarted
+	}
+	if s.liveCancel == nil {
+		return nil
+	}
+	s.liveCancel()
+	s.liveCancel = nil
+	s.throughput.forget(liveSyncJob)
+	log.Printf("Live sync paused")
+	return nil
+}
+
+// ResumeLiveSync restarts a paused live sync after the last tracked block, so no block is skipped.
+// Resuming a running live sync does nothing.
+func (s *Service) ResumeLiveSync() error {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	if s.liveDone == nil {
+		return ErrSyncNotStarted
+	}
+	if s.liveCancel != nil {
+		return nil
+	}
+	log.Printf("Resuming live sync")
+	s.runLiveSync(nil)
+	return nil
+}
+
+// PauseHistoricalSync stops every running historical sync job and keeps queued ones from starting. The jobs
+// are marked PAUSED and continue from their last processed block once resumed.
+func (s *Service) PauseHistoricalSync() {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	if s.historicalPaused {
+		return
+	}
+	s.historicalPaused = true
+	for _, cancel := range s.jobCancels {
+		cancel(errSyncPaused)
+	}
+	log.Printf("Historical sync paused, stopping %d running jobs", len(s.jobCancels))
+}
+
+// ResumeHistoricalSync requeues every incomplete historical sync job after a pause
+func (s *Service) ResumeHistoricalSync() error {
+	s.controlMu.Lock()
+	if !s.historicalPaused {
+		s.controlMu.Unlock()
+		return nil
+	}
+	s.historicalPaused = false
+	s.controlMu.Unlock()
+
+	progresses, err := s.repo.GetIncompleteSyncProgress()
+	if err != nil {
+		return fmt.Errorf("failed to get incomplete sync progress: %w", err)
+	}
+	log.Printf("Resuming historical sync of %d jobs", len(progresses))
+	s.enqueueHistoricalSync(s.syncCtx, progresses)
+	return nil
+}
+
+// EnqueueBackfill schedules a historical sync of the pool's transactions from fromBlock up to and including
+// toBlock. The range may overlap synced blocks; transactions already stored are kept.
+func (s *Service) EnqueueBackfill(ctx context.Context, poolAddress string, fromBlock, toBlock uint64) (*SyncProgress, error) {
+	pool, ok := s.Pool(poolAddress)
+	if !ok {
+		return nil, fmt.Errorf("%w: pool %s is not tracked", ErrInvalidBackfill, strings.ToLower(poolAddress))
+	}
+	if fromBlock == 0 || fromBlock > toBlock {
+		return nil, fmt.Errorf("%w: from_block must be positive and not greater than to_block", ErrInvalidBackfill)
+	}
+	latestBlock, err := s.nodeClient.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if toBlock > latestBlock {
+		return nil, fmt.Errorf("%w: to_block is after the latest block %d", ErrInvalidBackfill, latestBlock)
+	}
+
+	// Sync progress ranges start after StartBlock
+	progress := &SyncProgress{
+		PoolAddress:        pool.Address,
+		StartBlock:         fromBlock - 1,
+		EndBlock:           toBlock,
+		LastProcessedBlock: fromBlock - 1,
+		Status:             SyncStatusRunning,
+	}
+	if err := s.repo.CreateSyncProgress(progress); err != nil {
+		return nil, fmt.Errorf("failed to create sync progress: %w", err)
+	}
+	log.Printf("Enqueued backfill %d of pool %s from block %d to %d", progress.ID, pool.Name, fromBlock, toBlock)
+	s.enqueueHistoricalSync(s.syncCtx, []SyncProgress{*progress})
+	return progress, nil
+}
+
+// CancelSyncJob stops a historical sync job for good: a running job is stopped after its current batch and a
+// queued, paused or failed one will not be started again
+func (s *Service) CancelSyncJob(id uint) error {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+
+	progress, err := s.repo.GetSyncProgress(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSyncJobNotFound
+		}
+		return fmt.Errorf("failed to get sync progress: %w", err)
+	}
+	if progress.Status == SyncStatusCompleted || progress.Status == SyncStatusCancelled {
+		return ErrSyncJobFinished
+	}
+
+	if cancel, ok := s.jobCancels[id]; ok {
+		// The job records its cancellation once it has stopped
+		cancel(errSyncCancelled)
+		return nil
+	}
+	progress.Status = SyncStatusCancelled
+	progress.ErrorMessage = errSyncCancelled.Error()
+	if err := s.repo.UpdateSyncProgress(progress); err != nil {
+		return fmt.Errorf("failed to update sync progress: %w", err)
+	}
+	log.Printf("Cancelled historical sync job %d", id)
+	return nil
+}
+
+// startSyncJob registers the cancel function of a queued historical sync job about to run. It returns the
+// job's current state, or false when the job must not run: it is already running, finished, was cancelled
+// while queued or historical sync is paused.
+func (s *Service) startSyncJob(ctx context.Context, id uint) (*SyncProgress, context.Context, bool) {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+
+	if _, running := s.jobCancels[id]; running {
+		return nil, nil, false
+	}
+	// The queued copy may be stale, e.g. when a job was queued twice by a resume
+	progress, err := s.repo.GetSyncProgress(id)
+	if err != nil {
+		log.Printf("Failed to get sync progress %d: %v", id, err)
+		return nil, nil, false
+	}
+	if progress.Status == SyncStatusCompleted || progress.Status == SyncStatusCancelled {
+		return nil, nil, false
+	}
+	if s.historicalPaused {
+		progress.Status = SyncStatusPaused
+		progress.ErrorMessage = errSyncPaused.Error()
+		if err := s.repo.UpdateSyncProgress(progress); err != nil {
+			log.Printf("Failed to update sync progress: %v", err)
+		}
+		return nil, nil, false
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	s.jobCancels[id] = cancel
+	return progress, ctx, true
+}
+
+// finishSyncJob unregisters a historical sync job and records why it was stopped from the admin API. A job
+// paused and resumed before it stopped is requeued, as the resume dropped its queued copy while it still ran.
+func (s *Service) finishSyncJob(ctx context.Context, progress *SyncProgress) {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	cancel := s.jobCancels[progress.ID]
+	delete(s.jobCancels, progress.ID)
+
+	cause := context.Cause(ctx)
+	if progress.Status != SyncStatusCompleted && errors.Is(cause, errSyncPaused) && !s.historicalPaused {
+		progress.Status = SyncStatusRunning
+		progress.ErrorMessage = ""
+		if err := s.repo.UpdateSyncProgress(progress); err != nil {
+			log.Printf("Failed to update sync progress: %v", err)
+		}
+		log.Printf("Historical sync job %d was resumed while stopping, requeueing it", progress.ID)
+		s.enqueueHistoricalSync(s.syncCtx, []SyncProgress{*progress})
+	} else if progress.Status != SyncStatusCompleted && (errors.Is(cause, errSyncPaused) || errors.Is(cause, errSyncCancelled)) {
+		progress.Status = SyncStatusPaused
+		if errors.Is(cause, errSyncCancelled) {
+			progress.Status = SyncStatusCancelled
+		}
+		progress.ErrorMessage = cause.Error()
+		if err := s.repo.UpdateSyncProgress(progress); err != nil {
+			log.Printf("Failed to update sync progress: %v", err)
+		}
+		log.Printf("Historical sync job %d %s", progress.ID, cause)
+	}
+	cancel(nil)
+}
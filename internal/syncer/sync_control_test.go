@@ -0,0 +1,198 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newControlService returns a service whose historical jobs are left on the queue instead of being run
+func newControlService(chain *fakeChain, repo *memoryRepository) *Service {
+	service := NewService(&config.Config{Pools: config.DefaultPools}, nil, fakePriceClient{}, chain, repo)
+	service.historicalOnce.Do(func() {})
+	return service
+}
+
+// nextJob returns the next queued historical sync job
+func nextJob(t *testing.T, service *Service) *SyncProgress {
+	t.Helper()
+	select {
+	case progress := <-service.historicalJobs:
+		return progress
+	case <-time.After(time.Second):
+		t.Fatal("no job was queued")
+		return nil
+	}
+}
+
+func TestEnqueueBackfill(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 100, "a")
+	repo := newMemoryRepository()
+	service := newControlService(chain, repo)
+	pool := config.DefaultPools[0].Address
+
+	progress, err := service.EnqueueBackfill(context.Background(), pool, 10, 50)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), progress.StartBlock)
+	assert.Equal(t, uint64(50), progress.EndBlock)
+	assert.Equal(t, uint64(9), progress.LastProcessedBlock)
+	assert.Equal(t, SyncStatusRunning, progress.Status)
+	assert.Equal(t, progress.ID, nextJob(t, service).ID)
+
+	for _, tt := range []struct {
+		pool     string
+		from, to uint64
+	}{
+		{"0xcbcdf9626bc03e24f779434178a73a0b4bad62ed", 10, 50},
+		{pool, 0, 50},
+		{pool, 50, 10},
+		{pool, 10, 101},
+	} {
+		_, err := service.EnqueueBackfill(context.Background(), tt.pool, tt.from, tt.to)
+		assert.True(t, errors.Is(err, ErrInvalidBackfill), "expected ErrInvalidBackfill for %+v, got %v", tt, err)
+	}
+}
+
+func TestCancelSyncJob(t *testing.T) {
+	repo := newMemoryRepository()
+	service := newControlService(newFakeChain(), repo)
+	queued := &SyncProgress{StartBlock: 0, EndBlock: 100, Status: SyncStatusRunning}
+	running := &SyncProgress{StartBlock: 100, EndBlock: 200, Status: SyncStatusRunning}
+	completed := &SyncProgress{StartBlock: 200, EndBlock: 300, LastProcessedBlock: 300, Status: SyncStatusCompleted}
+	for _, progress := range []*SyncProgress{queued, running, completed} {
+		require.NoError(t, repo.CreateSyncProgress(progress))
+	}
+
+	// A queued job is cancelled right away and skipped once a worker picks it up
+	require.NoError(t, service.CancelSyncJob(queued.ID))
+	assert.Equal(t, SyncStatusCancelled, repo.progress[queued.ID].Status)
+	_, _, ok := service.startSyncJob(context.Background(), queued.ID)
+	assert.False(t, ok)
+
+	// A running job is stopped and records its cancellation once it returns
+	progress, ctx, ok := service.startSyncJob(context.Background(), running.ID)
+	require.True(t, ok)
+	_, _, ok = service.startSyncJob(context.Background(), running.ID)
+	assert.False(t, ok, "a job runs once at a time")
+	require.NoError(t, service.CancelSyncJob(running.ID))
+	require.Error(t, ctx.Err())
+	service.finishSyncJob(ctx, progress)
+	assert.Equal(t, SyncStatusCancelled, repo.progress[running.ID].Status)
+	assert.Equal(t, "cancelled by admin", repo.progress[running.ID].ErrorMessage)
+	assert.Empty(t, service.jobCancels)
+
+	assert.ErrorIs(t, service.CancelSyncJob(completed.ID), ErrSyncJobFinished)
+	assert.ErrorIs(t, service.CancelSyncJob(running.ID), ErrSyncJobFinished)
+	assert.ErrorIs(t, service.CancelSyncJob(42), ErrSyncJobNotFound)
+
+	incomplete, err := repo.GetIncompleteSyncProgress()
+	require.NoError(t, err)
+	assert.Empty(t, incomplete, "cancelled jobs are not resumed on restart")
+}
+
+func TestPauseHistoricalSync(t *testing.T) {
+	repo := newMemoryRepository()
+	service := newControlService(newFakeChain(), repo)
+	running := &SyncProgress{StartBlock: 0, EndBlock: 100, Status: SyncStatusRunning}
+	queued := &SyncProgress{StartBlock: 100, EndBlock: 200, Status: SyncStatusRunning}
+	require.NoError(t, repo.CreateSyncProgress(running))
+	require.NoError(t, repo.CreateSyncProgress(queued))
+
+	progress, ctx, ok := service.startSyncJob(context.Background(), running.ID)
+	require.True(t, ok)
+	service.PauseHistoricalSync()
+	require.Error(t, ctx.Err())
+	service.finishSyncJob(ctx, progress)
+	assert.Equal(t, SyncStatusPaused, repo.progress[running.ID].Status)
+
+	// Queued jobs do not start while paused
+	_, _, ok = service.startSyncJob(context.Background(), queued.ID)
+	assert.False(t, ok)
+	assert.Equal(t, SyncStatusPaused, repo.progress[queued.ID].Status)
+	_, historical := service.SyncPaused()
+	assert.True(t, historical)
+
+	// Resuming requeues both jobs, which continue from where they stopped
+	require.NoError(t, service.ResumeHistoricalSync())
+	resumed := map[uint]bool{nextJob(t, service).ID: true, nextJob(t, service).ID: true}
+	assert.Equal(t, map[uint]bool{running.ID: true, queued.ID: true}, resumed)
+	progress, _, ok = service.startSyncJob(context.Background(), queued.ID)
+	require.True(t, ok)
+	service.finishSyncJob(context.Background(), progress)
+}
+
+func TestPauseLiveSync(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 10, "a")
+	repo := newMemoryRepository()
+	repo.lastTracked = 10
+	service := newControlService(chain, repo)
+
+	assert.ErrorIs(t, service.PauseLiveSync(), ErrSyncNotStarted)
+	assert.ErrorIs(t, service.ResumeLiveSync(), ErrSyncNotStarted)
+
+	start := uint64(10)
+	service.controlMu.Lock()
+	service.runLiveSync(&start)
+	first := service.liveDone
+	service.controlMu.Unlock()
+
+	require.NoError(t, service.PauseLiveSync())
+	require.NoError(t, service.PauseLiveSync())
+	live, _ := service.SyncPaused()
+	assert.True(t, live)
+	select {
+	case <-first:
+	case <-time.After(5 * time.Second):
+		t.Fatal("live sync did not stop")
+	}
+
+	require.NoError(t, service.ResumeLiveSync())
+	live, _ = service.SyncPaused()
+	assert.False(t, live)
+	require.NoError(t, service.PauseLiveSync())
+	service.controlMu.Lock()
+	done := service.liveDone
+	service.controlMu.Unlock()
+	<-done
+}
+
+func TestResumeHistoricalSyncWhileJobStops(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 20, "a")
+	repo := newMemoryRepository()
+	cfg := &config.Config{
+		Pools:               []config.PoolConfig{config.DefaultPools[0]},
+		HistoricalConfig:    config.HistoricalConfig{Backend: config.HistoricalBackendLogs, LogWindow: 8, MaxWindow: 16},
+		PriceFetchBatchSize: 10,
+	}
+	service := NewService(cfg, nil, fakePriceClient{}, chain, repo)
+	service.historicalOnce.Do(func() {})
+	job := &SyncProgress{PoolAddress: testPool.Address, StartBlock: 4, EndBlock: 18, LastProcessedBlock: 4, Status: SyncStatusRunning}
+	require.NoError(t, repo.CreateSyncProgress(job))
+
+	// The job is paused and resumed before its run reaches the end of its batch, so the copy queued by the
+	// resume is dropped as the job still runs
+	stopping, ctx, ok := service.startSyncJob(context.Background(), job.ID)
+	require.True(t, ok)
+	service.PauseHistoricalSync()
+	require.NoError(t, service.ResumeHistoricalSync())
+	_, _, ok = service.startSyncJob(context.Background(), nextJob(t, service).ID)
+	assert.False(t, ok)
+	service.finishSyncJob(ctx, stopping)
+	assert.Equal(t, SyncStatusRunning, repo.progress[job.ID].Status)
+
+	// Once stopped, the job is requeued and completes
+	progress, ctx, ok := service.startSyncJob(context.Background(), nextJob(t, service).ID)
+	require.True(t, ok)
+	service.runHistoricalSync(ctx, progress)
+	service.finishSyncJob(ctx, progress)
+	assert.Equal(t, SyncStatusCompleted, repo.progress[job.ID].Status)
+	assert.Equal(t, uint64(18), repo.progress[job.ID].LastProcessedBlock)
+}
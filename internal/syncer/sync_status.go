@@ -0,0 +1,236 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"uniswap-fee-tracker/internal/metrics"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// throughputWindow is how far back sync throughput is measured for ETA estimates
+	throughputWindow = 10 * time.Minute
+	// liveSyncJob is the throughput key of live sync; sync progress IDs start at 1
+	liveSyncJob uint = 0
+)
+
+// progressSample is the last processed block of a sync job at a point in time
+type progressSample struct {
+	at    time.Time
+	block uint64
+}
+
+// throughputTracker keeps the recent progress of each sync job to estimate its speed
+type throughputTracker struct {
+	mu      sync.Mutex
+	samples map[uint][]progressSample
+}
+
+func newThroughputTracker() *throughputTracker {
+	return &throughputTracker{samples: make(map[uint][]progressSample)}
+}
+
+// record adds a progress sample of the job, discarding samples that fell out of the window
+func (t *throughputTracker) record(job uint, block uint64, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	samples := append(t.samples[job], progressSample{at: at, block: block})
+	cutoff := at.Add(-throughputWindow)
+	for len(samples) > 2 && samples[0].at.Before(cutoff) {
+		samples = samples[1:]
+	}
+	t.samples[job] = samples
+}
+
+// forget drops the samples of a job that is no longer running
+func (t *throughputTracker) forget(job uint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.samples, job)
+}
+
+// rate returns the blocks per second processed by the job over the window, or false when the job has not
+// made progress recently enough to tell
+func (t *throughputTracker) rate(job uint, now time.Time) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	samples := t.samples[job]
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 || last.block <= first.block || now.Sub(last.at) > throughputWindow {
+		return 0, false
+	}
+	return float64(last.block-first.block) / elapsed, true
+}
+
+// LiveSyncStatus compares the last block processed by live sync with the chain tip
+type LiveSyncStatus struct {
+	TipBlock         uint64
+	TipTime          time.Time
+	LastTrackedBlock uint64
+	LastTrackedTime  *time.Time
+	LagBlocks        uint64
+	LagSeconds       int64   // Time between the last processed block and the tip
+	BlocksPerSecond  float64 // Recent live sync throughput, 0 when unknown
+	ETA              *time.Duration
+	Paused           bool
+	Error            string // Set when the node could not be queried
+}
+
+// HistoricalSyncStatus is a historical sync chunk with its completion and estimated time left
+type HistoricalSyncStatus struct {
+	SyncProgress
+	Percent         float64
+	BlocksPerSecond float64 // Recent throughput of running chunks, 0 when unknown
+	ETA             *time.Duration
+}
+
+// SyncStatusReport describes the state of live and historical sync
+type SyncStatusReport struct {
+	Live       LiveSyncStatus
+	Historical []HistoricalSyncStatus
+	// Time left until every running or queued chunk completes, nil when no chunk made progress recently
+	HistoricalETA    *time.Duration
+	HistoricalPaused bool
+}
+
+// ProcessedBlocks returns the number of blocks of the range already synced
+func (p SyncProgress) ProcessedBlocks() uint64 {
+	if p.Status == SyncStatusCompleted {
+		return p.EndBlock - p.StartBlock
+	}
+	if p.LastProcessedBlock <= p.StartBlock {
+		return 0
+	}
+	return min(p.LastProcessedBlock, p.EndBlock) - p.StartBlock
+}
+
+// Percent returns the share of the range already synced
+func (p SyncProgress) Percent() float64 {
+	if p.EndBlock <= p.StartBlock {
+		return 100
+	}
+	return float64(p.ProcessedBlocks()) / float64(p.EndBlock-p.StartBlock) * 100
+}
+
+// estimate returns the time needed to process the remaining blocks at the given rate
+func estimate(remaining uint64, blocksPerSecond float64) *time.Duration {
+	eta := time.Duration(float64(remaining) / blocksPerSecond * float64(time.Second)).Round(time.Second)
+	return &eta
+}
+
+// SyncStatus reports the progress of every historical sync chunk and the lag of live sync behind the chain
+// tip, with ETAs estimated from the throughput of the last minutes. Node failures are reported in the live
+// status rather than failing the report.
+func (s *Service) SyncStatus(ctx context.Context) (*SyncStatusReport, error) {
+	now := time.Now()
+	progresses, err := s.repo.ListSyncProgress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync progress: %w", err)
+	}
+
+	report := &SyncStatusReport{Historical: make([]HistoricalSyncStatus, 0, len(progresses))}
+	var remaining uint64
+	var rate float64
+	for _, progress := range progresses {
+		status := HistoricalSyncStatus{SyncProgress: progress, Percent: progress.Percent()}
+		if progress.Status == SyncStatusRunning {
+			left := progress.EndBlock - progress.StartBlock - progress.ProcessedBlocks()
+			remaining += left
+			if blocksPerSecond, ok := s.throughput.rate(progress.ID, now); ok {
+				status.BlocksPerSecond = blocksPerSecond
+				status.ETA = estimate(left, blocksPerSecond)
+				rate += blocksPerSecond
+			}
+		}
+		report.Historical = append(report.Historical, status)
+	}
+	if remaining > 0 && rate > 0 {
+		// Queued chunks are picked up as workers free up, so the backlog drains at the combined rate of the
+		// chunks being synced
+		report.HistoricalETA = estimate(remaining, rate)
+	}
+
+	live, err := s.liveSyncStatus(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	report.Live = *live
+	report.Live.Paused, report.HistoricalPaused = s.SyncPaused()
+	return report, nil
+}
+
+// liveSyncStatus compares the block tracker with the chain tip
+func (s *Service) liveSyncStatus(ctx context.Context, now time.Time) (*LiveSyncStatus, error) {
+	status := &LiveSyncStatus{}
+	tracked, err := s.repo.GetLastTrackedBlock()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get last tracked block: %w", err)
+	}
+	status.LastTrackedBlock = tracked
+
+	tip, err := s.nodeClient.GetLatestBlockNumber(ctx)
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to get latest block: %v", err)
+		return status, nil
+	}
+	header, err := s.nodeClient.GetHeaderByNumber(ctx, tip)
+	if err != nil {
+		status.Error = fmt.Sprintf("failed to get header of block %d: %v", tip, err)
+		return status, nil
+	}
+	status.TipBlock = tip
+	status.TipTime = time.Unix(int64(header.Time), 0).UTC()
+	if tracked >= tip {
+		trackedTime := status.TipTime
+		status.LastTrackedTime = &trackedTime
+		return status, nil
+	}
+	status.LagBlocks = tip - tracked
+
+	if tracked > 0 {
+		if block, err := s.repo.GetProcessedBlock(tracked); err == nil {
+			trackedTime := block.Timestamp.UTC()
+			status.LastTrackedTime = &trackedTime
+		} else if header, err := s.nodeClient.GetHeaderByNumber(ctx, tracked); err == nil {
+			trackedTime := time.Unix(int64(header.Time), 0).UTC()
+			status.LastTrackedTime = &trackedTime
+		}
+	}
+	if status.LastTrackedTime != nil {
+		status.LagSeconds = int64(status.TipTime.Sub(*status.LastTrackedTime) / time.Second)
+	}
+	if blocksPerSecond, ok := s.throughput.rate(liveSyncJob, now); ok {
+		status.BlocksPerSecond = blocksPerSecond
+		status.ETA = estimate(status.LagBlocks, blocksPerSecond)
+	}
+	return status, nil
+}
+
+// SyncProgressMetrics returns the historical sync ranges for the metrics endpoint
+func (s *Service) SyncProgressMetrics() ([]metrics.SyncProgress, error) {
+	progresses, err := s.repo.ListSyncProgress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync progress: %w", err)
+	}
+	result := make([]metrics.SyncProgress, 0, len(progresses))
+	for _, progress := range progresses {
+		result = append(result, metrics.SyncProgress{
+			ID:                    progress.ID,
+			PoolAddress:           progress.PoolAddress,
+			Status:                string(progress.Status),
+			EndBlock:              progress.EndBlock,
+			LastProcessedBlock:    progress.LastProcessedBlock,
+			Ratio:                 progress.Percent() / 100,
+			TransactionsProcessed: progress.TransactionsProcessed,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *memoryRepository) GetLastTrackedBlock() (uint64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastTracked, nil
+}
+
+func TestThroughputTracker(t *testing.T) {
+	tracker := newThroughputTracker()
+	start := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+
+	tracker.record(1, 1000, start)
+	_, ok := tracker.rate(1, start)
+	assert.False(t, ok, "a single sample has no rate")
+
+	tracker.record(1, 1600, start.Add(time.Minute))
+	rate, ok := tracker.rate(1, start.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, 10.0, rate)
+
+	// Samples older than the window are dropped, leaving the last minute
+	tracker.record(1, 2200, start.Add(throughputWindow+time.Minute))
+	tracker.record(1, 2800, start.Add(throughputWindow+2*time.Minute))
+	rate, ok = tracker.rate(1, start.Add(throughputWindow+2*time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, 10.0, rate)
+
+	// A stalled job has no rate
+	_, ok = tracker.rate(1, start.Add(3*throughputWindow))
+	assert.False(t, ok)
+
+	tracker.forget(1)
+	_, ok = tracker.rate(1, start.Add(throughputWindow+2*time.Minute))
+	assert.False(t, ok)
+}
+
+func TestSyncStatus(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 110, "a")
+	repo := newMemoryRepository()
+	repo.lastTracked = 100
+	repo.blocks[100] = &ProcessedBlock{Number: 100, Timestamp: time.Unix(int64(1700000000+100*12), 0)}
+	repo.progress[1] = SyncProgress{StartBlock: 0, EndBlock: 1000, LastProcessedBlock: 1000, Status: SyncStatusCompleted, TransactionsProcessed: 42}
+	repo.progress[2] = SyncProgress{StartBlock: 1000, EndBlock: 2000, LastProcessedBlock: 1250, Status: SyncStatusRunning}
+	repo.progress[3] = SyncProgress{StartBlock: 2000, EndBlock: 3000, LastProcessedBlock: 2000, Status: SyncStatusRunning}
+	repo.progress[4] = SyncProgress{StartBlock: 3000, EndBlock: 4000, LastProcessedBlock: 3100, Status: SyncStatusFailed, ErrorMessage: "boom"}
+	for id, progress := range repo.progress {
+		progress.ID = id
+		repo.progress[id] = progress
+	}
+
+	service := NewService(&config.Config{}, nil, nil, chain, repo)
+	now := time.Now()
+	service.throughput.record(2, 1050, now.Add(-40*time.Second))
+	service.throughput.record(2, 1250, now.Add(-20*time.Second)) // 10 blocks per second
+	service.throughput.record(liveSyncJob, 95, now.Add(-10*time.Second))
+	service.throughput.record(liveSyncJob, 100, now) // 0.5 blocks per second
+
+	report, err := service.SyncStatus(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(110), report.Live.TipBlock)
+	assert.Equal(t, uint64(100), report.Live.LastTrackedBlock)
+	assert.Equal(t, uint64(10), report.Live.LagBlocks)
+	assert.Equal(t, int64(120), report.Live.LagSeconds)
+	require.NotNil(t, report.Live.ETA)
+	assert.Equal(t, 20*time.Second, *report.Live.ETA)
+	assert.Empty(t, report.Live.Error)
+
+	byID := make(map[uint]HistoricalSyncStatus)
+	for _, status := range report.Historical {
+		byID[status.ID] = status
+	}
+	require.Len(t, byID, 4)
+	assert.Equal(t, 100.0, byID[1].Percent)
+	assert.Nil(t, byID[1].ETA)
+	assert.Equal(t, 25.0, byID[2].Percent)
+	assert.Equal(t, 10.0, byID[2].BlocksPerSecond)
+	require.NotNil(t, byID[2].ETA)
+	assert.Equal(t, 75*time.Second, *byID[2].ETA)
+	assert.Zero(t, byID[3].Percent)
+	assert.Nil(t, byID[3].ETA, "queued chunks have no throughput")
+	assert.Equal(t, 10.0, byID[4].Percent)
+	assert.Equal(t, "boom", byID[4].ErrorMessage)
+
+	// The 750 + 1000 blocks left of the running chunks drain at the combined rate
+	require.NotNil(t, report.HistoricalETA)
+	assert.Equal(t, 175*time.Second, *report.HistoricalETA)
+}
@@ -1,23 +1,142 @@
 package syncer
 
 import (
+	"fmt"
+	"math/big"
 	"strings"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// Uniswap V3 WETH-USDC pool constants
+// Uniswap V3 pool constants
 const (
-	// WethUsdcPoolAddress is the address of the WETH-USDC pool
-	WethUsdcPoolAddress = "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"
 	// SwapEventTopic is the topic0 for Uniswap V3 swap events
 	SwapEventTopic = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
 )
 
-// IsWethUsdcPool checks if an address is the WETH-USDC pool
-func IsWethUsdcPool(address string) bool {
-	return strings.EqualFold(address, WethUsdcPoolAddress)
+// swapEventDataLength is the size of the non-indexed Swap event fields:
+// amount0, amount1, sqrtPriceX96, liquidity and tick, each ABI-encoded in a 32-byte word
+const swapEventDataLength = 5 * 32
+
+// Token describes an ERC-20 token held by a pool
+type Token struct {
+	Symbol   string
+	Decimals int
+}
+
+// Pool describes a tracked Uniswap V3 pool and its tokens
+type Pool struct {
+	Name       string
+	Address    string // Lowercase hex address
+	Token0     Token
+	Token1     Token
+	FeeTier    uint32
+	StartBlock uint64
+	// InvertPrice quotes execution prices in token0 per token1 instead of token1 per token0
+	InvertPrice bool
+}
+
+// NewPool creates a pool from its configuration
+func NewPool(cfg config.PoolConfig) Pool {
+	return Pool{
+		Name:        cfg.Name,
+		Address:     strings.ToLower(cfg.Address),
+		Token0:      Token{Symbol: cfg.Token0.Symbol, Decimals: cfg.Token0.Decimals},
+		Token1:      Token{Symbol: cfg.Token1.Symbol, Decimals: cfg.Token1.Decimals},
+		FeeTier:     cfg.FeeTier,
+		StartBlock:  cfg.StartBlock,
+		InvertPrice: cfg.InvertPrice,
+	}
+}
+
+// Is checks if an address is this pool
+func (p Pool) Is(address string) bool {
+	return strings.EqualFold(address, p.Address)
 }
 
 // IsSwapEvent checks if a log entry is a Uniswap V3 swap event
 func IsSwapEvent(topic string) bool {
 	return strings.EqualFold(topic, SwapEventTopic)
 }
+
+// DecodeSwapLog decodes a Uniswap V3 Swap(sender, recipient, amount0, amount1, sqrtPriceX96, liquidity, tick) log
+func DecodeSwapLog(log *types.Log) (*Swap, error) {
+	if len(log.Topics) != 3 || !IsSwapEvent(log.Topics[0].Hex()) {
+		return nil, fmt.Errorf("log %d of transaction %s is not a swap event", log.Index, log.TxHash.Hex())
+	}
+	if len(log.Data) != swapEventDataLength {
+		return nil, fmt.Errorf("swap event data has length %d, expected %d", len(log.Data), swapEventDataLength)
+	}
+
+	return &Swap{
+		TxHash:       log.TxHash.Hex(),
+		LogIndex:     log.Index,
+		BlockNumber:  log.BlockNumber,
+		PoolAddress:  strings.ToLower(log.Address.Hex()),
+		Sender:       strings.ToLower(common.BytesToAddress(log.Topics[1].Bytes()).Hex()),
+		Recipient:    strings.ToLower(common.BytesToAddress(log.Topics[2].Bytes()).Hex()),
+		Amount0:      NewBigInt(decodeInt256(log.Data[0:32])),
+		Amount1:      NewBigInt(decodeInt256(log.Data[32:64])),
+		SqrtPriceX96: NewBigInt(new(big.Int).SetBytes(log.Data[64:96])),
+		Liquidity:    NewBigInt(new(big.Int).SetBytes(log.Data[96:128])),
+		Tick:         int32(decodeInt256(log.Data[128:160]).Int64()),
+	}, nil
+}
+
+// decodeInt256 decodes a two's complement ABI-encoded signed integer
+func decodeInt256(word []byte) *big.Int {
+	value := new(big.Int).SetBytes(word)
+	if len(word) > 0 && word[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(len(word)*8)))
+	}
+	return value
+}
+
+// ScaleAmount converts a raw token amount into a decimal amount
+func (t Token) ScaleAmount(amount *big.Int) *big.Float {
+	return new(big.Float).Quo(
+		new(big.Float).SetInt(amount),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil)),
+	)
+}
+
+// ExecutionPrice returns the decimal-adjusted price implied by the swapped amounts,
+// or nil when either amount is zero
+func (p Pool) ExecutionPrice(amount0, amount1 *big.Int) *big.Float {
+	if amount0.Sign() == 0 || amount1.Sign() == 0 {
+		return nil
+	}
+	scaled0 := p.Token0.ScaleAmount(new(big.Int).Abs(amount0))
+	scaled1 := p.Token1.ScaleAmount(new(big.Int).Abs(amount1))
+	if p.InvertPrice {
+		return new(big.Float).Quo(scaled0, scaled1)
+	}
+	return new(big.Float).Quo(scaled1, scaled0)
+}
+
+// PriceUnit describes the unit of the prices returned by ExecutionPrice, e.g. "USDC/WETH"
+func (p Pool) PriceUnit() string {
+	if p.InvertPrice {
+		return p.Token0.Symbol + "/" + p.Token1.Symbol
+	}
+	return p.Token1.Symbol + "/" + p.Token0.Symbol
+}
+
+// decodePoolSwaps decodes the Swap events emitted by the pool among the given logs
+func decodePoolSwaps(pool Pool, logs []*types.Log) []Swap {
+	var swaps []Swap
+	for _, log := range logs {
+		if !pool.Is(log.Address.Hex()) || len(log.Topics) == 0 || !IsSwapEvent(log.Topics[0].Hex()) {
+			continue
+		}
+		swap, err := DecodeSwapLog(log)
+		if err != nil {
+			continue
+		}
+		swap.ExecutionPrice = NewBigFloat(pool.ExecutionPrice(swap.Amount0.Int, swap.Amount1.Int))
+		swaps = append(swaps, *swap)
+	}
+	return swaps
+}
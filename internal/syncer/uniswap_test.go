@@ -0,0 +1,117 @@
+package syncer
+
+import (
+	"math/big"
+	"testing"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPool is the WETH-USDC 0.05% pool; token0 is USDC and token1 is WETH
+var testPool = NewPool(config.DefaultPools[0])
+
+// encodeInt256 ABI-encodes a signed integer into a 32-byte two's complement word
+func encodeInt256(v *big.Int) []byte {
+	word := new(big.Int).Set(v)
+	if word.Sign() < 0 {
+		word.Add(word, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return common.LeftPadBytes(word.Bytes(), 32)
+}
+
+// newSwapLog builds a Swap event log emitted by the given pool
+func newSwapLog(pool string, txHash common.Hash, amount0, amount1 *big.Int, tick int64) *types.Log {
+	var data []byte
+	data = append(data, encodeInt256(amount0)...)
+	data = append(data, encodeInt256(amount1)...)
+	data = append(data, encodeInt256(big.NewInt(1234567890))...)
+	data = append(data, encodeInt256(big.NewInt(987654321))...)
+	data = append(data, encodeInt256(big.NewInt(tick))...)
+
+	return &types.Log{
+		Address: common.HexToAddress(pool),
+		Topics: []common.Hash{
+			common.HexToHash(SwapEventTopic),
+			common.BytesToHash(common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564").Bytes()),
+			common.BytesToHash(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes()),
+		},
+		Data:        data,
+		BlockNumber: 100,
+		TxHash:      txHash,
+		Index:       7,
+	}
+}
+
+func TestDecodeSwapLog(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+	// 1000 USDC paid out of the pool for 0.5 WETH paid in
+	amount0 := big.NewInt(-1000_000000)
+	amount1, _ := new(big.Int).SetString("500000000000000000", 10)
+	log := newSwapLog(testPool.Address, txHash, amount0, amount1, -200000)
+
+	swap, err := DecodeSwapLog(log)
+	assert.NoError(t, err)
+	assert.Equal(t, txHash.Hex(), swap.TxHash)
+	assert.Equal(t, uint(7), swap.LogIndex)
+	assert.Equal(t, uint64(100), swap.BlockNumber)
+	assert.Equal(t, testPool.Address, swap.PoolAddress)
+	assert.Equal(t, "0xe592427a0aece92de3edee1f18e0157c05861564", swap.Sender)
+	assert.Equal(t, "0x1111111111111111111111111111111111111111", swap.Recipient)
+	assert.Equal(t, "-1000000000", swap.Amount0.String())
+	assert.Equal(t, "500000000000000000", swap.Amount1.String())
+	assert.Equal(t, "1234567890", swap.SqrtPriceX96.String())
+	assert.Equal(t, "987654321", swap.Liquidity.String())
+	assert.Equal(t, int32(-200000), swap.Tick)
+
+	price := testPool.ExecutionPrice(swap.Amount0.Int, swap.Amount1.Int)
+	assert.Equal(t, "2000.000000", price.Text('f', 6))
+	assert.Equal(t, "USDC/WETH", testPool.PriceUnit())
+}
+
+func TestDecodeSwapLog_Invalid(t *testing.T) {
+	log := newSwapLog(testPool.Address, common.HexToHash("0xabc"), big.NewInt(1), big.NewInt(-1), 0)
+
+	transfer := *log
+	transfer.Topics = []common.Hash{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")}
+	_, err := DecodeSwapLog(&transfer)
+	assert.Error(t, err)
+
+	truncated := *log
+	truncated.Data = log.Data[:64]
+	_, err = DecodeSwapLog(&truncated)
+	assert.Error(t, err)
+}
+
+func TestDecodePoolSwaps(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+	logs := []*types.Log{
+		newSwapLog(testPool.Address, txHash, big.NewInt(-2000_000000), big.NewInt(1e18), 0),
+		// Swap emitted by another pool in the same transaction
+		newSwapLog("0x11b815efB8f581194ae79006d24E0d814B7697F6", txHash, big.NewInt(1), big.NewInt(-1), 0),
+	}
+	logs[1].Index = 8
+
+	swaps := decodePoolSwaps(testPool, logs)
+	assert.Len(t, swaps, 1)
+	assert.Equal(t, "2000.000000", swaps[0].ExecutionPrice.Text('f', 6))
+
+	// When both pools are tracked, swaps of either pool are decoded
+	usdtPoolConfig := config.PoolConfig{
+		Name:    "WETH-USDT-0.05%",
+		Address: "0x11b815efB8f581194ae79006d24E0d814B7697F6",
+		Token0:  config.TokenConfig{Symbol: "WETH", Decimals: 18},
+		Token1:  config.TokenConfig{Symbol: "USDT", Decimals: 6},
+	}
+	service := NewService(&config.Config{Pools: []config.PoolConfig{config.DefaultPools[0], usdtPoolConfig}}, nil, nil, nil, nil)
+	usdtPool, ok := service.Pool(usdtPoolConfig.Address)
+	assert.True(t, ok)
+
+	swaps = service.decodeSwaps(logs)
+	assert.Len(t, swaps, 2)
+	assert.Equal(t, testPool.Address, swaps[0].PoolAddress)
+	assert.Equal(t, usdtPool.Address, swaps[1].PoolAddress)
+	assert.Equal(t, "USDT/WETH", usdtPool.PriceUnit())
+}
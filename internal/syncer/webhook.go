@@ -0,0 +1,271 @@
+package syncer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription or delivery does not exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookEventTransaction is the event sent when a transaction matching a subscription is stored
+const WebhookEventTransaction = "transaction.created"
+
+// WebhookStatus represents the delivery status of a webhook notification
+type WebhookStatus string
+
+const (
+	WebhookStatusPending   WebhookStatus = "PENDING"
+	WebhookStatusDelivered WebhookStatus = "DELIVERED"
+	WebhookStatusDead      WebhookStatus = "DEAD" // Gave up after the maximum number of attempts
+)
+
+// WebhookSubscription is an endpoint notified of newly stored transactions matching its rules. Rules that are
+// unset are ignored and the others must all match. Only transactions mined after the subscription was created
+// are notified, so that backfills of older blocks do not replay history.
+type WebhookSubscription struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Secret      string    `gorm:"type:varchar(128);not null" json:"-"` // HMAC-SHA256 key of the payload signature
+	MinFeeUSDT  *BigFloat `gorm:"type:numeric(38,6)" json:"min_fee_usdt,omitempty"`
+	MinGasPrice *BigInt   `gorm:"type:numeric(78,0)" json:"min_gas_price,omitempty"` // In Wei
+	Address     string    `gorm:"type:varchar(42)" json:"address,omitempty"`         // Sender or called contract
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is an outbox entry: a notification of one transaction to one subscription. Entries are
+// written in the same database transaction as the transactions they notify and sent by the delivery worker.
+type WebhookDelivery struct {
+	ID             uint                 `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint                 `gorm:"not null;uniqueIndex:idx_webhook_deliveries_sub_tx" json:"subscription_id"`
+	Subscription   *WebhookSubscription `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	TxHash         string               `gorm:"type:varchar(66);not null;uniqueIndex:idx_webhook_deliveries_sub_tx" json:"tx_hash"`
+	BlockNumber    uint64               `gorm:"index" json:"block_number"`
+	Payload        string               `gorm:"type:text;not null" json:"payload"` // JSON WebhookEvent
+	Status         WebhookStatus        `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	LastError      string               `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt  time.Time            `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+// WebhookDeliveryFilter selects the deliveries listed by the admin API, most recent first
+type WebhookDeliveryFilter struct {
+	SubscriptionID uint // 0 for every subscription
+	Status         WebhookStatus
+	Limit          int
+}
+
+// WebhookEvent is the JSON body POSTed to a subscription's URL
+type WebhookEvent struct {
+	Event          string             `json:"event"`
+	SubscriptionID uint               `json:"subscription_id"`
+	Transaction    WebhookTransaction `json:"transaction"`
+}
+
+// WebhookTransaction describes the notified transaction. Amounts are decimal strings and USDT figures are
+// empty for transactions that could not be priced.
+type WebhookTransaction struct {
+	TxHash      string    `json:"tx_hash"`
+	BlockNumber uint64    `json:"block_number"`
+	Timestamp   time.Time `json:"timestamp"`
+	PoolAddress string    `json:"pool_address"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Reverted    bool      `json:"reverted"`
+	GasUsed     string    `json:"gas_used"`
+	GasPrice    string    `json:"gas_price"`
+	FeeETH      string    `json:"fee_eth"`
+	FeeUSDT     string    `json:"fee_usdt"`
+	ETHPrice    string    `json:"eth_price"`
+	PriceSource string    `json:"price_source"`
+}
+
+// Normalize validates the subscription, lowercases its address and generates a secret when none is set
+func (w *WebhookSubscription) Normalize() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: invalid webhook url %q", ErrInvalidFilter, w.URL)
+	}
+	if w.Address != "" && !addressPattern.MatchString(w.Address) {
+		return fmt.Errorf("%w: invalid address %q", ErrInvalidFilter, w.Address)
+	}
+	w.Address = strings.ToLower(w.Address)
+	if w.MinFeeUSDT != nil && w.MinFeeUSDT.Sign() < 0 {
+		return fmt.Errorf("%w: min_fee_usdt is negative", ErrInvalidFilter)
+	}
+	if w.MinGasPrice != nil && w.MinGasPrice.Sign() < 0 {
+		return fmt.Errorf("%w: min_gas_price is negative", ErrInvalidFilter)
+	}
+	if len(w.Secret) > 128 {
+		return fmt.Errorf("%w: secret is longer than 128 characters", ErrInvalidFilter)
+	}
+	if w.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate secret: %w", err)
+		}
+		w.Secret = hex.EncodeToString(secret)
+	}
+	return nil
+}
+
+// Matches reports whether the subscription is notified of the transaction
+func (w *WebhookSubscription) Matches(tx *Transaction) bool {
+	if tx.Timestamp.Before(w.CreatedAt) {
+		return false
+	}
+	if w.MinFeeUSDT != nil && w.MinFeeUSDT.Float != nil &&
+		(tx.FeeUSDT == nil || tx.FeeUSDT.Float == nil || tx.FeeUSDT.Cmp(w.MinFeeUSDT.Float) < 0) {
+		return false
+	}
+	if w.MinGasPrice != nil && w.MinGasPrice.Int != nil &&
+		(tx.GasPrice == nil || tx.GasPrice.Int == nil || tx.GasPrice.Cmp(w.MinGasPrice.Int) < 0) {
+		return false
+	}
+	if w.Address != "" && tx.From != w.Address && tx.To != w.Address {
+		return false
+	}
+	return true
+}
+
+// newWebhookDeliveries returns the pending deliveries of the transactions to the subscriptions they match
+func newWebhookDeliveries(subs []WebhookSubscription, txs []*Transaction) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	now := time.Now()
+	for i := range subs {
+		for _, tx := range txs {
+			if !subs[i].Matches(tx) {
+				continue
+			}
+			payload, err := json.Marshal(WebhookEvent{
+				Event:          WebhookEventTransaction,
+				SubscriptionID: subs[i].ID,
+				Transaction:    newWebhookTransaction(tx),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode webhook payload of %s: %w", tx.TxHash, err)
+			}
+			deliveries = append(deliveries, &WebhookDelivery{
+				SubscriptionID: subs[i].ID,
+				TxHash:         tx.TxHash,
+				BlockNumber:    tx.BlockNumber,
+				Payload:        string(payload),
+				Status:         WebhookStatusPending,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+	return deliveries, nil
+}
+
+// newWebhookTransaction formats a transaction for a webhook payload
+func newWebhookTransaction(tx *Transaction) WebhookTransaction {
+	return WebhookTransaction{
+		TxHash:      tx.TxHash,
+		BlockNumber: tx.BlockNumber,
+		Timestamp:   tx.Timestamp.UTC(),
+		PoolAddress: tx.PoolAddress,
+		From:        tx.From,
+		To:          tx.To,
+		Reverted:    tx.Reverted,
+		GasUsed:     formatInt(tx.GasUsed),
+		GasPrice:    formatInt(tx.GasPrice),
+		FeeETH:      formatDecimal(tx.FeeETH, 18),
+		FeeUSDT:     formatDecimal(tx.FeeUSDT, 6),
+		ETHPrice:    formatDecimal(tx.ETHPrice, 6),
+		PriceSource: tx.PriceSource,
+	}
+}
+
+// formatInt returns v in base 10, or an empty string when unset
+func formatInt(v *BigInt) string {
+	if v == nil || v.Int == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// formatDecimal returns v with the given number of decimals, or an empty string when unset
+func formatDecimal(v *BigFloat, decimals int) string {
+	if v == nil || v.Float == nil {
+		return ""
+	}
+	return v.Text('f', decimals)
+}
+
+// SignWebhookPayload returns the signature sent in the X-Webhook-Signature header: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription secret, prefixed with "sha256=". Receivers should recompute
+// it and reject stale timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhookSubscription validates and stores a subscription. The returned subscription carries its secret.
+func (s *Service) CreateWebhookSubscription(sub *WebhookSubscription) error {
+	if err := sub.Normalize(); err != nil {
+		return err
+	}
+	if err := s.repo.CreateWebhookSubscription(sub); err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookSubscriptions returns every webhook subscription
+func (s *Service) ListWebhookSubscriptions() ([]WebhookSubscription, error) {
+	return s.repo.ListWebhookSubscriptions()
+}
+
+// DeleteWebhookSubscription removes a subscription along with its deliveries
+func (s *Service) DeleteWebhookSubscription(id uint) error {
+	err := s.repo.DeleteWebhookSubscription(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListWebhookDeliveries returns the most recent deliveries matching the filter
+func (s *Service) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	switch filter.Status {
+	case "", WebhookStatusPending, WebhookStatusDelivered, WebhookStatusDead:
+	default:
+		return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidFilter, filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	if filter.Limit > MaxPageSize {
+		return nil, fmt.Errorf("%w: limit must not exceed %d", ErrInvalidFilter, MaxPageSize)
+	}
+	return s.repo.ListWebhookDeliveries(filter)
+}
+
+// RetryWebhookDelivery requeues a dead-lettered delivery of the subscription for immediate delivery with a
+// fresh attempt budget
+func (s *Service) RetryWebhookDelivery(subscriptionID, id uint) error {
+	err := s.repo.RetryWebhookDelivery(subscriptionID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
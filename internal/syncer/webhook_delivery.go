@@ -0,0 +1,151 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookBatchSize is the number of due deliveries loaded per query
+	webhookBatchSize = 100
+	// webhookRetryBaseDelay is the delay before the first retry of a failed delivery
+	webhookRetryBaseDelay = 30 * time.Second
+	// webhookRetryMaxDelay caps the exponential backoff between delivery attempts
+	webhookRetryMaxDelay = time.Hour
+)
+
+// WebhookDeliveryResult summarizes a delivery run
+type WebhookDeliveryResult struct {
+	Delivered int
+	Retried   int
+	Dead      int
+}
+
+// webhookRetryBackoff returns the delay before the next delivery attempt after the given number of failures
+func webhookRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookRetryMaxDelay)
+}
+
+// DeliverWebhooks sends every pending delivery due by now. Failed deliveries are retried with exponential
+// backoff and dead-lettered once they have used up the configured number of attempts.
+func (s *Service) DeliverWebhooks(ctx context.Context, now time.Time) (*WebhookDeliveryResult, error) {
+	maxAttempts := s.config.WebhookConfig.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+
+	result := &WebhookDeliveryResult{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		deliveries, err := s.repo.GetDueWebhookDeliveries(now, webhookBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+		}
+
+		for _, delivery := range deliveries {
+			err := s.sendWebhook(ctx, delivery)
+			delivery.Attempts++
+			switch {
+			case err == nil:
+				delivered := time.Now()
+				delivery.Status = WebhookStatusDelivered
+				delivery.DeliveredAt = &delivered
+				delivery.LastError = ""
+				result.Delivered++
+			case delivery.Attempts >= maxAttempts:
+				log.Printf("Dead-lettering webhook delivery %d of %s after %d attempts: %v",
+					delivery.ID, delivery.TxHash, delivery.Attempts, err)
+				delivery.Status = WebhookStatusDead
+				delivery.LastError = err.Error()
+				result.Dead++
+			default:
+				delivery.LastError = err.Error()
+				delivery.NextAttemptAt = now.Add(webhookRetryBackoff(delivery.Attempts))
+				result.Retried++
+			}
+			if err := s.repo.UpdateWebhookDelivery(delivery); err != nil {
+				return result, fmt.Errorf("failed to update webhook delivery %d: %w", delivery.ID, err)
+			}
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// sendWebhook POSTs a delivery's signed payload to its subscription, failing on non-2xx responses
+func (s *Service) sendWebhook(ctx context.Context, delivery *WebhookDelivery) error {
+	if delivery.Subscription == nil {
+		return fmt.Errorf("subscription %d not loaded", delivery.SubscriptionID)
+	}
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "uniswap-fee-tracker-webhook")
+	req.Header.Set("X-Webhook-Event", WebhookEventTransaction)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(delivery.Subscription.Secret, timestamp, body))
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runWebhookDelivery periodically sends due webhook deliveries until the context is cancelled
+func (s *Service) runWebhookDelivery(ctx context.Context) {
+	interval := s.config.WebhookConfig.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result, err := s.DeliverWebhooks(ctx, time.Now())
+		if err != nil {
+			log.Printf("Error delivering webhooks: %v", err)
+			continue
+		}
+		if result.Delivered+result.Retried+result.Dead > 0 {
+			log.Printf("📬 Delivered webhooks | Delivered: %d | Retrying: %d | Dead: %d",
+				result.Delivered, result.Retried, result.Dead)
+		}
+	}
+}
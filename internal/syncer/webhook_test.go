@@ -0,0 +1,204 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"uniswap-fee-tracker/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *memoryRepository) GetDueWebhookDeliveries(dueBy time.Time, limit int) ([]*WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != WebhookStatusPending || delivery.NextAttemptAt.After(dueBy) {
+			continue
+		}
+		for i := range r.webhooks {
+			if r.webhooks[i].ID == delivery.SubscriptionID {
+				delivery.Subscription = &r.webhooks[i]
+			}
+		}
+		due = append(due, delivery)
+		if len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryRepository) UpdateWebhookDelivery(*WebhookDelivery) error {
+	// Deliveries are updated in place
+	return nil
+}
+
+func TestWebhookSubscription_Matches(t *testing.T) {
+	const sender = "0x5f6ab5e2ab6a2f4d3c6a1b2b9b5c1e7c7f3e9a01"
+	created := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	tx := &Transaction{
+		Timestamp: created.Add(time.Minute),
+		From:      sender,
+		To:        "0xe592427a0aece92de3edee1f18e0157c05861564",
+		GasPrice:  NewBigInt(big.NewInt(30e9)),
+		FeeUSDT:   NewBigFloat(big.NewFloat(12.5)),
+	}
+	unpriced := &Transaction{Timestamp: tx.Timestamp, GasPrice: NewBigInt(big.NewInt(30e9))}
+
+	tests := []struct {
+		name string
+		sub  WebhookSubscription
+		tx   *Transaction
+		want bool
+	}{
+		{"no rules", WebhookSubscription{CreatedAt: created}, tx, true},
+		{"mined before subscribing", WebhookSubscription{CreatedAt: created.Add(time.Hour)}, tx, false},
+		{"fee above minimum", WebhookSubscription{CreatedAt: created, MinFeeUSDT: NewBigFloat(big.NewFloat(10))}, tx, true},
+		{"fee below minimum", WebhookSubscription{CreatedAt: created, MinFeeUSDT: NewBigFloat(big.NewFloat(20))}, tx, false},
+		{"unpriced with minimum fee", WebhookSubscription{CreatedAt: created, MinFeeUSDT: NewBigFloat(big.NewFloat(0))}, unpriced, false},
+		{"gas price above minimum", WebhookSubscription{CreatedAt: created, MinGasPrice: NewBigInt(big.NewInt(20e9))}, tx, true},
+		{"gas price below minimum", WebhookSubscription{CreatedAt: created, MinGasPrice: NewBigInt(big.NewInt(40e9))}, tx, false},
+		{"sender", WebhookSubscription{CreatedAt: created, Address: sender}, tx, true},
+		{"called contract", WebhookSubscription{CreatedAt: created, Address: tx.To}, tx, true},
+		{"other address", WebhookSubscription{CreatedAt: created, Address: "0xcbcdf9626bc03e24f779434178a73a0b4bad62ed"}, tx, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sub.Matches(tt.tx))
+		})
+	}
+
+	for _, invalid := range []WebhookSubscription{
+		{URL: "ftp://example.com/hook"},
+		{URL: "https://example.com/hook", Address: "0x1"},
+		{URL: "https://example.com/hook", MinFeeUSDT: NewBigFloat(big.NewFloat(-1))},
+	} {
+		err := invalid.Normalize()
+		assert.True(t, errors.Is(err, ErrInvalidFilter), "expected ErrInvalidFilter, got %v", err)
+	}
+	sub := WebhookSubscription{URL: "https://example.com/hook", Address: "0x5F6AB5E2AB6A2F4D3C6A1B2B9B5C1E7C7F3E9A01"}
+	require.NoError(t, sub.Normalize())
+	assert.Equal(t, sender, sub.Address)
+	assert.Len(t, sub.Secret, 64)
+}
+
+// webhookReceiver records correctly signed webhook events after answering the first failures of them with 503
+type webhookReceiver struct {
+	mu       sync.Mutex
+	secret   string
+	failures int
+	events   []WebhookEvent
+	invalid  int // Requests whose signature did not verify
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get("X-Webhook-Timestamp"), 10, 64)
+	if req.Header.Get("X-Webhook-Signature") != SignWebhookPayload(r.secret, timestamp, body) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var event WebhookEvent
+	_ = json.Unmarshal(body, &event)
+	r.events = append(r.events, event)
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	receiver := &webhookReceiver{secret: "s3cret", failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	repo := newMemoryRepository()
+	created := time.Date(2024, 2, 13, 10, 0, 0, 0, time.UTC)
+	repo.webhooks = []WebhookSubscription{
+		{ID: 1, URL: server.URL, Secret: receiver.secret, MinFeeUSDT: NewBigFloat(big.NewFloat(10)), CreatedAt: created},
+		{ID: 2, URL: server.URL, Secret: "wrong", CreatedAt: created},
+	}
+	tx := &Transaction{
+		TxHash:      "0x01",
+		BlockNumber: 100,
+		Timestamp:   created.Add(time.Minute),
+		GasUsed:     NewBigInt(big.NewInt(100000)),
+		GasPrice:    NewBigInt(big.NewInt(50e9)),
+	}
+	tx.UpdatePrices(big.NewFloat(2000)) // 10 USDT
+	cheap := &Transaction{
+		TxHash:    "0x02",
+		Timestamp: tx.Timestamp,
+		GasUsed:   NewBigInt(big.NewInt(21000)),
+		GasPrice:  NewBigInt(big.NewInt(1e9)),
+	}
+	cheap.UpdatePrices(big.NewFloat(2000))
+	require.NoError(t, repo.SaveTransactions([]*Transaction{tx, cheap}))
+	// Subscription 1 only matches the expensive transaction, subscription 2 matches both
+	require.Len(t, repo.deliveries, 3)
+
+	service := NewService(&config.Config{WebhookConfig: config.WebhookConfig{MaxAttempts: 3}}, nil, nil, nil, repo)
+	now := time.Now()
+
+	// The receiver is unavailable: the delivery backs off
+	result, err := service.DeliverWebhooks(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, WebhookDeliveryResult{Retried: 3}, *result)
+	delivery := repo.deliveries[0]
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, now.Add(webhookRetryBaseDelay), delivery.NextAttemptAt)
+	assert.Equal(t, "unexpected status 503", delivery.LastError)
+
+	// Nothing is due before the backoff has elapsed
+	result, err = service.DeliverWebhooks(context.Background(), now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Zero(t, *result)
+
+	now = now.Add(webhookRetryBaseDelay)
+	result, err = service.DeliverWebhooks(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, WebhookDeliveryResult{Retried: 3}, *result)
+
+	// The third attempt reaches the receiver; deliveries signed with the wrong secret are dead-lettered
+	now = now.Add(webhookRetryBackoff(2))
+	result, err = service.DeliverWebhooks(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Delivered)
+	assert.Equal(t, 2, result.Dead)
+	assert.Equal(t, WebhookStatusDelivered, delivery.Status)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Equal(t, WebhookStatusDead, repo.deliveries[1].Status)
+	assert.Equal(t, "unexpected status 401", repo.deliveries[1].LastError)
+
+	assert.Equal(t, 6, receiver.invalid)
+	require.Len(t, receiver.events, 1)
+	event := receiver.events[0]
+	assert.Equal(t, WebhookEventTransaction, event.Event)
+	assert.Equal(t, uint(1), event.SubscriptionID)
+	assert.Equal(t, "0x01", event.Transaction.TxHash)
+	assert.Equal(t, "10.000000", event.Transaction.FeeUSDT)
+	assert.Equal(t, "50000000000", event.Transaction.GasPrice)
+}
+
+func TestWebhookRetryBackoff(t *testing.T) {
+	assert.Equal(t, time.Duration(0), webhookRetryBackoff(0))
+	assert.Equal(t, 30*time.Second, webhookRetryBackoff(1))
+	assert.Equal(t, time.Minute, webhookRetryBackoff(2))
+	assert.Equal(t, 4*time.Minute, webhookRetryBackoff(4))
+	assert.Equal(t, time.Hour, webhookRetryBackoff(20))
+}